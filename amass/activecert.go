@@ -8,7 +8,6 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
-	"fmt"
 	"net"
 	"strconv"
 	"time"
@@ -21,8 +20,20 @@ import (
 const (
 	defaultTLSConnectTimeout = 3 * time.Second
 	defaultHandshakeDeadline = 5 * time.Second
+
+	// The smallest IPv4 prefix length of in-scope netblocks that will have certificates pulled
+	minNetblockCertPrefix = 16
+
+	// The maximum number of discovered names sent via SNI to each address
+	maxSNINamesPerAddr = 5
 )
 
+// certTarget is an address that certificates will be pulled from using the server name.
+type certTarget struct {
+	Addr       string
+	ServerName string
+}
+
 // ActiveCertService is the AmassService that handles all active certificate activities
 // within the architecture.
 type ActiveCertService struct {
@@ -31,16 +42,20 @@ type ActiveCertService struct {
 	bus       evbus.Bus
 	maxPulls  *utils.Semaphore
 	filter    *utils.StringFilter
-	addrQueue []string
+	queue     []*certTarget
+	sniCounts map[string]int
+	// The addresses of the in-scope netblocks, which are used when the queue is empty
+	netblocks *utils.IPIterator
 }
 
 // NewActiveCertService requires the enumeration configuration and event bus as parameters.
 // The object returned is initialized, but has not yet been started.
 func NewActiveCertService(config *core.AmassConfig, bus evbus.Bus) *ActiveCertService {
 	acs := &ActiveCertService{
-		bus:      bus,
-		maxPulls: utils.NewSemaphore(25),
-		filter:   utils.NewStringFilter(),
+		bus:       bus,
+		maxPulls:  utils.NewSemaphore(25),
+		filter:    utils.NewStringFilter(),
+		sniCounts: make(map[string]int),
 	}
 
 	acs.BaseAmassService = *core.NewBaseAmassService("Active Certificate Service", config, acs)
@@ -53,6 +68,7 @@ func (acs *ActiveCertService) OnStart() error {
 
 	if acs.Config().Active {
 		acs.bus.SubscribeAsync(core.ACTIVECERT, acs.queueAddress, false)
+		go acs.queueScopeNetblocks()
 	}
 	go acs.processRequests()
 	return nil
@@ -68,30 +84,72 @@ func (acs *ActiveCertService) OnStop() error {
 	return nil
 }
 
-func (acs *ActiveCertService) queueAddress(addr string) {
+// queueAddress adds the address to the queue, along with the name that resolved to
+// the address. Certificates will be requested for a limited number of names per address.
+func (acs *ActiveCertService) queueAddress(addr, name string) {
 	acs.Lock()
 	defer acs.Unlock()
 
-	if acs.filter.Duplicate(addr) {
+	if !acs.filter.Duplicate(addr) {
+		acs.queue = append(acs.queue, &certTarget{Addr: addr})
+	}
+	if name == "" || acs.sniCounts[addr] >= maxSNINamesPerAddr || acs.filter.Duplicate(addr+","+name) {
 		return
 	}
-	acs.addrQueue = append(acs.addrQueue, addr)
+	acs.sniCounts[addr]++
+	acs.queue = append(acs.queue, &certTarget{Addr: addr, ServerName: name})
 }
 
-func (acs *ActiveCertService) nextAddress() string {
+// queueScopeNetblocks provides the addresses within the in-scope netblocks to nextTarget,
+// which obtains them one at a time instead of adding every address to the queue.
+func (acs *ActiveCertService) queueScopeNetblocks() {
+	var ranges []utils.IPRange
+	for _, cidr := range acs.Config().ScopeCIDRs() {
+		if ones, bits := cidr.Mask.Size(); bits != 32 || ones < minNetblockCertPrefix {
+			acs.Config().Log.Printf("Certificates will not be pulled from all addresses in %s", cidr)
+			continue
+		}
+		ranges = append(ranges, utils.CIDRHosts(cidr))
+	}
+	if len(ranges) == 0 {
+		return
+	}
+
 	acs.Lock()
 	defer acs.Unlock()
+	acs.netblocks = utils.NewIPIterator(utils.MergeIPRanges(ranges)...)
+}
 
-	if len(acs.addrQueue) == 0 {
-		return ""
+// nextScopeAddress returns the next in-scope netblock address that has not been queued.
+func (acs *ActiveCertService) nextScopeAddress() *certTarget {
+	if acs.netblocks == nil {
+		return nil
 	}
 
-	next := acs.addrQueue[0]
+	for ip := acs.netblocks.Next(); ip != nil; ip = acs.netblocks.Next() {
+		if addr := ip.String(); acs.Config().IsAddressInScope(addr) && !acs.filter.Duplicate(addr) {
+			return &certTarget{Addr: addr}
+		}
+	}
+	acs.netblocks = nil
+	return nil
+}
+
+func (acs *ActiveCertService) nextTarget() *certTarget {
+	acs.Lock()
+	defer acs.Unlock()
+
+	// The addresses discovered during the enumeration are pulled before the netblock addresses
+	if len(acs.queue) == 0 {
+		return acs.nextScopeAddress()
+	}
+
+	next := acs.queue[0]
 	// Remove the first slice element
-	if len(acs.addrQueue) > 1 {
-		acs.addrQueue = acs.addrQueue[1:]
+	if len(acs.queue) > 1 {
+		acs.queue = acs.queue[1:]
 	} else {
-		acs.addrQueue = []string{}
+		acs.queue = []*certTarget{}
 	}
 	return next
 }
@@ -104,99 +162,116 @@ func (acs *ActiveCertService) processRequests() {
 		case <-acs.Quit():
 			return
 		default:
-			if addr := acs.nextAddress(); addr != "" {
-				go acs.performRequest(addr)
+			// A target is only obtained once a pull can start, so the netblock
+			// addresses are not all taken from the iterator at once
+			if !acs.maxPulls.TryAcquire(1) {
+				time.Sleep(10 * time.Millisecond)
+				continue
+			}
+			if t := acs.nextTarget(); t != nil {
+				go acs.performRequest(t)
 			} else {
+				acs.maxPulls.Release(1)
 				time.Sleep(100 * time.Millisecond)
 			}
 		}
 	}
 }
 
-func (acs *ActiveCertService) performRequest(addr string) {
-	acs.maxPulls.Acquire(1)
+// performRequest pulls the certificates from the target, and releases the
+// maxPulls semaphore acquired by processRequests once the pull has completed.
+func (acs *ActiveCertService) performRequest(t *certTarget) {
 	defer acs.maxPulls.Release(1)
 
 	acs.SetActive()
-	for _, r := range PullCertificateNames(addr, acs.Config().Ports) {
-		if acs.Config().IsDomainInScope(r.Name) {
-			acs.Config().MaxFlow.Acquire(1)
-			acs.bus.Publish(core.NEWNAME, r)
+	for _, c := range PullCertificates(t.Addr, t.ServerName, acs.Config().Ports) {
+		acs.bus.Publish(core.NEWCERT, c)
+
+		for _, r := range reqFromNames(utils.CertificateNames(c.Certificate)) {
+			if acs.Config().IsDomainInScope(r.Name) {
+				acs.Config().MaxFlow.Acquire(1)
+				acs.bus.Publish(core.NEWNAME, r)
+			}
 		}
 	}
 }
 
 // PullCertificateNames attempts to pull a cert from one or more ports on an IP.
 func PullCertificateNames(addr string, ports []int) []*core.AmassRequest {
+	return PullCertificateNamesSNI(addr, "", ports)
+}
+
+// PullCertificateNamesSNI attempts to pull a cert from one or more ports on an IP while
+// requesting the certificate for the server name provided. STARTTLS is negotiated on
+// the well-known ports of SMTP, IMAP, POP3, FTP, XMPP, LDAP and PostgreSQL.
+func PullCertificateNamesSNI(addr, serverName string, ports []int) []*core.AmassRequest {
 	var requests []*core.AmassRequest
 
 	// Check hosts for certificates that contain subdomain names
+	for _, c := range PullCertificates(addr, serverName, ports) {
+		// Create the new requests from names found within the cert
+		requests = append(requests, reqFromNames(utils.CertificateNames(c.Certificate))...)
+	}
+	return requests
+}
+
+// PullCertificates returns the certificates presented on one or more ports of an IP
+// when requesting the certificate for the server name provided.
+func PullCertificates(addr, serverName string, ports []int) []*core.CertificateData {
+	var certs []*core.CertificateData
+
 	for _, port := range ports {
-		cfg := &tls.Config{InsecureSkipVerify: true}
-		// Set the maximum time allowed for making the connection
-		ctx, cancel := context.WithTimeout(context.Background(), defaultTLSConnectTimeout)
-		defer cancel()
-		// Obtain the connection
-		d := net.Dialer{}
-		conn, err := d.DialContext(ctx, "tcp", addr+":"+strconv.Itoa(port))
+		cert, err := pullCertificate(addr, serverName, port, starttlsPorts[port])
 		if err != nil {
 			continue
 		}
-		defer conn.Close()
-
-		c := tls.Client(conn, cfg)
-		// Attempt to acquire the certificate chain
-		errChan := make(chan error, 2)
-		// This goroutine will break us out of the handshake
-		time.AfterFunc(defaultHandshakeDeadline, func() {
-			errChan <- errors.New("Handshake timeout")
+
+		certs = append(certs, &core.CertificateData{
+			Address:     addr,
+			Port:        port,
+			ServerName:  serverName,
+			Certificate: cert,
 		})
-		// Be sure we do not wait too long in this attempt
-		c.SetDeadline(time.Now().Add(defaultHandshakeDeadline))
-		// The handshake is performed in the goroutine
-		go func() {
-			errChan <- c.Handshake()
-		}()
-		// The error channel returns handshake or timeout error
-		if err = <-errChan; err != nil {
-			continue
-		}
-		// Get the correct certificate in the chain
-		certChain := c.ConnectionState().PeerCertificates
-		cert := certChain[0]
-		// Create the new requests from names found within the cert
-		requests = append(requests, reqFromNames(namesFromCert(cert))...)
 	}
-	return requests
+	return certs
 }
 
-func namesFromCert(cert *x509.Certificate) []string {
-	var cn string
+// pullCertificate returns the leaf certificate presented on the port, after performing
+// the STARTTLS negotiation when a function has been provided.
+func pullCertificate(addr, serverName string, port int, starttls starttlsFunc) (*x509.Certificate, error) {
+	// Set the maximum time allowed for making the connection
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTLSConnectTimeout)
+	defer cancel()
+	// Obtain the connection
+	d := net.Dialer{}
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(addr, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	// Be sure we do not wait too long in this attempt
+	conn.SetDeadline(time.Now().Add(defaultHandshakeDeadline))
 
-	for _, name := range cert.Subject.Names {
-		oid := name.Type
-		if len(oid) == 4 && oid[0] == 2 && oid[1] == 5 && oid[2] == 4 {
-			if oid[3] == 3 {
-				cn = fmt.Sprintf("%s", name.Value)
-				break
-			}
+	if starttls != nil {
+		if err := starttls(conn, serverName); err != nil {
+			return nil, err
 		}
 	}
 
-	var subdomains []string
-	// Add the subject common name to the list of subdomain names
-	commonName := utils.RemoveAsteriskLabel(cn)
-	if commonName != "" {
-		subdomains = append(subdomains, commonName)
+	c := tls.Client(conn, &tls.Config{
+		ServerName:         serverName,
+		InsecureSkipVerify: true,
+	})
+	// Attempt to acquire the certificate chain
+	if err := c.Handshake(); err != nil {
+		return nil, err
 	}
-	// Add the cert DNS names to the list of subdomain names
-	for _, name := range cert.DNSNames {
-		n := utils.RemoveAsteriskLabel(name)
-		if n != "" {
-			subdomains = utils.UniqueAppend(subdomains, n)
-		}
+	// Get the correct certificate in the chain
+	certChain := c.ConnectionState().PeerCertificates
+	if len(certChain) == 0 {
+		return nil, errors.New("No certificates were presented")
 	}
-	return subdomains
+	return certChain[0], nil
 }
 
 func reqFromNames(subdomains []string) []*core.AmassRequest {
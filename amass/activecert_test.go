@@ -0,0 +1,64 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"bytes"
+	"log"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/amass/core"
+	"github.com/OWASP/Amass/amass/utils"
+	evbus "github.com/asaskevich/EventBus"
+)
+
+func newTestActiveCertService(scope string) *ActiveCertService {
+	_, cidr, _ := net.ParseCIDR(scope)
+	config := &core.AmassConfig{Log: log.New(new(bytes.Buffer), "", 0)}
+	config.SetScope([]*net.IPNet{cidr}, nil)
+
+	acs := NewActiveCertService(config, evbus.New())
+	acs.queueScopeNetblocks()
+	return acs
+}
+
+func TestActiveCertNextTarget(t *testing.T) {
+	acs := newTestActiveCertService("192.0.2.0/30")
+	acs.queueAddress("198.51.100.1", "www.example.com")
+	acs.queueAddress("192.0.2.1", "")
+
+	// The discovered addresses are pulled before the netblock addresses that were not queued
+	expected := []certTarget{
+		{Addr: "198.51.100.1"},
+		{Addr: "198.51.100.1", ServerName: "www.example.com"},
+		{Addr: "192.0.2.1"},
+		{Addr: "192.0.2.2"},
+	}
+	for _, e := range expected {
+		if got := acs.nextTarget(); got == nil || *got != e {
+			t.Errorf("nextTarget returned %v instead of %v", got, e)
+		}
+	}
+	if got := acs.nextTarget(); got != nil {
+		t.Errorf("nextTarget returned %v after the netblock addresses were exhausted", got)
+	}
+}
+
+func TestActiveCertPullLimit(t *testing.T) {
+	acs := newTestActiveCertService("192.0.2.0/24")
+	// All the pulls are in progress
+	acs.maxPulls = utils.NewSemaphore(1)
+	acs.maxPulls.Acquire(1)
+
+	go acs.processRequests()
+	time.Sleep(50 * time.Millisecond)
+	acs.Stop()
+
+	// No netblock addresses were taken while waiting for a pull to complete
+	if got := acs.nextTarget(); got == nil || got.Addr != "192.0.2.1" {
+		t.Errorf("nextTarget returned %v instead of the first netblock address", got)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+var defaultAltWords = []string{
+	"admin",
+	"api",
+	"app",
+	"apps",
+	"auth",
+	"backend",
+	"beta",
+	"blog",
+	"cdn",
+	"cloud",
+	"corp",
+	"dashboard",
+	"data",
+	"db",
+	"demo",
+	"dev",
+	"develop",
+	"development",
+	"dmz",
+	"docker",
+	"docs",
+	"ext",
+	"external",
+	"gateway",
+	"git",
+	"gw",
+	"help",
+	"int",
+	"internal",
+	"intranet",
+	"jenkins",
+	"k8s",
+	"lab",
+	"legacy",
+	"login",
+	"m",
+	"mail",
+	"mgmt",
+	"mobile",
+	"monitor",
+	"new",
+	"node",
+	"old",
+	"origin",
+	"portal",
+	"preprod",
+	"priv",
+	"private",
+	"prod",
+	"production",
+	"proxy",
+	"qa",
+	"qas",
+	"remote",
+	"sandbox",
+	"secure",
+	"server",
+	"service",
+	"sso",
+	"stage",
+	"staging",
+	"stg",
+	"static",
+	"support",
+	"sys",
+	"test",
+	"testing",
+	"tmp",
+	"uat",
+	"v1",
+	"v2",
+	"vpn",
+	"web",
+	"ws",
+	"www",
+}
@@ -6,25 +6,71 @@ package amass
 import (
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/OWASP/Amass/amass/core"
+	"github.com/OWASP/Amass/amass/utils"
 	evbus "github.com/asaskevich/EventBus"
 	"github.com/miekg/dns"
 )
 
+const (
+	// The maximum number of labels learned from discovered names
+	maxLearnedLabels = 100
+
+	// The maximum number of word-based altered names waiting to be sent
+	maxAltQueueSize = 100000
+
+	// The number of names required within a domain before a name model is learned
+	minNamesForModel = 10
+
+	// The maximum number of parent names that learned labels are attached to
+	maxModelParents = 3
+
+	// How often the name models are learned from the names within the graph
+	nameModelInterval = 30 * time.Second
+)
+
 // AlterationService is the AmassService that handles all DNS name permutation within
 // the architecture. This is achieved by receiving all the RESOLVED events.
 type AlterationService struct {
 	core.BaseAmassService
 
 	bus evbus.Bus
+
+	// Ensures word-based altered names are only sent once
+	filter *utils.StringFilter
+
+	// The words used to generate altered names
+	words []string
+
+	// Labels learned from the names discovered during the enumeration
+	labels []string
+
+	// Word-based altered names waiting to be released at the alteration rate
+	altQueue []*core.AmassRequest
+	limiter  *utils.RateLimiter
+
+	// The number of names used the last time each domain name model was learned
+	modelSizes map[string]int
+
+	progress *progressCounter
 }
 
 // NewAlterationService requires the enumeration configuration and event bus as parameters.
 // The object returned is initialized, but has not yet been started.
 func NewAlterationService(config *core.AmassConfig, bus evbus.Bus) *AlterationService {
-	as := &AlterationService{bus: bus}
+	as := &AlterationService{
+		bus:        bus,
+		filter:     utils.NewStringFilter(),
+		words:      config.AltWordlist,
+		modelSizes: make(map[string]int),
+		progress:   newProgressCounter(),
+	}
+	if len(as.words) == 0 {
+		as.words = defaultAltWords
+	}
 
 	as.BaseAmassService = *core.NewBaseAmassService("Alteration Service", config, as)
 	return as
@@ -35,8 +81,16 @@ func (as *AlterationService) OnStart() error {
 	as.BaseAmassService.OnStart()
 
 	if as.Config().Alterations {
+		rate := as.Config().AltRate
+		if rate == 0 {
+			rate = core.TimingToAlterationsPerSecond(as.Config().Timing)
+		}
+		as.limiter = utils.NewRateLimiter(rate)
+
 		as.bus.SubscribeAsync(core.CHECKED, as.SendRequest, false)
 		go as.processRequests()
+		go as.processAltQueue()
+		go as.processNameModels()
 	}
 	return nil
 }
@@ -47,6 +101,7 @@ func (as *AlterationService) OnStop() error {
 
 	if as.Config().Alterations {
 		as.bus.Unsubscribe(core.CHECKED, as.SendRequest)
+		as.limiter.Stop()
 	}
 	return nil
 }
@@ -72,6 +127,8 @@ func (as *AlterationService) executeAlterations(req *core.AmassRequest) {
 	}
 	as.flipNumbersInName(req)
 	as.appendNumbers(req)
+	as.learnLabels(req)
+	as.wordAlterations(req)
 }
 
 func (as *AlterationService) correctRecordTypes(req *core.AmassRequest) bool {
@@ -153,5 +210,218 @@ func (as *AlterationService) sendAlteredName(name, domain string) {
 			Tag:    core.ALT,
 			Source: "Alterations",
 		})
+		as.nameSent()
+	}
+}
+
+func (as *AlterationService) nameSent() {
+	as.progress.AddTotal(1)
+	as.progress.Inc()
+}
+
+// Progress returns the progress of the alterations, including the altered names waiting to be sent.
+func (as *AlterationService) Progress() PhaseProgress {
+	as.Lock()
+	queued := len(as.altQueue)
+	as.Unlock()
+
+	return as.progress.Snapshot(queued, uint64(queued))
+}
+
+// learnLabels collects the labels of discovered names for use as alteration words.
+func (as *AlterationService) learnLabels(req *core.AmassRequest) {
+	num := len(strings.Split(req.Name, ".")) - len(strings.Split(req.Domain, "."))
+	if num <= 0 {
+		return
+	}
+
+	as.Lock()
+	defer as.Unlock()
+
+	for _, label := range strings.Split(req.Name, ".")[:num] {
+		for _, word := range strings.Split(label, "-") {
+			if len(as.labels) >= maxLearnedLabels {
+				return
+			}
+			if len(word) < 2 || strings.IndexFunc(word, unicode.IsLetter) < 0 {
+				continue
+			}
+			if !stringInSlice(word, as.words) {
+				as.labels = utils.UniqueAppend(as.labels, word)
+			}
+		}
+	}
+}
+
+func (as *AlterationService) altWords() []string {
+	as.Lock()
+	defer as.Unlock()
+
+	words := make([]string, len(as.words), len(as.words)+len(as.labels))
+	copy(words, as.words)
+	return append(words, as.labels...)
+}
+
+// wordAlterations inserts, prepends, appends and swaps words within the subdomain name.
+func (as *AlterationService) wordAlterations(req *core.AmassRequest) {
+	if req.Name == req.Domain {
+		return
+	}
+
+	parts := strings.SplitN(req.Name, ".", 2)
+	label, rest := parts[0], parts[1]
+	for _, word := range as.altWords() {
+		if word == label {
+			continue
+		}
+
+		for _, l := range permuteLabel(label, word) {
+			as.queueAlteredName(l+"."+rest, req.Domain, "Alterations")
+		}
+		// Add the word as a new label above and below the first label
+		as.queueAlteredName(word+"."+req.Name, req.Domain, "Alterations")
+		as.queueAlteredName(label+"."+word+"."+rest, req.Domain, "Alterations")
+	}
+}
+
+// permuteLabel returns the labels created by joining the word with the label
+// and by inserting and swapping the word within a hyphenated label.
+func permuteLabel(label, word string) []string {
+	results := []string{
+		word + label,
+		word + "-" + label,
+		label + word,
+		label + "-" + word,
+	}
+
+	pieces := strings.Split(label, "-")
+	for i := range pieces {
+		// Insert the word between the hyphenated pieces
+		if i > 0 {
+			inserted := append(append(append([]string{}, pieces[:i]...), word), pieces[i:]...)
+			results = append(results, strings.Join(inserted, "-"))
+		}
+		// Swap the word with the hyphenated piece
+		if len(pieces) > 1 && pieces[i] != word {
+			swapped := append([]string{}, pieces...)
+			swapped[i] = word
+			results = append(results, strings.Join(swapped, "-"))
+		}
+	}
+	return results
+}
+
+// queueAlteredName checks that the provided name is valid and queues it for release at the alteration rate.
+func (as *AlterationService) queueAlteredName(name, domain, source string) {
+	re := as.Config().DomainRegex(domain)
+	if re == nil || !re.MatchString(name) || as.filter.Duplicate(name) {
+		return
+	}
+
+	as.Lock()
+	defer as.Unlock()
+
+	if len(as.altQueue) >= maxAltQueueSize {
+		return
+	}
+	as.altQueue = append(as.altQueue, &core.AmassRequest{
+		Name:   name,
+		Domain: domain,
+		Tag:    core.ALT,
+		Source: source,
+	})
+}
+
+func (as *AlterationService) nextAlteredName() *core.AmassRequest {
+	as.Lock()
+	defer as.Unlock()
+
+	if len(as.altQueue) == 0 {
+		return nil
+	}
+
+	next := as.altQueue[0]
+	// Remove the first slice element
+	if len(as.altQueue) > 1 {
+		as.altQueue = as.altQueue[1:]
+	} else {
+		as.altQueue = []*core.AmassRequest{}
+	}
+	return next
+}
+
+func (as *AlterationService) processAltQueue() {
+	for {
+		select {
+		case <-as.PauseChan():
+			<-as.ResumeChan()
+		case <-as.Quit():
+			return
+		default:
+			if !as.limiter.Take() {
+				return
+			}
+			if req := as.nextAlteredName(); req != nil {
+				as.SetActive()
+				as.Config().MaxFlow.Acquire(1)
+				as.bus.Publish(core.NEWNAME, req)
+				as.nameSent()
+			}
+		}
+	}
+}
+
+func (as *AlterationService) processNameModels() {
+	t := time.NewTicker(nameModelInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-as.PauseChan():
+			<-as.ResumeChan()
+		case <-as.Quit():
+			return
+		case <-t.C:
+			for _, domain := range as.Config().Domains() {
+				as.learnNameModel(domain)
+			}
+		}
+	}
+}
+
+// learnNameModel trains a name model using the names within the domain and queues
+// the most likely candidates generated. The number of candidates is budgeted by MaxFlow.
+func (as *AlterationService) learnNameModel(domain string) {
+	names := as.Config().Graph().SubdomainNames(domain)
+	if len(names) < minNamesForModel || len(names) == as.modelSizes[domain] {
+		return
+	}
+	as.modelSizes[domain] = len(names)
+
+	as.SetActive()
+	model := newNameModel()
+	for _, name := range names {
+		model.Train(name, domain)
+	}
+
+	parents := model.Parents(maxModelParents)
+	if len(parents) == 0 {
+		return
+	}
+
+	budget := core.TimingToMaxFlow(as.Config().Timing)
+	for _, c := range model.Generate(budget / len(parents)) {
+		for _, parent := range parents {
+			as.queueAlteredName(c.Label+"."+parent, domain, "Name Model")
+		}
+	}
+}
+
+func stringInSlice(s string, list []string) bool {
+	for _, item := range list {
+		if s == item {
+			return true
+		}
 	}
+	return false
 }
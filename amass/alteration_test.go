@@ -0,0 +1,30 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"testing"
+)
+
+func TestPermuteLabel(t *testing.T) {
+	expected := []string{
+		"devapi-prod",
+		"dev-api-prod",
+		"api-proddev",
+		"api-prod-dev",
+		"api-dev-prod",
+		"dev-prod",
+		"api-dev",
+	}
+
+	results := permuteLabel("api-prod", "dev")
+	if len(results) != len(expected) {
+		t.Fatalf("permuteLabel returned %d labels instead of %d: %v", len(results), len(expected), results)
+	}
+	for _, e := range expected {
+		if !stringInSlice(e, results) {
+			t.Errorf("permuteLabel did not return %s", e)
+		}
+	}
+}
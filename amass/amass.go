@@ -4,16 +4,17 @@
 package amass
 
 import (
-	"bufio"
 	"errors"
-	"io"
+	"fmt"
 	"io/ioutil"
 	"log"
-	"strings"
+	"net"
+	"sync"
 	"time"
 
 	"github.com/OWASP/Amass/amass/core"
 	"github.com/OWASP/Amass/amass/dnssrv"
+	"github.com/OWASP/Amass/amass/sources"
 	"github.com/OWASP/Amass/amass/utils"
 	evbus "github.com/asaskevich/EventBus"
 )
@@ -42,8 +43,6 @@ const (
 
 	// Author is used to display the founder of the amass package.
 	Author = "caffix (@jeff_foley)"
-
-	defaultWordlistURL = "https://raw.githubusercontent.com/OWASP/Amass/master/wordlists/namelist.txt"
 )
 
 // Enumeration is the object type used to execute a DNS enumeration with Amass.
@@ -59,6 +58,12 @@ type Enumeration struct {
 	// Pause/Resume channels for halting the enumeration
 	pause  chan struct{}
 	resume chan struct{}
+
+	// The services that report the progress of the enumeration
+	lock       sync.Mutex
+	subdomains *SubdomainService
+	alts       *AlterationService
+	brute      *BruteForceService
 }
 
 // NewEnumeration returns an initialized Enumeration that has not been started yet.
@@ -94,16 +99,105 @@ func (e *Enumeration) checkConfig() error {
 	if e.Config.Passive && e.Config.DataOptsWriter != nil {
 		return errors.New("Data operations cannot be saved without DNS resolution")
 	}
+	all := sources.GetAllSources(nil)
+	for _, name := range append(e.Config.IncludeSources, e.Config.ExcludeSources...) {
+		if !sources.ValidSourceName(all, name) {
+			return fmt.Errorf("%s is not a valid data source name or type", name)
+		}
+	}
+	if e.Config.SweepSize < 0 {
+		return errors.New("The reverse DNS sweep size cannot be negative")
+	}
+	if e.Config.AltRate < 0 {
+		return errors.New("The alteration rate cannot be negative")
+	}
+	if e.Config.MaxRecursionDepth < 0 {
+		return errors.New("The maximum recursion depth cannot be negative")
+	}
+	if e.Config.SweepRate < 0 {
+		return errors.New("The reverse DNS sweep rate cannot be negative")
+	}
+	if _, err := e.Config.BlacklistRules(); err != nil {
+		return err
+	}
+	for _, asn := range append(e.Config.ASNs, e.Config.ExcludedASNs...) {
+		if asn <= 0 {
+			return fmt.Errorf("%d is not a valid autonomous system number", asn)
+		}
+	}
+	for _, path := range e.Config.ASNDatabases {
+		if err := LoadASNDatabase(path); err != nil {
+			return err
+		}
+	}
+	for _, spec := range e.Config.CloudRanges {
+		if err := LoadCloudRanges(spec); err != nil {
+			return err
+		}
+	}
+	if e.Config.GeoDatabase != "" {
+		if err := LoadGeoDatabase(e.Config.GeoDatabase); err != nil {
+			return err
+		}
+	}
+	if e.Config.InfraCacheTTL < 0 {
+		return errors.New("The infrastructure cache TTL cannot be negative")
+	}
+	if e.Config.InfraCachePath != "" {
+		if err := OpenInfrastructureCache(e.Config.InfraCachePath, e.Config.InfraCacheTTL); err != nil {
+			return err
+		}
+	}
+	// Expand the autonomous systems into the netblocks they announce
+	included, err := asnsToNetblocks(e.Config.ASNs)
+	if err != nil {
+		return err
+	}
+	included = append(append([]*net.IPNet{}, e.Config.CIDRs...), included...)
+	if (len(e.Config.CIDRs) > 0 || len(e.Config.ASNs) > 0) && len(included) == 0 {
+		return errors.New("No netblocks were resolved for the address scope")
+	}
+	excluded, err := asnsToNetblocks(e.Config.ExcludedASNs)
+	if err != nil {
+		return err
+	}
+	e.Config.SetScope(included, append(append([]*net.IPNet{}, e.Config.ExcludedCIDRs...), excluded...))
 	if len(e.Config.Ports) == 0 {
 		e.Config.Ports = []int{443}
 	}
-	if e.Config.BruteForcing && len(e.Config.Wordlist) == 0 {
-		e.Config.Wordlist, _ = getDefaultWordlist()
+	if _, err := BruteForceGenerators(e.Config); err != nil {
+		return err
+	}
+	if _, err := RecursiveGenerators(e.Config); err != nil {
+		return err
 	}
 	e.Config.MaxFlow = utils.NewSemaphore(core.TimingToMaxFlow(e.Config.Timing))
 	return nil
 }
 
+func asnsToNetblocks(asns []int) ([]*net.IPNet, error) {
+	var cidrs []*net.IPNet
+
+	for _, asn := range asns {
+		record, err := ASNRequest(asn)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to obtain the netblocks for AS%d: %v", asn, err)
+		}
+
+		var found bool
+		for _, nb := range record.Netblocks {
+			if _, ipnet, err := net.ParseCIDR(nb); err == nil {
+				cidrs = append(cidrs, ipnet)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("No netblocks were found for AS%d", asn)
+		}
+	}
+	return cidrs, nil
+}
+
 // Start begins the DNS enumeration process for the Amass Enumeration object.
 func (e *Enumeration) Start() error {
 	if err := e.checkConfig(); err != nil {
@@ -113,19 +207,25 @@ func (e *Enumeration) Start() error {
 	bus := evbus.New()
 	bus.SubscribeAsync(core.OUTPUT, e.sendOutput, true)
 	// Select the correct services to be used in this enumeration
+	e.lock.Lock()
+	e.subdomains = NewSubdomainService(e.Config, bus)
 	services := []core.AmassService{
-		NewSubdomainService(e.Config, bus),
+		e.subdomains,
 		NewSourcesService(e.Config, bus),
 	}
 	if !e.Config.Passive {
+		e.alts = NewAlterationService(e.Config, bus)
+		e.brute = NewBruteForceService(e.Config, bus)
 		services = append(services,
 			NewDataManagerService(e.Config, bus),
 			dnssrv.NewDNSService(e.Config, bus),
-			NewAlterationService(e.Config, bus),
-			NewBruteForceService(e.Config, bus),
+			e.alts,
+			e.brute,
 			NewActiveCertService(e.Config, bus),
+			NewHTTPProbeService(e.Config, bus),
 		)
 	}
+	e.lock.Unlock()
 
 	for _, srv := range services {
 		if err := srv.Start(); err != nil {
@@ -167,6 +267,9 @@ loop:
 	}
 	time.Sleep(time.Second)
 	bus.Unsubscribe(core.OUTPUT, e.sendOutput)
+	if err := SaveInfrastructureCache(); err != nil {
+		e.Config.Log.Print(err)
+	}
 	if completed {
 		close(e.Done)
 	}
@@ -175,6 +278,26 @@ loop:
 	return nil
 }
 
+// Progress returns a snapshot of the work performed by the enumeration.
+// The zero value is returned before the enumeration has been started.
+func (e *Enumeration) Progress() Progress {
+	var p Progress
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.subdomains != nil {
+		p.Resolved, p.ResolvedRate = e.subdomains.Resolved()
+	}
+	if e.alts != nil {
+		p.Alterations = e.alts.Progress()
+	}
+	if e.brute != nil {
+		p.BruteForce = e.brute.Progress()
+	}
+	return p
+}
+
 // Pause temporarily halts the DNS enumeration.
 func (e *Enumeration) Pause() {
 	e.pause <- struct{}{}
@@ -194,25 +317,3 @@ func (e *Enumeration) sendOutput(out *core.AmassOutput) {
 		e.Output <- out
 	}
 }
-
-func getDefaultWordlist() ([]string, error) {
-	var list []string
-	var wordlist io.Reader
-
-	page, err := utils.RequestWebPage(defaultWordlistURL, nil, nil, "", "")
-	if err != nil {
-		return list, err
-	}
-	wordlist = strings.NewReader(page)
-
-	scanner := bufio.NewScanner(wordlist)
-	// Once we have used all the words, we are finished
-	for scanner.Scan() {
-		// Get the next word in the list
-		word := scanner.Text()
-		if err := scanner.Err(); err == nil && word != "" {
-			list = utils.UniqueAppend(list, word)
-		}
-	}
-	return list, nil
-}
@@ -0,0 +1,46 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestCheckConfigScope(t *testing.T) {
+	defer func() { netCache = newInfraCache(DefaultInfraCacheTTL) }()
+
+	netCache = newInfraCache(time.Hour)
+	netCache.insert(&ASRecord{ASN: 64500, Netblocks: []string{"192.0.2.0/24"}})
+	netCache.insert(&ASRecord{ASN: 64501, Netblocks: []string{"not-a-netblock"}})
+
+	_, cidr, _ := net.ParseCIDR("198.51.100.0/24")
+	e := NewEnumeration()
+	e.Config.CIDRs = []*net.IPNet{cidr}
+	e.Config.ASNs = []int{64500}
+
+	// Starting the enumeration again does not change the provided configuration
+	for i := 0; i < 2; i++ {
+		if err := e.checkConfig(); err != nil {
+			t.Fatalf("checkConfig failed: %v", err)
+		}
+		if len(e.Config.CIDRs) != 1 || len(e.Config.ScopeCIDRs()) != 2 {
+			t.Errorf("The scope has the unexpected netblocks %v", e.Config.ScopeCIDRs())
+		}
+	}
+	if !e.Config.IsAddressInScope("192.0.2.1") || e.Config.IsAddressInScope("203.0.113.1") {
+		t.Errorf("The addresses were not checked against the netblocks of the ASN")
+	}
+
+	// The scope is not opened up to all addresses when the ASN netblocks are not resolved
+	e = NewEnumeration()
+	e.Config.ASNs = []int{64501}
+	if err := e.checkConfig(); err == nil {
+		t.Errorf("checkConfig did not return an error when no netblocks were resolved")
+	}
+	if e.Config.IsAddressInScope("203.0.113.1") {
+		t.Errorf("An address was in scope without any netblocks resolved")
+	}
+}
@@ -0,0 +1,583 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OWASP/Amass/amass/utils"
+)
+
+// ipRange is a range of addresses announced or assigned to an autonomous system.
+type ipRange struct {
+	First net.IP
+	Last  net.IP
+	ASN   int
+}
+
+func (r *ipRange) contains(ip net.IP) bool {
+	return bytes.Compare(r.First, ip) <= 0 && bytes.Compare(ip, r.Last) <= 0
+}
+
+// size returns a value that orders ranges by the number of addresses they contain.
+func (r *ipRange) size() *big.Int {
+	return new(big.Int).Sub(new(big.Int).SetBytes(r.Last), new(big.Int).SetBytes(r.First))
+}
+
+// rangeIndex is an interval tree stored within a slice of ranges sorted by the first
+// address. Each subtree is centered on the middle element of the slice and tracks the
+// largest last address of the ranges within the subtree, so overlapping ranges are supported.
+type rangeIndex struct {
+	ranges  []*ipRange
+	maxLast []net.IP
+}
+
+func newRangeIndex(ranges []*ipRange) *rangeIndex {
+	sort.Slice(ranges, func(i, j int) bool {
+		return bytes.Compare(ranges[i].First, ranges[j].First) < 0
+	})
+
+	ri := &rangeIndex{
+		ranges:  ranges,
+		maxLast: make([]net.IP, len(ranges)),
+	}
+	ri.build(0, len(ranges))
+	return ri
+}
+
+func (ri *rangeIndex) build(lo, hi int) net.IP {
+	if lo >= hi {
+		return nil
+	}
+
+	mid := (lo + hi) / 2
+	max := ri.ranges[mid].Last
+	for _, last := range []net.IP{ri.build(lo, mid), ri.build(mid+1, hi)} {
+		if last != nil && bytes.Compare(last, max) > 0 {
+			max = last
+		}
+	}
+	ri.maxLast[mid] = max
+	return max
+}
+
+// Search returns the smallest range containing the address.
+func (ri *rangeIndex) Search(ip net.IP) *ipRange {
+	var best *ipRange
+
+	ri.search(0, len(ri.ranges), ip.To16(), func(r *ipRange) {
+		if best == nil || r.size().Cmp(best.size()) <= 0 {
+			best = r
+		}
+	})
+	return best
+}
+
+func (ri *rangeIndex) search(lo, hi int, ip net.IP, fn func(*ipRange)) {
+	if lo >= hi {
+		return
+	}
+
+	mid := (lo + hi) / 2
+	// None of the ranges within the subtree extend to the address
+	if bytes.Compare(ri.maxLast[mid], ip) < 0 {
+		return
+	}
+
+	ri.search(lo, mid, ip, fn)
+	// The ranges following the middle element all start after the address
+	if bytes.Compare(ri.ranges[mid].First, ip) > 0 {
+		return
+	}
+	if ri.ranges[mid].contains(ip) {
+		fn(ri.ranges[mid])
+	}
+	ri.search(mid+1, hi, ip, fn)
+}
+
+// asnDatabase holds the IP-to-ASN data loaded from local files.
+type asnDatabase struct {
+	sync.Mutex
+	index   *rangeIndex
+	ranges  []*ipRange
+	records map[int]*ASRecord
+	byASN   map[int][]*ipRange
+	files   loadedFiles
+}
+
+var localASNData = newASNDatabase()
+
+func newASNDatabase() *asnDatabase {
+	return &asnDatabase{
+		records: make(map[int]*ASRecord),
+		byASN:   make(map[int][]*ipRange),
+	}
+}
+
+// loadedFiles records the database files that have been loaded, so the files provided
+// again by later enumerations within the same process do not duplicate the entries.
+type loadedFiles struct {
+	sync.Mutex
+	keys map[string]struct{}
+}
+
+// load executes the function unless the file identified by the key has already been loaded.
+func (lf *loadedFiles) load(key string, fn func() error) error {
+	lf.Lock()
+	defer lf.Unlock()
+
+	if _, found := lf.keys[key]; found {
+		return nil
+	}
+	if err := fn(); err != nil {
+		return err
+	}
+
+	if lf.keys == nil {
+		lf.keys = make(map[string]struct{})
+	}
+	lf.keys[key] = struct{}{}
+	return nil
+}
+
+// databaseFileKey identifies the database file regardless of the relative path used to provide it.
+func databaseFileKey(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+func (db *asnDatabase) addRange(first, last net.IP, asn int) {
+	r := &ipRange{
+		First: first.To16(),
+		Last:  last.To16(),
+		ASN:   asn,
+	}
+	if r.First == nil || r.Last == nil || bytes.Compare(r.First, r.Last) > 0 {
+		return
+	}
+
+	db.ranges = append(db.ranges, r)
+	db.byASN[asn] = append(db.byASN[asn], r)
+	// The index is rebuilt during the next search
+	db.index = nil
+}
+
+// addRecord stores the autonomous system details, while keeping the details that were already known.
+func (db *asnDatabase) addRecord(asn int, cc, registry, desc string, date time.Time) {
+	record, found := db.records[asn]
+	if !found {
+		record = &ASRecord{ASN: asn}
+		db.records[asn] = record
+	}
+
+	if record.CC == "" {
+		record.CC = cc
+	}
+	if record.Registry == "" {
+		record.Registry = registry
+	}
+	if record.Description == "" {
+		record.Description = desc
+	}
+	if record.AllocationDate.IsZero() {
+		record.AllocationDate = date
+	}
+}
+
+// search returns the ASN and the range of addresses that contain the address.
+func (db *asnDatabase) search(ip net.IP) *ipRange {
+	db.Lock()
+	defer db.Unlock()
+
+	if len(db.ranges) == 0 || ip == nil {
+		return nil
+	}
+	if db.index == nil {
+		db.index = newRangeIndex(db.ranges)
+	}
+	return db.index.Search(ip)
+}
+
+// lookupIP returns the ASN, netblock and description for the address.
+func (db *asnDatabase) lookupIP(addr string) (int, *net.IPNet, string) {
+	ip := net.ParseIP(addr)
+
+	r := db.search(ip)
+	if r == nil {
+		return 0, nil, ""
+	}
+	// The netblock is the largest CIDR within the range that contains the address
+	for _, cidr := range rangeToCIDRs(r.First, r.Last) {
+		if cidr.Contains(ip) {
+			return r.ASN, cidr, db.description(r.ASN)
+		}
+	}
+	return 0, nil, ""
+}
+
+// lookupCIDR returns the ASN and description for the range that contains the entire netblock.
+func (db *asnDatabase) lookupCIDR(cidr *net.IPNet) (int, string) {
+	first, last := utils.NetFirstLast(cidr)
+
+	r := db.search(first)
+	if r == nil || !r.contains(last.To16()) {
+		return 0, ""
+	}
+	return r.ASN, db.description(r.ASN)
+}
+
+func (db *asnDatabase) description(asn int) string {
+	db.Lock()
+	defer db.Unlock()
+
+	if record, found := db.records[asn]; found {
+		return record.Description
+	}
+	return ""
+}
+
+// record returns the ASRecord for the ASN, including the netblocks of all the ranges.
+func (db *asnDatabase) record(asn int) *ASRecord {
+	db.Lock()
+	defer db.Unlock()
+
+	ranges := db.byASN[asn]
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	record := &ASRecord{ASN: asn}
+	if r, found := db.records[asn]; found {
+		*record = *r
+	}
+	record.Netblocks = []string{}
+	for _, r := range ranges {
+		for _, cidr := range rangeToCIDRs(r.First, r.Last) {
+			record.Netblocks = utils.UniqueAppend(record.Netblocks, cidr.String())
+		}
+	}
+	if len(record.Netblocks) > 0 {
+		record.Prefix = record.Netblocks[0]
+	}
+	return record
+}
+
+// searchDescriptions returns the ASNs of the autonomous systems with descriptions containing the string.
+func (db *asnDatabase) searchDescriptions(s string) []int {
+	db.Lock()
+	defer db.Unlock()
+
+	var asns []int
+	s = strings.ToLower(s)
+	for asn, record := range db.records {
+		if len(db.byASN[asn]) > 0 && strings.Contains(strings.ToLower(record.Description), s) {
+			asns = append(asns, asn)
+		}
+	}
+	sort.Ints(asns)
+	return asns
+}
+
+// rangeToCIDRs returns the smallest set of CIDRs that cover the range of addresses.
+func rangeToCIDRs(first, last net.IP) []*net.IPNet {
+	bits := 128
+	if first.To4() != nil && last.To4() != nil {
+		bits = 32
+		first, last = first.To4(), last.To4()
+	}
+
+	var cidrs []*net.IPNet
+	one := big.NewInt(1)
+	start := new(big.Int).SetBytes(first)
+	end := new(big.Int).SetBytes(last)
+	for start.Cmp(end) <= 0 {
+		// Find the largest block aligned at the start that does not extend beyond the end
+		size := uint(0)
+		for size < uint(bits) {
+			if start.Bit(int(size)) != 0 {
+				break
+			}
+
+			blockEnd := new(big.Int).Lsh(one, size+1)
+			blockEnd.Add(blockEnd, start).Sub(blockEnd, one)
+			if blockEnd.Cmp(end) > 0 {
+				break
+			}
+			size++
+		}
+
+		ip := make(net.IP, bits/8)
+		b := start.Bytes()
+		copy(ip[len(ip)-len(b):], b)
+		cidrs = append(cidrs, &net.IPNet{
+			IP:   ip,
+			Mask: net.CIDRMask(bits-int(size), bits),
+		})
+
+		start.Add(start, new(big.Int).Lsh(one, size))
+	}
+	return cidrs
+}
+
+// LoadASNDatabase adds the IP-to-ASN data within the file to the data consulted before
+// online sources. The iptoasn.com TSV files, MaxMind GeoLite2 ASN databases and the
+// extended delegation files published by the regional internet registries are supported,
+// and the files can be gzip compressed.
+func LoadASNDatabase(path string) error {
+	return localASNData.files.load(databaseFileKey(path), func() error {
+		data, err := readDatabaseFile(path)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case utils.IsMMDB(data):
+			err = localASNData.loadMMDB(data)
+		case isDelegationFile(data):
+			err = localASNData.loadDelegations(bytes.NewReader(data))
+		default:
+			err = localASNData.loadIPToASN(bytes.NewReader(data))
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to load the ASN database %s: %v", path, err)
+		}
+		return nil
+	})
+}
+
+// readDatabaseFile returns the contents of the file, which is decompressed when necessary.
+func readDatabaseFile(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read the database file %s: %v", path, err)
+	}
+
+	if len(data) > 2 && data[0] == 0x1f && data[1] == 0x8b {
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decompress the database file %s: %v", path, err)
+		}
+		defer gz.Close()
+
+		data, err = ioutil.ReadAll(gz)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decompress the database file %s: %v", path, err)
+		}
+	}
+	return data, nil
+}
+
+// isDelegationFile checks the first data line for the pipe separated fields of RIR delegation files.
+func isDelegationFile(data []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		return strings.Count(line, "|") >= 5
+	}
+	return false
+}
+
+// loadIPToASN parses the iptoasn.com format: range_start, range_end, AS_number, country_code and AS_description.
+func (db *asnDatabase) loadIPToASN(r io.Reader) error {
+	db.Lock()
+	defer db.Unlock()
+
+	var count int
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) < 3 {
+			return fmt.Errorf("The line is not in the iptoasn format: %s", line)
+		}
+
+		asn, err := strconv.Atoi(fields[2])
+		// ASN zero indicates the range is not routed
+		if err != nil || asn == 0 {
+			continue
+		}
+
+		first, last := net.ParseIP(fields[0]), net.ParseIP(fields[1])
+		if first == nil || last == nil {
+			continue
+		}
+
+		var cc, desc string
+		if len(fields) > 3 {
+			cc = strings.TrimSpace(fields[3])
+		}
+		if len(fields) > 4 {
+			desc = strings.TrimSpace(fields[4])
+		}
+		db.addRange(first, last, asn)
+		db.addRecord(asn, cc, "", desc, time.Time{})
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if count == 0 {
+		return errors.New("No address ranges were found")
+	}
+	return nil
+}
+
+// loadDelegations parses the delegation files published by the regional internet registries.
+// The ranges are associated with an ASN using the opaque ID of the extended file format, and
+// the lowest ASN is used when the organization holds more than one.
+func (db *asnDatabase) loadDelegations(r io.Reader) error {
+	var blocks [][]string
+	orgASNs := make(map[string]int)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// registry|cc|type|start|value|date|status|opaque-id
+		fields := strings.Split(line, "|")
+		if len(fields) < 7 || fields[1] == "*" {
+			continue
+		}
+		if s := fields[6]; s != "allocated" && s != "assigned" {
+			continue
+		}
+
+		switch fields[2] {
+		case "asn":
+			first, err := strconv.Atoi(fields[3])
+			if err != nil {
+				continue
+			}
+
+			date, _ := time.Parse("20060102", fields[5])
+			db.Lock()
+			db.addRecord(first, fields[1], strings.ToUpper(fields[0]), "", date)
+			db.Unlock()
+			if len(fields) > 7 && fields[7] != "" {
+				if asn, found := orgASNs[fields[7]]; !found || first < asn {
+					orgASNs[fields[7]] = first
+				}
+			}
+		case "ipv4", "ipv6":
+			if len(fields) > 7 && fields[7] != "" {
+				blocks = append(blocks, fields)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	db.Lock()
+	defer db.Unlock()
+
+	var count int
+	for _, fields := range blocks {
+		asn, found := orgASNs[fields[7]]
+		if !found {
+			continue
+		}
+
+		first := net.ParseIP(fields[3])
+		value, err := strconv.Atoi(fields[4])
+		if first == nil || err != nil || value <= 0 {
+			continue
+		}
+
+		var last net.IP
+		if fields[2] == "ipv4" {
+			// The value is the number of addresses in the range
+			if first.To4() == nil {
+				continue
+			}
+			n := new(big.Int).SetBytes(first.To4())
+			n.Add(n, big.NewInt(int64(value-1)))
+			b := n.Bytes()
+			if len(b) > 4 {
+				continue
+			}
+			last = make(net.IP, 4)
+			copy(last[4-len(b):], b)
+		} else {
+			// The value is the prefix length of the range
+			if value > 128 {
+				continue
+			}
+			_, last = utils.NetFirstLast(&net.IPNet{
+				IP:   first.Mask(net.CIDRMask(value, 128)),
+				Mask: net.CIDRMask(value, 128),
+			})
+		}
+		db.addRange(first, last, asn)
+		count++
+	}
+	if count == 0 {
+		return errors.New("No address ranges were associated with autonomous systems")
+	}
+	return nil
+}
+
+// loadMMDB walks the networks within a MaxMind ASN database.
+func (db *asnDatabase) loadMMDB(data []byte) error {
+	reader, err := utils.NewMMDBReader(data)
+	if err != nil {
+		return err
+	}
+
+	db.Lock()
+	defer db.Unlock()
+
+	var count int
+	err = reader.Networks(func(ipnet *net.IPNet, value interface{}) error {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		asn, ok := m["autonomous_system_number"].(uint64)
+		if !ok || asn == 0 {
+			return nil
+		}
+		desc, _ := m["autonomous_system_organization"].(string)
+
+		first, last := utils.NetFirstLast(ipnet)
+		db.addRange(first, last, int(asn))
+		db.addRecord(int(asn), "", "", desc, time.Time{})
+		count++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("The %s database does not contain autonomous system numbers", reader.DatabaseType)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testIPToASN = `1.0.0.0	1.0.0.255	13335	US	CLOUDFLARENET - Cloudflare, Inc.
+1.0.1.0	1.0.3.255	0	None	Not routed
+1.0.4.0	1.0.7.255	38803	AU	WPL-AS-AP Wirefreebroadband Pty Ltd
+2001:db8::	2001:db8:ffff:ffff:ffff:ffff:ffff:ffff	64500	ZZ	EXAMPLE-V6
+`
+
+const testDelegations = `2|arin|20181105|3|19700101|20181105|-0500
+arin|*|asn|*|2|summary
+arin|US|asn|64510|1|20100101|assigned|org-1
+arin|US|asn|64505|1|20120101|assigned|org-1
+arin|US|ipv4|203.0.113.0|512|20100101|allocated|org-1
+arin|US|ipv6|2001:db8:1000::|36|20100101|allocated|org-1
+arin|CA|ipv4|198.51.100.0|256|20100101|reserved|org-2
+`
+
+func TestRangeIndexOverlapping(t *testing.T) {
+	var ranges []*ipRange
+	for _, r := range []struct {
+		first, last string
+		asn         int
+	}{
+		{"10.0.0.0", "10.255.255.255", 1},
+		{"10.1.0.0", "10.1.255.255", 2},
+		{"10.1.2.0", "10.1.2.255", 3},
+		{"11.0.0.0", "11.0.0.255", 4},
+		{"9.0.0.0", "12.0.0.0", 5},
+	} {
+		ranges = append(ranges, &ipRange{
+			First: net.ParseIP(r.first).To16(),
+			Last:  net.ParseIP(r.last).To16(),
+			ASN:   r.asn,
+		})
+	}
+
+	index := newRangeIndex(ranges)
+	for addr, asn := range map[string]int{
+		"10.1.2.3":   3,
+		"10.1.3.1":   2,
+		"10.200.0.1": 1,
+		"11.0.0.1":   4,
+		"11.0.1.1":   5,
+	} {
+		if r := index.Search(net.ParseIP(addr)); r == nil || r.ASN != asn {
+			t.Errorf("%s: Search returned %v instead of AS%d", addr, r, asn)
+		}
+	}
+	if r := index.Search(net.ParseIP("13.0.0.1")); r != nil {
+		t.Errorf("Search returned AS%d for an address outside of the ranges", r.ASN)
+	}
+}
+
+func TestRangeToCIDRs(t *testing.T) {
+	tests := []struct {
+		first, last string
+		cidrs       string
+	}{
+		{"192.168.1.0", "192.168.1.255", "192.168.1.0/24"},
+		{"1.0.4.0", "1.0.7.255", "1.0.4.0/22"},
+		{"10.0.0.1", "10.0.0.6", "10.0.0.1/32 10.0.0.2/31 10.0.0.4/31 10.0.0.6/32"},
+		{"2001:db8::", "2001:db8:0:1:ffff:ffff:ffff:ffff", "2001:db8::/63"},
+	}
+
+	for _, test := range tests {
+		var cidrs []string
+		for _, c := range rangeToCIDRs(net.ParseIP(test.first), net.ParseIP(test.last)) {
+			cidrs = append(cidrs, c.String())
+		}
+		if got := strings.Join(cidrs, " "); got != test.cidrs {
+			t.Errorf("%s-%s: rangeToCIDRs returned %s instead of %s", test.first, test.last, got, test.cidrs)
+		}
+	}
+}
+
+func TestLoadIPToASN(t *testing.T) {
+	db := newASNDatabase()
+	if err := db.loadIPToASN(strings.NewReader(testIPToASN)); err != nil {
+		t.Fatalf("loadIPToASN failed: %v", err)
+	}
+
+	asn, cidr, desc := db.lookupIP("1.0.5.1")
+	if asn != 38803 || cidr.String() != "1.0.4.0/22" || !strings.HasPrefix(desc, "WPL-AS-AP") {
+		t.Errorf("lookupIP returned AS%d, %v, %s", asn, cidr, desc)
+	}
+	if asn, _, _ = db.lookupIP("1.0.2.1"); asn != 0 {
+		t.Errorf("lookupIP returned AS%d for a range that is not routed", asn)
+	}
+	if asn, _, _ = db.lookupIP("2001:db8::1"); asn != 64500 {
+		t.Errorf("lookupIP returned AS%d instead of AS64500 for the IPv6 address", asn)
+	}
+
+	_, ipnet, _ := net.ParseCIDR("1.0.6.0/24")
+	if asn, _ := db.lookupCIDR(ipnet); asn != 38803 {
+		t.Errorf("lookupCIDR returned AS%d instead of AS38803", asn)
+	}
+	_, ipnet, _ = net.ParseCIDR("1.0.0.0/16")
+	if asn, _ := db.lookupCIDR(ipnet); asn != 0 {
+		t.Errorf("lookupCIDR returned AS%d for a netblock larger than the range", asn)
+	}
+
+	record := db.record(13335)
+	if record == nil || record.CC != "US" || len(record.Netblocks) != 1 || record.Netblocks[0] != "1.0.0.0/24" {
+		t.Errorf("record returned the unexpected ASRecord %v", record)
+	}
+	if asns := db.searchDescriptions("cloudflare"); len(asns) != 1 || asns[0] != 13335 {
+		t.Errorf("searchDescriptions returned %v", asns)
+	}
+}
+
+func TestLoadDelegations(t *testing.T) {
+	db := newASNDatabase()
+	if err := db.loadDelegations(strings.NewReader(testDelegations)); err != nil {
+		t.Fatalf("loadDelegations failed: %v", err)
+	}
+
+	// The lowest ASN of the organization is used
+	asn, cidr, _ := db.lookupIP("203.0.114.200")
+	if asn != 64505 || cidr.String() != "203.0.114.0/24" {
+		t.Errorf("lookupIP returned AS%d, %v", asn, cidr)
+	}
+	if asn, _, _ = db.lookupIP("2001:db8:1fff::1"); asn != 64505 {
+		t.Errorf("lookupIP returned AS%d for the IPv6 range", asn)
+	}
+	// Reserved ranges are not loaded
+	if asn, _, _ = db.lookupIP("198.51.100.1"); asn != 0 {
+		t.Errorf("lookupIP returned AS%d for a reserved range", asn)
+	}
+
+	record := db.record(64505)
+	if record == nil || record.Registry != "ARIN" || record.AllocationDate.Year() != 2012 {
+		t.Errorf("record returned the unexpected ASRecord %v", record)
+	}
+}
+
+func TestLoadASNDatabase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "asndb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "ip2asn-combined.tsv.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	gz.Write([]byte(testIPToASN))
+	gz.Close()
+	f.Close()
+
+	defer func() { localASNData = newASNDatabase() }()
+	if err := LoadASNDatabase(path); err != nil {
+		t.Fatalf("LoadASNDatabase failed: %v", err)
+	}
+
+	// Loading the file again, as repeated enumerations do, does not duplicate the ranges
+	ranges := len(localASNData.ranges)
+	if err := LoadASNDatabase(filepath.Join(dir, ".", "ip2asn-combined.tsv.gz")); err != nil {
+		t.Fatalf("LoadASNDatabase failed to load the file again: %v", err)
+	}
+	if len(localASNData.ranges) != ranges {
+		t.Errorf("Loading the file again changed the ranges from %d to %d", ranges, len(localASNData.ranges))
+	}
+
+	// Data cached by a previous run does not override the databases provided
+	cache := netCache
+	defer func() { netCache = cache }()
+	netCache = newInfraCache(DefaultInfraCacheTTL)
+	netCache.insert(&ASRecord{ASN: 64500, Description: "STALE", Netblocks: []string{"1.0.0.0/24"}})
+
+	// The local data is used without online lookups
+	asn, cidr, desc, err := IPRequest("1.0.0.1")
+	if err != nil || asn != 13335 || cidr.String() != "1.0.0.0/24" || !strings.HasPrefix(desc, "CLOUDFLARENET") {
+		t.Errorf("IPRequest returned AS%d, %v, %s, %v", asn, cidr, desc, err)
+	}
+	record, err := ASNRequest(38803)
+	if err != nil || len(record.Netblocks) != 1 || record.Netblocks[0] != "1.0.4.0/22" {
+		t.Errorf("ASNRequest returned %v, %v", record, err)
+	}
+
+	if err := LoadASNDatabase(filepath.Join(dir, "missing.tsv")); err == nil {
+		t.Errorf("LoadASNDatabase did not return an error for a missing file")
+	}
+}
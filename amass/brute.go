@@ -4,9 +4,12 @@
 package amass
 
 import (
+	"container/heap"
+	"math"
 	"time"
 
 	"github.com/OWASP/Amass/amass/core"
+	"github.com/OWASP/Amass/amass/dnssrv"
 	evbus "github.com/asaskevich/EventBus"
 )
 
@@ -15,13 +18,34 @@ import (
 type BruteForceService struct {
 	core.BaseAmassService
 
-	bus evbus.Bus
+	bus        evbus.Bus
+	generators []WordGenerator
+
+	// The wordlists used at each level of recursive brute forcing
+	levels []WordGenerator
+
+	// Subdomains considered for recursive brute forcing, ordered by score
+	targets map[string]*recursionTarget
+	queue   recursionQueue
+	signal  chan struct{}
+
+	progress *progressCounter
+
+	// The number of words provided by each generator, once counted in the background
+	wordCounts map[WordGenerator]uint64
+	// The generators of the brute forcing started before the words were counted
+	uncounted [][]WordGenerator
 }
 
 // NewBruteForceService requires the enumeration configuration and event bus as parameters.
 // The object returned is initialized, but has not yet been started.
 func NewBruteForceService(config *core.AmassConfig, bus evbus.Bus) *BruteForceService {
-	bfs := &BruteForceService{bus: bus}
+	bfs := &BruteForceService{
+		bus:      bus,
+		targets:  make(map[string]*recursionTarget),
+		signal:   make(chan struct{}, 1),
+		progress: newProgressCounter(),
+	}
 
 	bfs.BaseAmassService = *core.NewBaseAmassService("Brute Forcing Service", config, bfs)
 	return bfs
@@ -32,9 +56,23 @@ func (bfs *BruteForceService) OnStart() error {
 	bfs.BaseAmassService.OnStart()
 
 	if bfs.Config().BruteForcing {
+		gens, err := BruteForceGenerators(bfs.Config())
+		if err != nil {
+			return err
+		}
+		bfs.generators = gens
+
+		levels, err := RecursiveGenerators(bfs.Config())
+		if err != nil {
+			return err
+		}
+		bfs.levels = levels
+
+		go bfs.countWords()
 		go bfs.startRootDomains()
 
 		if bfs.Config().Recursive {
+			go bfs.processRecursion()
 			bfs.bus.SubscribeAsync(core.NEWSUB, bfs.newSubdomain, false)
 		}
 	}
@@ -54,26 +92,180 @@ func (bfs *BruteForceService) OnStop() error {
 func (bfs *BruteForceService) startRootDomains() {
 	// Look at each domain provided by the config
 	for _, domain := range bfs.Config().Domains() {
-		bfs.performBruteForcing(domain, domain)
+		bfs.performBruteForcing(domain, domain, bfs.generators)
 	}
 }
 
 func (bfs *BruteForceService) newSubdomain(req *core.AmassRequest, times int) {
-	if times == bfs.Config().MinForRecursive {
-		go bfs.performBruteForcing(req.Name, req.Domain)
+	if times < bfs.Config().MinForRecursive {
+		return
+	}
+
+	depth := subdomainDepth(req.Name, req.Domain)
+	if depth == 0 {
+		return
+	}
+	if max := bfs.Config().MaxRecursionDepth; max > 0 && depth > max {
+		return
+	}
+
+	bfs.Lock()
+	if t, found := bfs.targets[req.Name]; found {
+		bfs.queue.update(t, times)
+	} else {
+		t = &recursionTarget{
+			Name:     req.Name,
+			Domain:   req.Domain,
+			Depth:    depth,
+			Children: times,
+		}
+		bfs.targets[req.Name] = t
+		heap.Push(&bfs.queue, t)
+	}
+	bfs.Unlock()
+
+	select {
+	case bfs.signal <- struct{}{}:
+	default:
 	}
 }
 
-func (bfs *BruteForceService) performBruteForcing(subdomain, root string) {
-	t := time.NewTicker(time.Second)
-	defer t.Stop()
-	for _, word := range bfs.Config().Wordlist {
+func (bfs *BruteForceService) nextTarget() *recursionTarget {
+	bfs.Lock()
+	defer bfs.Unlock()
+
+	if bfs.queue.Len() == 0 {
+		return nil
+	}
+	return heap.Pop(&bfs.queue).(*recursionTarget)
+}
+
+// processRecursion brute forces the subdomains one at a time, so the
+// most productive subdomains are always selected next.
+func (bfs *BruteForceService) processRecursion() {
+	for {
 		select {
-		case <-t.C:
-			bfs.SetActive()
 		case <-bfs.Quit():
 			return
-		default:
+		case <-bfs.signal:
+		}
+
+		for t := bfs.nextTarget(); t != nil; t = bfs.nextTarget() {
+			select {
+			case <-bfs.Quit():
+				return
+			default:
+			}
+
+			bfs.SetActive()
+			if bfs.hasWildcard(t.Name) {
+				bfs.Config().Log.Printf("Recursive brute forcing of %s was skipped due to a DNS wildcard", t.Name)
+				continue
+			}
+			bfs.performBruteForcing(t.Name, t.Domain, bfs.generatorsForDepth(t.Depth))
+		}
+	}
+}
+
+// generatorsForDepth returns the word generators for brute forcing beneath a subdomain
+// at the depth provided. The last level wordlist is used for all deeper subdomains.
+func (bfs *BruteForceService) generatorsForDepth(depth int) []WordGenerator {
+	if len(bfs.levels) == 0 {
+		return bfs.generators
+	}
+	if depth > len(bfs.levels) {
+		depth = len(bfs.levels)
+	}
+	return []WordGenerator{bfs.levels[depth-1]}
+}
+
+// hasWildcard returns true when an unlikely name beneath the subdomain resolves.
+func (bfs *BruteForceService) hasWildcard(sub string) bool {
+	name := dnssrv.UnlikelyName(sub)
+	if name == "" {
+		return false
+	}
+
+	core.MaxConnections.Acquire(1)
+	defer core.MaxConnections.Release(1)
+
+	_, err := dnssrv.Resolve(name, "A")
+	return err == nil
+}
+
+// countWords counts the words provided by each generator, which can require reading large
+// wordlist files, and then adds the words of the brute forcing that has already started to the total.
+func (bfs *BruteForceService) countWords() {
+	counts := make(map[WordGenerator]uint64)
+	for _, gen := range append(append([]WordGenerator{}, bfs.generators...), bfs.levels...) {
+		if _, found := counts[gen]; !found {
+			counts[gen] = gen.Count()
+		}
+	}
+
+	bfs.Lock()
+	bfs.wordCounts = counts
+	started := bfs.uncounted
+	bfs.uncounted = nil
+	bfs.Unlock()
+
+	for _, gens := range started {
+		bfs.progress.AddTotal(wordCount(gens, counts))
+	}
+}
+
+// addTotal adds the words of the generators to the total, or holds them until the words have been counted.
+func (bfs *BruteForceService) addTotal(gens []WordGenerator) {
+	bfs.Lock()
+	if bfs.wordCounts == nil {
+		bfs.uncounted = append(bfs.uncounted, gens)
+		bfs.Unlock()
+		return
+	}
+	total := wordCount(gens, bfs.wordCounts)
+	bfs.Unlock()
+
+	bfs.progress.AddTotal(total)
+}
+
+// Progress returns the progress of brute forcing, including the subdomains waiting for recursion.
+// The total is unknown until the words provided by the generators have been counted.
+func (bfs *BruteForceService) Progress() PhaseProgress {
+	bfs.Lock()
+	queued := bfs.queue.Len()
+	if bfs.Config().BruteForcing && bfs.wordCounts == nil {
+		bfs.Unlock()
+		return bfs.progress.Snapshot(queued, math.MaxUint64)
+	}
+
+	var pending []WordGenerator
+	for _, t := range bfs.queue {
+		pending = append(pending, bfs.generatorsForDepth(t.Depth)...)
+	}
+	extra := wordCount(pending, bfs.wordCounts)
+	bfs.Unlock()
+
+	return bfs.progress.Snapshot(queued, extra)
+}
+
+func (bfs *BruteForceService) performBruteForcing(subdomain, root string, gens []WordGenerator) {
+	bfs.addTotal(gens)
+
+	t := time.NewTicker(time.Second)
+	defer t.Stop()
+
+	var quit bool
+	for _, gen := range gens {
+		gen.Each(func(word string) bool {
+			select {
+			case <-t.C:
+				bfs.SetActive()
+			case <-bfs.Quit():
+				quit = true
+				return false
+			default:
+			}
+
 			bfs.Config().MaxFlow.Acquire(1)
 			bfs.bus.Publish(core.NEWNAME, &core.AmassRequest{
 				Name:   word + "." + subdomain,
@@ -81,6 +273,15 @@ func (bfs *BruteForceService) performBruteForcing(subdomain, root string) {
 				Tag:    core.BRUTE,
 				Source: "Brute Force",
 			})
+			bfs.progress.Inc()
+			return true
+		})
+
+		if g, ok := gen.(interface{ Err() error }); ok && g.Err() != nil {
+			bfs.Config().Log.Printf("Brute forcing %s: %v", subdomain, g.Err())
+		}
+		if quit {
+			return
 		}
 	}
 }
@@ -0,0 +1,272 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// CloudRange is a netblock published by a cloud or CDN provider.
+type CloudRange struct {
+	Provider string
+	Service  string
+	Region   string
+	CIDR     *net.IPNet
+}
+
+// cloudDatabase provides the most specific published netblock containing an address.
+type cloudDatabase struct {
+	sync.Mutex
+	trie  *prefixTrie
+	files loadedFiles
+}
+
+// The cloud provider IP ranges loaded using LoadCloudRanges
+var cloudRanges = newCloudDatabase()
+
+func newCloudDatabase() *cloudDatabase {
+	return &cloudDatabase{trie: newPrefixTrie()}
+}
+
+func (db *cloudDatabase) insert(cr *CloudRange) {
+	db.Lock()
+	defer db.Unlock()
+
+	n := db.trie.insert(cr.CIDR)
+	// Ranges that provide the service are preferred over generic ones, such as the
+	// AMAZON ranges that duplicate the ranges of the individual AWS services
+	if old, ok := n.value.(*CloudRange); !ok || old.Service == "" || old.Service == "AMAZON" {
+		n.value = cr
+	}
+}
+
+func (db *cloudDatabase) lookup(addr string) *CloudRange {
+	db.Lock()
+	defer db.Unlock()
+
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return nil
+	}
+
+	if n := db.trie.match(ip); n != nil {
+		return n.value.(*CloudRange)
+	}
+	return nil
+}
+
+func (db *cloudDatabase) add(provider, service, region, cidr string) {
+	_, ipnet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+	if err != nil {
+		return
+	}
+
+	db.insert(&CloudRange{
+		Provider: provider,
+		Service:  service,
+		Region:   region,
+		CIDR:     ipnet,
+	})
+}
+
+// The formats of the JSON files published by the providers
+type awsRanges struct {
+	Prefixes []struct {
+		Prefix  string `json:"ip_prefix"`
+		Region  string `json:"region"`
+		Service string `json:"service"`
+	} `json:"prefixes"`
+	IPv6Prefixes []struct {
+		Prefix  string `json:"ipv6_prefix"`
+		Region  string `json:"region"`
+		Service string `json:"service"`
+	} `json:"ipv6_prefixes"`
+}
+
+type azureRanges struct {
+	Values []struct {
+		Name       string `json:"name"`
+		Properties struct {
+			Region          string   `json:"region"`
+			SystemService   string   `json:"systemService"`
+			AddressPrefixes []string `json:"addressPrefixes"`
+		} `json:"properties"`
+	} `json:"values"`
+}
+
+type gcpRanges struct {
+	Prefixes []struct {
+		IPv4Prefix string `json:"ipv4Prefix"`
+		IPv6Prefix string `json:"ipv6Prefix"`
+		Service    string `json:"service"`
+		Scope      string `json:"scope"`
+	} `json:"prefixes"`
+}
+
+type oracleRanges struct {
+	Regions []struct {
+		Region string `json:"region"`
+		CIDRs  []struct {
+			CIDR string   `json:"cidr"`
+			Tags []string `json:"tags"`
+		} `json:"cidrs"`
+	} `json:"regions"`
+}
+
+type fastlyRanges struct {
+	Addresses     []string `json:"addresses"`
+	IPv6Addresses []string `json:"ipv6_addresses"`
+}
+
+// loadJSON detects the provider that published the JSON data and adds the ranges.
+func (db *cloudDatabase) loadJSON(data []byte, provider string) error {
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return err
+	}
+
+	has := func(key string) bool {
+		_, found := keys[key]
+		return found
+	}
+
+	switch {
+	case has("ipv6_prefixes"):
+		var r awsRanges
+		if err := json.Unmarshal(data, &r); err != nil {
+			return err
+		}
+		for _, p := range r.Prefixes {
+			db.add(providerName(provider, "AWS"), p.Service, p.Region, p.Prefix)
+		}
+		for _, p := range r.IPv6Prefixes {
+			db.add(providerName(provider, "AWS"), p.Service, p.Region, p.Prefix)
+		}
+	case has("values") && has("cloud"):
+		var r azureRanges
+		if err := json.Unmarshal(data, &r); err != nil {
+			return err
+		}
+		for _, v := range r.Values {
+			service := v.Properties.SystemService
+			if service == "" {
+				service = strings.SplitN(v.Name, ".", 2)[0]
+			}
+			for _, p := range v.Properties.AddressPrefixes {
+				db.add(providerName(provider, "Azure"), service, v.Properties.Region, p)
+			}
+		}
+	case has("prefixes"):
+		var r gcpRanges
+		if err := json.Unmarshal(data, &r); err != nil {
+			return err
+		}
+		for _, p := range r.Prefixes {
+			cidr := p.IPv4Prefix
+			if cidr == "" {
+				cidr = p.IPv6Prefix
+			}
+			db.add(providerName(provider, "GCP"), p.Service, p.Scope, cidr)
+		}
+	case has("regions"):
+		var r oracleRanges
+		if err := json.Unmarshal(data, &r); err != nil {
+			return err
+		}
+		for _, region := range r.Regions {
+			for _, c := range region.CIDRs {
+				db.add(providerName(provider, "Oracle"), strings.Join(c.Tags, ","), region.Region, c.CIDR)
+			}
+		}
+	case has("addresses"):
+		var r fastlyRanges
+		if err := json.Unmarshal(data, &r); err != nil {
+			return err
+		}
+		for _, cidr := range append(r.Addresses, r.IPv6Addresses...) {
+			db.add(providerName(provider, "Fastly"), "", "", cidr)
+		}
+	default:
+		return fmt.Errorf("The format of the JSON data was not recognized")
+	}
+	return nil
+}
+
+// loadText adds the netblocks listed one per line, such as the Cloudflare lists. The lines
+// can provide the service and region after the netblock, separated by commas.
+func (db *cloudDatabase) loadText(data []byte, provider string) error {
+	if provider == "" {
+		return fmt.Errorf("The provider name is required for IP range lists")
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, ",")
+		for len(fields) < 3 {
+			fields = append(fields, "")
+		}
+		if _, _, err := net.ParseCIDR(strings.TrimSpace(fields[0])); err != nil {
+			return fmt.Errorf("%s is not a valid netblock", fields[0])
+		}
+		db.add(provider, strings.TrimSpace(fields[1]), strings.TrimSpace(fields[2]), fields[0])
+	}
+	return scanner.Err()
+}
+
+func providerName(provided, detected string) string {
+	if provided != "" {
+		return provided
+	}
+	return detected
+}
+
+// LoadCloudRanges adds the IP ranges published by a cloud or CDN provider to the data used to
+// label addresses. The spec is the path to the file, optionally preceded by the provider name
+// and an equal sign (e.g. cloudflare=ips-v4). The JSON files published by AWS, Azure, GCP,
+// Oracle Cloud and Fastly are recognized, and other providers are supported using files that
+// list one netblock per line. The files can be gzip compressed.
+func LoadCloudRanges(spec string) error {
+	var provider string
+
+	path := spec
+	if parts := strings.SplitN(spec, "=", 2); len(parts) == 2 && !strings.ContainsAny(parts[0], `/\`) {
+		provider, path = strings.TrimSpace(parts[0]), parts[1]
+	}
+
+	// The provider name is part of the key, since it labels the ranges
+	return cloudRanges.files.load(provider+"="+databaseFileKey(path), func() error {
+		data, err := readDatabaseFile(path)
+		if err != nil {
+			return err
+		}
+
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+			err = cloudRanges.loadJSON(trimmed, provider)
+		} else {
+			err = cloudRanges.loadText(data, provider)
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to load the cloud IP ranges %s: %v", filepath.Base(path), err)
+		}
+		return nil
+	})
+}
+
+// CloudRequest returns the most specific published cloud or CDN provider range containing
+// the address, or nil when the address is not within the ranges loaded by LoadCloudRanges.
+func CloudRequest(addr string) *CloudRange {
+	return cloudRanges.lookup(addr)
+}
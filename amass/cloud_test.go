@@ -0,0 +1,71 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"testing"
+)
+
+func TestCloudDatabaseJSON(t *testing.T) {
+	db := newCloudDatabase()
+
+	aws := `{"syncToken": "1", "prefixes": [
+		{"ip_prefix": "192.0.2.0/24", "region": "us-east-1", "service": "AMAZON"},
+		{"ip_prefix": "192.0.2.0/24", "region": "us-east-1", "service": "EC2"},
+		{"ip_prefix": "192.0.2.128/25", "region": "us-east-1", "service": "AMAZON"}],
+		"ipv6_prefixes": [{"ipv6_prefix": "2001:db8::/32", "region": "eu-west-1", "service": "S3"}]}`
+	gcp := `{"syncToken": "1", "prefixes": [{"ipv4Prefix": "198.51.100.0/24", "service": "Google Cloud", "scope": "us-central1"}]}`
+	azure := `{"changeNumber": 1, "cloud": "Public", "values": [{"name": "AzureCloud.westeurope",
+		"properties": {"region": "westeurope", "systemService": "", "addressPrefixes": ["203.0.113.0/24"]}}]}`
+
+	for _, data := range []string{aws, gcp, azure} {
+		if err := db.loadJSON([]byte(data), ""); err != nil {
+			t.Fatalf("loadJSON failed: %v", err)
+		}
+	}
+	if err := db.loadJSON([]byte(`{"unknown": []}`), ""); err == nil {
+		t.Errorf("loadJSON did not return an error for an unknown format")
+	}
+
+	tests := []struct {
+		addr, provider, service, region, cidr string
+	}{
+		{"192.0.2.10", "AWS", "EC2", "us-east-1", "192.0.2.0/24"},
+		{"192.0.2.200", "AWS", "AMAZON", "us-east-1", "192.0.2.128/25"},
+		{"2001:db8::1", "AWS", "S3", "eu-west-1", "2001:db8::/32"},
+		{"198.51.100.1", "GCP", "Google Cloud", "us-central1", "198.51.100.0/24"},
+		{"203.0.113.1", "Azure", "AzureCloud", "westeurope", "203.0.113.0/24"},
+	}
+	for _, test := range tests {
+		cr := db.lookup(test.addr)
+		if cr == nil || cr.Provider != test.provider || cr.Service != test.service ||
+			cr.Region != test.region || cr.CIDR.String() != test.cidr {
+			t.Errorf("The lookup for %s returned %+v", test.addr, cr)
+		}
+	}
+	if cr := db.lookup("192.168.1.1"); cr != nil {
+		t.Errorf("The lookup for an address outside of the ranges returned %+v", cr)
+	}
+}
+
+func TestCloudDatabaseText(t *testing.T) {
+	db := newCloudDatabase()
+	data := []byte("# Cloudflare\n192.0.2.0/24\n\n192.0.2.64/26,CDN,LHR\n")
+
+	if err := db.loadText(data, ""); err == nil {
+		t.Errorf("loadText did not return an error without the provider name")
+	}
+	if err := db.loadText(data, "Cloudflare"); err != nil {
+		t.Fatalf("loadText failed: %v", err)
+	}
+	if cr := db.lookup("192.0.2.70"); cr == nil || cr.Provider != "Cloudflare" || cr.Service != "CDN" || cr.Region != "LHR" {
+		t.Errorf("The lookup for 192.0.2.70 returned %+v", cr)
+	}
+	if cr := db.lookup("192.0.2.1"); cr == nil || cr.CIDR.String() != "192.0.2.0/24" || cr.Service != "" {
+		t.Errorf("The lookup for 192.0.2.1 returned %+v", cr)
+	}
+	if err := db.loadText([]byte("not-a-cidr\n"), "Cloudflare"); err == nil {
+		t.Errorf("loadText did not return an error for an invalid netblock")
+	}
+}
@@ -4,11 +4,14 @@
 package core
 
 import (
+	"fmt"
 	"io"
 	"log"
+	"net"
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/OWASP/Amass/amass/utils"
 )
@@ -26,21 +29,50 @@ type AmassConfig struct {
 	// The ports that will be checked for certificates
 	Ports []int
 
+	// Will web services on the ports be probed for each resolved name?
+	HTTPProbe bool
+
 	// The list of words to use when generating names
 	Wordlist []string
 
+	// Wordlist files streamed during brute forcing, which can be gzip compressed
+	WordlistFiles []string
+
 	// Will the enumeration including brute forcing techniques
 	BruteForcing bool
 
+	// Hashcat style masks used to generate labels during brute forcing
+	BruteMasks []string
+
+	// Custom character sets referenced within the masks as ?1 through ?4
+	MaskCharsets []string
+
+	// Words joined with the Wordlist as word1-word2 during brute forcing
+	CombinatorWordlist []string
+
 	// Will recursive brute forcing be performed?
 	Recursive bool
 
 	// Minimum number of subdomain discoveries before performing recursive brute forcing
 	MinForRecursive int
 
+	// Maximum number of labels beneath the root domain for subdomains that are
+	// recursively brute forced (no limit when zero)
+	MaxRecursionDepth int
+
+	// Wordlist files used for each level of recursive brute forcing, starting with the
+	// subdomains directly beneath the root domain (the last file is used for deeper levels)
+	RecursiveWordlistFiles []string
+
 	// Will discovered subdomain name alterations be generated?
 	Alterations bool
 
+	// The list of words used when generating altered names (default list used when empty)
+	AltWordlist []string
+
+	// The maximum number of word-based altered names sent each second (timing based when zero)
+	AltRate int
+
 	// Indicates a speed band for the enumeration to execute within
 	Timing EnumerationTiming
 
@@ -53,9 +85,54 @@ type AmassConfig struct {
 	// Determines if unresolved DNS names will be output by the enumeration
 	IncludeUnresolvable bool
 
+	// The number of addresses swept around each discovered address (default used when zero)
+	SweepSize int
+
+	// Will the entire netblock containing each discovered address be swept?
+	SweepNetblocks bool
+
+	// The maximum number of reverse DNS sweep queries per second (timing based when zero)
+	SweepRate int
+
+	// The IP address ranges in scope for the enumeration (all addresses when empty)
+	CIDRs []*net.IPNet
+
+	// The autonomous systems whose announced netblocks are in scope for the enumeration
+	ASNs []int
+
+	// The IP address ranges that are out of scope for the enumeration
+	ExcludedCIDRs []*net.IPNet
+
+	// The autonomous systems whose announced netblocks are out of scope for the enumeration
+	ExcludedASNs []int
+
+	// Local IP-to-ASN database files consulted before the online sources
+	ASNDatabases []string
+
+	// Cloud and CDN provider IP range files used to label the addresses (see LoadCloudRanges)
+	CloudRanges []string
+
+	// MaxMind DB file that provides the location of the addresses (not used when empty)
+	GeoDatabase string
+
+	// The file that keeps the ASN and netblock data obtained online between runs (not kept when empty)
+	InfraCachePath string
+
+	// The amount of time cached ASN and netblock data is used before it is refreshed
+	InfraCacheTTL time.Duration
+
+	// Will registration data be retrieved using RDAP for netblocks, ASNs and root domains?
+	RDAP bool
+
 	// A blacklist of subdomain names that will not be investigated
 	Blacklist []string
 
+	// The data source names or types that will be used (all sources when empty)
+	IncludeSources []string
+
+	// The data source names or types that will not be used
+	ExcludeSources []string
+
 	// The writer used to save the data operations performed
 	DataOptsWriter io.Writer
 
@@ -70,6 +147,15 @@ type AmassConfig struct {
 
 	// The API keys used by various data sources
 	apikeys map[string]string
+
+	// The rules built from the blacklist patterns, and a copy of the patterns used
+	blacklistRules    *RuleSet
+	blacklistPatterns []string
+
+	// The netblocks in and out of scope once the autonomous systems have been expanded
+	scopeCIDRs    []*net.IPNet
+	scopeExcluded []*net.IPNet
+	scopeSet      bool
 }
 
 // Graph returns the Amass graph that contains all enumeration findings.
@@ -142,17 +228,120 @@ func (c *AmassConfig) WhichDomain(name string) string {
 	return ""
 }
 
-// Blacklisted returns true is the name in the parameter ends with a subdomain name in the config blacklist.
+// SetScope assigns the netblocks in and out of scope, which include the netblocks
+// announced by the autonomous systems in the configuration.
+func (c *AmassConfig) SetScope(included, excluded []*net.IPNet) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.scopeCIDRs = included
+	c.scopeExcluded = excluded
+	c.scopeSet = true
+}
+
+// ScopeCIDRs returns the netblocks in scope for the enumeration, including the netblocks
+// announced by the autonomous systems once the scope has been assigned.
+func (c *AmassConfig) ScopeCIDRs() []*net.IPNet {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.scopeSet {
+		return c.scopeCIDRs
+	}
+	return c.CIDRs
+}
+
+func (c *AmassConfig) excludedCIDRs() []*net.IPNet {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.scopeSet {
+		return c.scopeExcluded
+	}
+	return c.ExcludedCIDRs
+}
+
+// IsAddressInScope returns true if the IP address in the parameter is within the address scope
+// of the enumeration. All addresses that are not excluded are in scope when no CIDRs or ASNs are provided.
+func (c *AmassConfig) IsAddressInScope(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil || c.IsAddressExcluded(addr) {
+		return false
+	}
+	if len(c.CIDRs) == 0 && len(c.ASNs) == 0 {
+		return true
+	}
+
+	for _, cidr := range c.ScopeCIDRs() {
+		if cidr.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAddressExcluded returns true if the IP address in the parameter is within an excluded CIDR.
+func (c *AmassConfig) IsAddressExcluded(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+
+	for _, cidr := range c.excludedCIDRs() {
+		if cidr.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Blacklisted returns true if the name in the parameter is matched by the rules in the config blacklist.
 func (c *AmassConfig) Blacklisted(name string) bool {
-	var resp bool
+	blacklisted, _ := c.BlacklistReason(name)
+	return blacklisted
+}
 
-	for _, bl := range c.Blacklist {
-		if match := strings.HasSuffix(name, bl); match {
-			resp = true
-			break
+// BlacklistReason returns true if the name in the parameter is matched by the rules in the config
+// blacklist, along with the reason that identifies the responsible rule.
+// Names are considered blacklisted when the patterns cannot be parsed.
+func (c *AmassConfig) BlacklistReason(name string) (bool, string) {
+	rules, err := c.BlacklistRules()
+	if err != nil {
+		return true, fmt.Sprintf("%s was not investigated: %v", name, err)
+	}
+	return rules.Match(name)
+}
+
+// BlacklistRules returns the RuleSet built from the patterns in the config blacklist.
+// The rules are built again when the blacklist has been changed.
+func (c *AmassConfig) BlacklistRules() (*RuleSet, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.blacklistRules != nil && equalStrings(c.blacklistPatterns, c.Blacklist) {
+		return c.blacklistRules, nil
+	}
+
+	rules, err := NewRuleSet(c.Blacklist)
+	if err != nil {
+		return nil, err
+	}
+	c.blacklistRules = rules
+	c.blacklistPatterns = append([]string{}, c.Blacklist...)
+	return rules, nil
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
 		}
 	}
-	return resp
+	return true
 }
 
 // AddAPIKey adds the data source and API key association provided to the configuration.
@@ -0,0 +1,55 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package core
+
+import (
+	"net"
+	"testing"
+)
+
+func parseTestCIDRs(nets []string) []*net.IPNet {
+	var cidrs []*net.IPNet
+
+	for _, n := range nets {
+		if _, cidr, err := net.ParseCIDR(n); err == nil {
+			cidrs = append(cidrs, cidr)
+		}
+	}
+	return cidrs
+}
+
+func TestIsAddressInScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		cidrs    []string
+		excluded []string
+		addr     string
+		expected bool
+	}{
+		{"no scope", nil, nil, "203.0.113.1", true},
+		{"within netblock", []string{"192.0.2.0/24"}, nil, "192.0.2.10", true},
+		{"outside netblock", []string{"192.0.2.0/24"}, nil, "198.51.100.1", false},
+		{"excluded", nil, []string{"192.0.2.0/25"}, "192.0.2.10", false},
+		{"excluded within netblock", []string{"192.0.2.0/24"}, []string{"192.0.2.0/25"}, "192.0.2.10", false},
+		{"not excluded within netblock", []string{"192.0.2.0/24"}, []string{"192.0.2.0/25"}, "192.0.2.200", true},
+		{"ipv6", []string{"2001:db8::/32"}, nil, "2001:db8::1", true},
+		{"invalid address", nil, nil, "not-an-address", false},
+	}
+
+	for _, test := range tests {
+		config := &AmassConfig{
+			CIDRs:         parseTestCIDRs(test.cidrs),
+			ExcludedCIDRs: parseTestCIDRs(test.excluded),
+		}
+
+		if got := config.IsAddressInScope(test.addr); got != test.expected {
+			t.Errorf("%s: IsAddressInScope(%s) returned %t", test.name, test.addr, got)
+		}
+	}
+
+	config := &AmassConfig{ExcludedCIDRs: parseTestCIDRs([]string{"192.0.2.0/25"})}
+	if !config.IsAddressExcluded("192.0.2.10") || config.IsAddressExcluded("192.0.2.200") {
+		t.Errorf("IsAddressExcluded did not check the addresses against the excluded netblocks")
+	}
+}
@@ -15,11 +15,15 @@ const (
 	CHECKED    = "amass:checked"
 	DNSQUERY   = "amass:dnsquery"
 	DNSSWEEP   = "amass.dnssweep"
+	HTTPINFO   = "amass:httpinfo"
+	HTTPPROBE  = "amass:httpprobe"
+	NEWCERT    = "amass:newcert"
 	NEWNAME    = "amass:newname"
 	NEWSUB     = "amass:newsubdomain"
 	OUTPUT     = "amass:output"
 	RELEASEREQ = "amass:releaserequest"
 	RESOLVED   = "amass:resolved"
+	SOURCEDATA = "amass:sourcedata"
 
 	ALT     = "alt"
 	ARCHIVE = "archive"
@@ -128,3 +132,45 @@ func TimingToReleasesPerSecond(t EnumerationTiming) int {
 	}
 	return result
 }
+
+// TimingToSweepsPerSecond returns the number of reverse DNS sweep queries performed each second.
+func TimingToSweepsPerSecond(t EnumerationTiming) int {
+	var result int
+
+	switch t {
+	case Paranoid:
+		result = 1
+	case Sneaky:
+		result = 3
+	case Polite:
+		result = 10
+	case Normal:
+		result = 33
+	case Aggressive:
+		result = 100
+	case Insane:
+		result = 1000
+	}
+	return result
+}
+
+// TimingToAlterationsPerSecond returns the number of word-based altered names sent each second.
+func TimingToAlterationsPerSecond(t EnumerationTiming) int {
+	var result int
+
+	switch t {
+	case Paranoid:
+		result = 3
+	case Sneaky:
+		result = 10
+	case Polite:
+		result = 33
+	case Normal:
+		result = 100
+	case Aggressive:
+		result = 333
+	case Insane:
+		result = 3333
+	}
+	return result
+}
@@ -4,14 +4,21 @@
 package core
 
 import (
+	"crypto/x509"
 	"fmt"
 	"net"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/OWASP/Amass/amass/utils"
 	"github.com/OWASP/Amass/amass/utils/viz"
 )
 
+// The maximum amount of time output is held for web services to be probed
+const maxHTTPProbeWait = time.Minute
+
 // Edge represents a graph edge.
 type Edge struct {
 	From, To int
@@ -47,27 +54,33 @@ func (n *Node) AddEdge(e int) {
 // Graph is the object for managing a network infrastructure link graph.
 type Graph struct {
 	sync.Mutex
-	Domains    map[string]*Node
-	Subdomains map[string]*Node
-	Addresses  map[string]*Node
-	PTRs       map[string]*Node
-	Netblocks  map[string]*Node
-	ASNs       map[int]*Node
-	Nodes      []*Node
-	curNodeIdx int
-	Edges      []*Edge
-	curEdgeIdx int
+	Domains      map[string]*Node
+	Subdomains   map[string]*Node
+	Addresses    map[string]*Node
+	PTRs         map[string]*Node
+	Netblocks    map[string]*Node
+	ASNs         map[int]*Node
+	Certificates map[string]*Node
+	certNames    map[string][]*Node
+	httpPending  map[string]time.Time
+	Nodes        []*Node
+	curNodeIdx   int
+	Edges        []*Edge
+	curEdgeIdx   int
 }
 
 // NewGraph returns an intialized Graph object.
 func NewGraph() *Graph {
 	return &Graph{
-		Domains:    make(map[string]*Node),
-		Subdomains: make(map[string]*Node),
-		Addresses:  make(map[string]*Node),
-		PTRs:       make(map[string]*Node),
-		Netblocks:  make(map[string]*Node),
-		ASNs:       make(map[int]*Node),
+		Domains:      make(map[string]*Node),
+		Subdomains:   make(map[string]*Node),
+		Addresses:    make(map[string]*Node),
+		PTRs:         make(map[string]*Node),
+		Netblocks:    make(map[string]*Node),
+		ASNs:         make(map[int]*Node),
+		Certificates: make(map[string]*Node),
+		certNames:    make(map[string][]*Node),
+		httpPending:  make(map[string]time.Time),
 	}
 }
 
@@ -213,6 +226,7 @@ func (g *Graph) VizData() ([]viz.Node, []viz.Edge) {
 
 	for idx, node := range g.Nodes {
 		var label, title, source string
+		var loc *viz.Location
 		t := node.Labels[0]
 
 		switch t {
@@ -227,6 +241,16 @@ func (g *Graph) VizData() ([]viz.Node, []viz.Edge) {
 		case "IPAddress":
 			label = node.Properties["addr"]
 			title = t + ": " + label
+			if l := geoInfoFromNode(node); l != nil {
+				loc = &viz.Location{
+					Country:   l.Country,
+					City:      l.City,
+					Located:   l.Located,
+					Latitude:  l.Latitude,
+					Longitude: l.Longitude,
+				}
+				title += ", Location: " + loc.String()
+			}
 		case "PTR":
 			label = node.Properties["name"]
 			title = t + ": " + label
@@ -244,14 +268,20 @@ func (g *Graph) VizData() ([]viz.Node, []viz.Edge) {
 		case "AS":
 			label = node.Properties["asn"]
 			title = t + ": " + label + ", Desc: " + node.Properties["desc"]
+		case "Certificate":
+			label = node.Properties["subject"]
+			title = t + ": " + label + ", Issuer: " + node.Properties["issuer"] +
+				", Expires: " + node.Properties["not_after"]
+			source = node.Properties["source"]
 		}
 
 		nodes = append(nodes, viz.Node{
-			ID:     idx,
-			Type:   t,
-			Label:  label,
-			Title:  title,
-			Source: source,
+			ID:       idx,
+			Type:     t,
+			Label:    label,
+			Title:    title,
+			Source:   source,
+			Location: loc,
 		})
 	}
 	return nodes, edges
@@ -276,6 +306,13 @@ func (g *Graph) insertSubdomain(name, domain, tag, source string) {
 			g.NewEdge(d.idx, s.idx, "ROOT_OF")
 		}
 	}
+	// Link the certificates that were inserted before the name was discovered
+	g.Lock()
+	certs := g.certNames[name]
+	g.Unlock()
+	for _, c := range certs {
+		g.NewEdge(c.idx, sub.idx, "CERT_FOR")
+	}
 }
 
 // InsertDomain implements the Amass data handler interface.
@@ -506,6 +543,129 @@ func (g *Graph) InsertMX(name, domain, target, tdomain, tag, source string) erro
 	return fmt.Errorf("Failed to insert the MX_TO edge between %s and %s", target, name)
 }
 
+// InsertSourceData implements the Amass data handler interface.
+func (g *Graph) InsertSourceData(name, domain string, addrs []string, first, last time.Time, evidence, tag, source string) error {
+	if name != domain {
+		g.insertSubdomain(name, domain, tag, source)
+	}
+
+	s := g.subdomainNode(name)
+	if s == nil {
+		return fmt.Errorf("Failed to obtain a reference to the node for %s", name)
+	}
+
+	// The findings of each data source are merged with those already recorded
+	s.Lock()
+	if evidence != "" {
+		var found bool
+		urls := strings.Fields(s.Properties["evidence"])
+		for _, u := range urls {
+			if u == evidence {
+				found = true
+				break
+			}
+		}
+		if !found {
+			s.Properties["evidence"] = strings.Join(append(urls, evidence), " ")
+		}
+	}
+	if !first.IsZero() {
+		f := first.UTC().Format(time.RFC3339)
+		if cur := s.Properties["first_seen"]; cur == "" || f < cur {
+			s.Properties["first_seen"] = f
+		}
+	}
+	if !last.IsZero() {
+		l := last.UTC().Format(time.RFC3339)
+		if cur := s.Properties["last_seen"]; cur == "" || l > cur {
+			s.Properties["last_seen"] = l
+		}
+	}
+	s.Unlock()
+
+	for _, addr := range addrs {
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			continue
+		}
+
+		// The data source observed the address, but it has not been resolved
+		a := g.insertAddress(addr, ip)
+		g.NewEdge(s.idx, a.idx, "SEEN_AT")
+	}
+	return nil
+}
+
+// insertAddress returns the node for the address, which is created when not already in the graph.
+func (g *Graph) insertAddress(addr string, ip net.IP) *Node {
+	a := g.addressNode(addr)
+	if a == nil {
+		a = g.NewNode("IPAddress")
+		a.Properties["addr"] = addr
+		a.Properties["type"] = "IPv6"
+		if ip.To4() != nil {
+			a.Properties["type"] = "IPv4"
+		}
+		g.Lock()
+		g.Addresses[addr] = a
+		g.Unlock()
+	}
+	return a
+}
+
+// InsertCertificate implements the Amass data handler interface.
+func (g *Graph) InsertCertificate(addr string, port int, cert *x509.Certificate, tag, source string) error {
+	ip := net.ParseIP(addr)
+	if ip == nil || cert == nil {
+		return fmt.Errorf("Failed to insert the certificate presented by %s", addr)
+	}
+
+	fingerprint := utils.CertificateFingerprint(cert)
+	g.Lock()
+	c, found := g.Certificates[fingerprint]
+	g.Unlock()
+	if !found {
+		c = g.NewNode("Certificate")
+		for k, v := range utils.CertificateProperties(cert) {
+			c.Properties[k] = v
+		}
+		c.Properties["names"] = strings.Join(utils.CertificateNames(cert), ",")
+		c.Properties["tag"] = tag
+		c.Properties["source"] = source
+		g.Lock()
+		g.Certificates[fingerprint] = c
+		g.Unlock()
+	}
+	// Track each address and port that presented the shared certificate
+	endpoint := net.JoinHostPort(addr, strconv.Itoa(port))
+	c.Lock()
+	endpoints := strings.Split(c.Properties["endpoints"], ",")
+	if c.Properties["endpoints"] == "" {
+		endpoints = []string{}
+	}
+	endpoints = utils.UniqueAppend(endpoints, endpoint)
+	c.Properties["endpoints"] = strings.Join(endpoints, ",")
+	c.Unlock()
+
+	a := g.insertAddress(addr, ip)
+	g.NewEdge(a.idx, c.idx, "PRESENTS_CERT")
+	if found {
+		return nil
+	}
+	// Link the certificate to the names it contains
+	for _, name := range utils.CertificateNames(cert) {
+		name = strings.ToLower(name)
+
+		g.Lock()
+		g.certNames[name] = append(g.certNames[name], c)
+		g.Unlock()
+		if s := g.subdomainNode(name); s != nil {
+			g.NewEdge(c.idx, s.idx, "CERT_FOR")
+		}
+	}
+	return nil
+}
+
 // InsertInfrastructure implements the Amass data handler interface.
 func (g *Graph) InsertInfrastructure(addr string, asn int, cidr *net.IPNet, desc string) error {
 	str := cidr.String()
@@ -545,6 +705,190 @@ func (g *Graph) InsertInfrastructure(addr string, asn int, cidr *net.IPNet, desc
 	return nil
 }
 
+// InsertCloudRange implements the Amass data handler interface.
+func (g *Graph) InsertCloudRange(addr string, cidr *net.IPNet, provider, service, region string) error {
+	a := g.addressNode(addr)
+	if a == nil {
+		return fmt.Errorf("Failed to obtain a reference to the node for %s", addr)
+	}
+
+	a.Lock()
+	defer a.Unlock()
+
+	a.Properties["cloud_provider"] = provider
+	a.Properties["cloud_service"] = service
+	a.Properties["cloud_region"] = region
+	a.Properties["cloud_cidr"] = cidr.String()
+	return nil
+}
+
+// InsertGeolocation implements the Amass data handler interface.
+func (g *Graph) InsertGeolocation(addr, country, city string, located bool, lat, lon float64) error {
+	a := g.addressNode(addr)
+	if a == nil {
+		return fmt.Errorf("Failed to obtain a reference to the node for %s", addr)
+	}
+
+	a.Lock()
+	defer a.Unlock()
+
+	a.Properties["geo_country"] = country
+	a.Properties["geo_city"] = city
+	// Records without coordinates, such as those of the Country databases, are not placed on the map
+	if !located {
+		delete(a.Properties, "geo_lat")
+		delete(a.Properties, "geo_lon")
+		return nil
+	}
+	a.Properties["geo_lat"] = strconv.FormatFloat(lat, 'f', -1, 64)
+	a.Properties["geo_lon"] = strconv.FormatFloat(lon, 'f', -1, 64)
+	return nil
+}
+
+// InsertRegistration implements the Amass data handler interface.
+func (g *Graph) InsertRegistration(target, handle, org, country string, abuse []string,
+	first, last string, registered, expires time.Time, tag, source string) error {
+	var n *Node
+
+	if asn, err := strconv.Atoi(strings.TrimPrefix(target, "AS")); err == nil && strings.HasPrefix(target, "AS") {
+		n = g.asnNode(asn)
+	} else if _, ipnet, err := net.ParseCIDR(target); err == nil {
+		n = g.netblockNode(ipnet.String())
+	} else if n = g.domainNode(target); n == nil {
+		n = g.subdomainNode(target)
+	}
+	if n == nil {
+		return fmt.Errorf("Failed to obtain a reference to the node for %s", target)
+	}
+
+	n.Lock()
+	defer n.Unlock()
+
+	n.Properties["rdap_handle"] = handle
+	n.Properties["rdap_org"] = org
+	n.Properties["rdap_country"] = country
+	n.Properties["rdap_abuse"] = strings.Join(abuse, ",")
+	if first != "" && last != "" {
+		n.Properties["rdap_range"] = first + "-" + last
+	}
+	if !registered.IsZero() {
+		n.Properties["rdap_registered"] = registered.UTC().Format(time.RFC3339)
+	}
+	if !expires.IsZero() {
+		n.Properties["rdap_expires"] = expires.UTC().Format(time.RFC3339)
+	}
+	n.Properties["rdap_source"] = source
+	return nil
+}
+
+// InsertHTTPService implements the Amass data handler interface.
+func (g *Graph) InsertHTTPService(name, domain, url string, status int, title, server string, redirects, techs []string, tag, source string) error {
+	s := g.subdomainNode(name)
+	if s == nil {
+		return fmt.Errorf("Failed to obtain a reference to the node for %s", name)
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	var services []string
+	if s.Properties["http_services"] != "" {
+		services = strings.Split(s.Properties["http_services"], " ")
+	}
+	s.Properties["http_services"] = strings.Join(utils.UniqueAppend(services, url), " ")
+	s.Properties["http_status:"+url] = strconv.Itoa(status)
+	s.Properties["http_title:"+url] = title
+	s.Properties["http_server:"+url] = server
+	s.Properties["http_redirects:"+url] = strings.Join(redirects, " ")
+	s.Properties["http_tech:"+url] = strings.Join(techs, ",")
+	return nil
+}
+
+// SetHTTPPending delays the output for the name until the web services have been probed.
+func (g *Graph) SetHTTPPending(name string) {
+	g.Lock()
+	defer g.Unlock()
+
+	g.httpPending[name] = time.Now()
+}
+
+// HTTPProbeDone allows the output for the name to be sent.
+func (g *Graph) HTTPProbeDone(name string) {
+	g.Lock()
+	defer g.Unlock()
+
+	delete(g.httpPending, name)
+}
+
+// ClearHTTPPending allows the output for all names to be sent, even when
+// the web services have not finished being probed.
+func (g *Graph) ClearHTTPPending() {
+	g.Lock()
+	defer g.Unlock()
+
+	g.httpPending = make(map[string]time.Time)
+}
+
+func (g *Graph) httpProbePending(name string) bool {
+	g.Lock()
+	defer g.Unlock()
+
+	start, found := g.httpPending[name]
+	// Do not hold the output for probes that never completed
+	return found && time.Since(start) < maxHTTPProbeWait
+}
+
+func httpServicesFromNode(sub *Node) []AmassHTTPInfo {
+	var services []AmassHTTPInfo
+
+	sub.Lock()
+	defer sub.Unlock()
+
+	if sub.Properties["http_services"] == "" {
+		return nil
+	}
+	for _, url := range strings.Split(sub.Properties["http_services"], " ") {
+		info := AmassHTTPInfo{
+			URL:    url,
+			Title:  sub.Properties["http_title:"+url],
+			Server: sub.Properties["http_server:"+url],
+		}
+		info.StatusCode, _ = strconv.Atoi(sub.Properties["http_status:"+url])
+		if r := sub.Properties["http_redirects:"+url]; r != "" {
+			info.Redirects = strings.Split(r, " ")
+		}
+		if t := sub.Properties["http_tech:"+url]; t != "" {
+			info.Technologies = strings.Split(t, ",")
+		}
+		services = append(services, info)
+	}
+	return services
+}
+
+// SubdomainNames returns the names of the subdomains within the domain provided.
+func (g *Graph) SubdomainNames(domain string) []string {
+	var names []string
+
+	d := g.domainNode(domain)
+	if d == nil {
+		return names
+	}
+
+	for _, idx := range d.Edges() {
+		g.Lock()
+		edge := g.Edges[idx]
+		n := g.Nodes[edge.To]
+		g.Unlock()
+
+		if edge.Label == "ROOT_OF" {
+			n.Lock()
+			names = append(names, n.Properties["name"])
+			n.Unlock()
+		}
+	}
+	return names
+}
+
 // GetNewOutput returns new findings within the enumeration Graph.
 func (g *Graph) GetNewOutput() []*AmassOutput {
 	var domains []string
@@ -613,8 +957,9 @@ func (g *Graph) findSubdomainOutput(domain *Node) []*AmassOutput {
 func (g *Graph) buildSubdomainOutput(sub *Node) *AmassOutput {
 	sub.Lock()
 	_, ok := sub.Properties["sent"]
+	name := sub.Properties["name"]
 	sub.Unlock()
-	if ok {
+	if ok || g.httpProbePending(name) {
 		return nil
 	}
 
@@ -646,6 +991,7 @@ func (g *Graph) buildSubdomainOutput(sub *Node) *AmassOutput {
 	if len(output.Addresses) == 0 {
 		return nil
 	}
+	output.HTTP = httpServicesFromNode(sub)
 	sub.Lock()
 	sub.Properties["sent"] = "yes"
 	sub.Unlock()
@@ -704,5 +1050,30 @@ func (g *Graph) obtainInfrastructureData(addr *Node) *AmassAddressInfo {
 
 	infr.ASN, _ = strconv.Atoi(as.Properties["asn"])
 	infr.Description = as.Properties["desc"]
+
+	addr.Lock()
+	infr.CloudProvider = addr.Properties["cloud_provider"]
+	infr.CloudService = addr.Properties["cloud_service"]
+	infr.CloudRegion = addr.Properties["cloud_region"]
+	infr.Location = geoInfoFromNode(addr)
+	addr.Unlock()
 	return infr
 }
+
+// geoInfoFromNode returns the location stored with the address node.
+func geoInfoFromNode(addr *Node) *AmassGeoInfo {
+	if _, found := addr.Properties["geo_country"]; !found {
+		return nil
+	}
+
+	loc := &AmassGeoInfo{
+		Country: addr.Properties["geo_country"],
+		City:    addr.Properties["geo_city"],
+	}
+	if _, found := addr.Properties["geo_lat"]; found {
+		loc.Located = true
+		loc.Latitude, _ = strconv.ParseFloat(addr.Properties["geo_lat"], 64)
+		loc.Longitude, _ = strconv.ParseFloat(addr.Properties["geo_lon"], 64)
+	}
+	return loc
+}
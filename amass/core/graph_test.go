@@ -0,0 +1,243 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package core
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestInsertCertificate(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "www.example.com"},
+		DNSNames:     []string{"www.example.com", "mail.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+
+	g := NewGraph()
+	g.InsertDomain("example.com", DNS, "Forward DNS")
+	g.InsertA("www.example.com", "example.com", "192.168.1.1", DNS, "Forward DNS")
+	if err := g.InsertCertificate("192.168.1.1", 443, cert, CERT, "Active Cert"); err != nil {
+		t.Fatalf("InsertCertificate failed: %v", err)
+	}
+	// The same certificate presented by another address is shared
+	if err := g.InsertCertificate("192.168.1.2", 25, cert, CERT, "Active Cert"); err != nil {
+		t.Fatalf("InsertCertificate failed: %v", err)
+	}
+	// Names discovered after the certificate are linked to it
+	g.InsertA("mail.example.com", "example.com", "192.168.1.2", DNS, "Forward DNS")
+
+	if len(g.Certificates) != 1 {
+		t.Fatalf("The graph contains %d certificates instead of 1", len(g.Certificates))
+	}
+
+	var c *Node
+	for _, node := range g.Certificates {
+		c = node
+	}
+	if c.Properties["serial"] != "2a" || c.Properties["self_signed"] != "true" || c.Properties["key_type"] != "ECDSA P-256" {
+		t.Errorf("The certificate node has unexpected properties: %v", c.Properties)
+	}
+	if c.Properties["endpoints"] != "192.168.1.1:443,192.168.1.2:25" {
+		t.Errorf("The certificate node has the endpoints %s", c.Properties["endpoints"])
+	}
+
+	counts := make(map[string]int)
+	for _, idx := range c.Edges() {
+		counts[g.Edges[idx].Label]++
+	}
+	if counts["PRESENTS_CERT"] != 2 || counts["CERT_FOR"] != 2 {
+		t.Errorf("The certificate node has unexpected edges: %v", counts)
+	}
+}
+
+func TestInsertRegistration(t *testing.T) {
+	_, cidr, _ := net.ParseCIDR("192.0.2.0/24")
+	registered := time.Date(2009, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	g := NewGraph()
+	g.InsertDomain("example.com", DNS, "Forward DNS")
+	g.InsertA("www.example.com", "example.com", "192.0.2.10", DNS, "Forward DNS")
+	g.InsertInfrastructure("192.0.2.10", 64500, cidr, "EXAMPLE-AS")
+
+	err := g.InsertRegistration("192.0.2.0/24", "NET-1", "Example Networks", "US",
+		[]string{"abuse@example.net"}, "192.0.2.0", "192.0.2.255", registered, time.Time{}, API, "RDAP")
+	if err != nil {
+		t.Fatalf("InsertRegistration failed for the netblock: %v", err)
+	}
+	nb := g.Netblocks["192.0.2.0/24"]
+	if nb.Properties["rdap_org"] != "Example Networks" || nb.Properties["rdap_range"] != "192.0.2.0-192.0.2.255" ||
+		nb.Properties["rdap_registered"] != "2009-01-02T03:04:05Z" {
+		t.Errorf("The netblock node has unexpected properties: %v", nb.Properties)
+	}
+
+	for _, target := range []string{"AS64500", "example.com"} {
+		if err := g.InsertRegistration(target, "H", "Org", "", nil, "", "", time.Time{}, time.Time{}, API, "RDAP"); err != nil {
+			t.Errorf("InsertRegistration failed for %s: %v", target, err)
+		}
+	}
+	if g.ASNs[64500].Properties["rdap_org"] != "Org" || g.Domains["example.com"].Properties["rdap_org"] != "Org" {
+		t.Errorf("The registration data was not stored with the ASN and domain nodes")
+	}
+	if err := g.InsertRegistration("AS1", "", "", "", nil, "", "", time.Time{}, time.Time{}, API, "RDAP"); err == nil {
+		t.Errorf("InsertRegistration did not return an error for a missing node")
+	}
+}
+
+func TestInsertCloudRange(t *testing.T) {
+	_, cidr, _ := net.ParseCIDR("192.0.2.0/24")
+	_, cloud, _ := net.ParseCIDR("192.0.2.0/26")
+
+	g := NewGraph()
+	g.InsertDomain("example.com", DNS, "Forward DNS")
+	g.InsertA("www.example.com", "example.com", "192.0.2.10", DNS, "Forward DNS")
+	if err := g.InsertCloudRange("192.0.2.10", cloud, "AWS", "EC2", "us-east-1"); err != nil {
+		t.Fatalf("InsertCloudRange failed: %v", err)
+	}
+	g.InsertInfrastructure("192.0.2.10", 64500, cidr, "EXAMPLE-AS")
+
+	output := g.GetNewOutput()
+	if len(output) != 1 || len(output[0].Addresses) != 1 {
+		t.Fatalf("GetNewOutput returned %v", output)
+	}
+	if a := output[0].Addresses[0]; a.CloudProvider != "AWS" || a.CloudService != "EC2" || a.CloudRegion != "us-east-1" {
+		t.Errorf("The output has unexpected cloud data: %+v", a)
+	}
+	if err := g.InsertCloudRange("198.51.100.1", cloud, "AWS", "", ""); err == nil {
+		t.Errorf("InsertCloudRange did not return an error for a missing node")
+	}
+}
+
+func TestInsertGeolocation(t *testing.T) {
+	_, cidr, _ := net.ParseCIDR("192.0.2.0/24")
+
+	g := NewGraph()
+	g.InsertDomain("example.com", DNS, "Forward DNS")
+	g.InsertA("www.example.com", "example.com", "192.0.2.10", DNS, "Forward DNS")
+	if err := g.InsertGeolocation("192.0.2.10", "DE", "Frankfurt am Main", true, 50.1188, 8.6843); err != nil {
+		t.Fatalf("InsertGeolocation failed: %v", err)
+	}
+	g.InsertInfrastructure("192.0.2.10", 64500, cidr, "EXAMPLE-AS")
+
+	output := g.GetNewOutput()
+	if len(output) != 1 || len(output[0].Addresses) != 1 {
+		t.Fatalf("GetNewOutput returned %v", output)
+	}
+	if l := output[0].Addresses[0].Location; l == nil || l.Country != "DE" || l.City != "Frankfurt am Main" ||
+		!l.Located || l.Latitude != 50.1188 || l.Longitude != 8.6843 {
+		t.Errorf("The output has the unexpected location %+v", l)
+	}
+
+	nodes, _ := g.VizData()
+	for _, n := range nodes {
+		if n.Type == "IPAddress" && (n.Location == nil || n.Title != "IPAddress: 192.0.2.10, Location: Frankfurt am Main, DE") {
+			t.Errorf("The address node has the unexpected viz data %+v", n)
+		}
+	}
+	if err := g.InsertGeolocation("198.51.100.1", "US", "", false, 0, 0); err == nil {
+		t.Errorf("InsertGeolocation did not return an error for a missing node")
+	}
+}
+
+func TestInsertGeolocationCountryOnly(t *testing.T) {
+	_, cidr, _ := net.ParseCIDR("192.0.2.0/24")
+
+	g := NewGraph()
+	g.InsertDomain("example.com", DNS, "Forward DNS")
+	g.InsertA("www.example.com", "example.com", "192.0.2.10", DNS, "Forward DNS")
+	// Records of the Country databases do not provide coordinates
+	if err := g.InsertGeolocation("192.0.2.10", "NL", "", false, 0, 0); err != nil {
+		t.Fatalf("InsertGeolocation failed: %v", err)
+	}
+	g.InsertInfrastructure("192.0.2.10", 64500, cidr, "EXAMPLE-AS")
+
+	a := g.addressNode("192.0.2.10")
+	if _, found := a.Properties["geo_lat"]; found {
+		t.Errorf("The address node has the latitude %s without coordinates", a.Properties["geo_lat"])
+	}
+
+	output := g.GetNewOutput()
+	if len(output) != 1 || len(output[0].Addresses) != 1 {
+		t.Fatalf("GetNewOutput returned %v", output)
+	}
+	if l := output[0].Addresses[0].Location; l == nil || l.Country != "NL" || l.Located {
+		t.Errorf("The output has the unexpected location %+v", l)
+	}
+
+	nodes, _ := g.VizData()
+	for _, n := range nodes {
+		if n.Type == "IPAddress" && (n.Location == nil || n.Location.Located) {
+			t.Errorf("The address node has the unexpected viz data %+v", n)
+		}
+	}
+}
+
+func TestClearHTTPPending(t *testing.T) {
+	g := NewGraph()
+	g.InsertDomain("example.com", DNS, "Forward DNS")
+	g.InsertA("www.example.com", "example.com", "192.168.1.1", DNS, "Forward DNS")
+	_, cidr, _ := net.ParseCIDR("192.168.1.0/24")
+	g.InsertInfrastructure("192.168.1.1", 64512, cidr, "Private Network")
+	g.SetHTTPPending("www.example.com")
+
+	for _, o := range g.GetNewOutput() {
+		if o.Name == "www.example.com" {
+			t.Fatal("GetNewOutput returned a name that is waiting on a web service probe")
+		}
+	}
+
+	g.ClearHTTPPending()
+	var found bool
+	for _, o := range g.GetNewOutput() {
+		if o.Name == "www.example.com" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("GetNewOutput did not return the name after the pending probes were cleared")
+	}
+}
+
+func TestInsertSourceDataMerge(t *testing.T) {
+	early := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	late := time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	g := NewGraph()
+	g.InsertDomain("example.com", DNS, "Forward DNS")
+	g.InsertSourceData("www.example.com", "example.com", nil, late, late, "https://a.example.org/", API, "SourceA")
+	g.InsertSourceData("www.example.com", "example.com", nil, early, early, "https://b.example.org/", API, "SourceB")
+	g.InsertSourceData("www.example.com", "example.com", nil, time.Time{}, time.Time{}, "https://a.example.org/", API, "SourceA")
+
+	s := g.subdomainNode("www.example.com")
+	if s == nil {
+		t.Fatal("The subdomain node was not inserted")
+	}
+	if e := s.Properties["evidence"]; e != "https://a.example.org/ https://b.example.org/" {
+		t.Errorf("The subdomain node has the evidence %q", e)
+	}
+	if f := s.Properties["first_seen"]; f != early.Format(time.RFC3339) {
+		t.Errorf("The subdomain node was first seen at %s", f)
+	}
+	if l := s.Properties["last_seen"]; l != late.Format(time.RFC3339) {
+		t.Errorf("The subdomain node was last seen at %s", l)
+	}
+}
@@ -3,7 +3,11 @@
 
 package core
 
-import "net"
+import (
+	"crypto/x509"
+	"net"
+	"time"
+)
 
 // DNSAnswer is the type used by Amass to represent a DNS record.
 type DNSAnswer struct {
@@ -15,11 +19,35 @@ type DNSAnswer struct {
 
 // AmassRequest contains data obtained throughout AmassService processing
 type AmassRequest struct {
-	Name    string
-	Domain  string
-	Records []DNSAnswer
-	Tag     string
-	Source  string
+	Name     string
+	Domain   string
+	Records  []DNSAnswer
+	Tag      string
+	Source   string
+	Evidence *SourceEvidence
+}
+
+// SourceEvidence contains the supporting data provided by a data source for a discovered name.
+type SourceEvidence struct {
+	Addresses []string
+	FirstSeen time.Time
+	LastSeen  time.Time
+	URL       string
+}
+
+// CertificateData contains a certificate presented by a network service.
+type CertificateData struct {
+	Address     string
+	Port        int
+	ServerName  string
+	Certificate *x509.Certificate
+}
+
+// HTTPProbeResult contains the web services found for an enumerated DNS name.
+type HTTPProbeResult struct {
+	Name     string
+	Domain   string
+	Services []AmassHTTPInfo
 }
 
 // AmassOutput contains all the output data for an enumerated DNS name.
@@ -27,8 +55,11 @@ type AmassOutput struct {
 	Name      string
 	Domain    string
 	Addresses []AmassAddressInfo
+	HTTP      []AmassHTTPInfo
 	Tag       string
 	Source    string
+	// Does the name only resolve to addresses outside of the address scope?
+	OutOfScope bool
 }
 
 // AmassAddressInfo stores all network addressing info for the AmassOutput type.
@@ -37,4 +68,30 @@ type AmassAddressInfo struct {
 	Netblock    *net.IPNet
 	ASN         int
 	Description string
+	// The cloud or CDN provider range containing the address, when known
+	CloudProvider string
+	CloudService  string
+	CloudRegion   string
+	// The location provided by the geolocation database, when known
+	Location *AmassGeoInfo
+}
+
+// AmassGeoInfo stores the geolocation of an address for the AmassAddressInfo type.
+type AmassGeoInfo struct {
+	Country string
+	City    string
+	// Located is false when the coordinates of the address are not known
+	Located   bool
+	Latitude  float64
+	Longitude float64
+}
+
+// AmassHTTPInfo stores the details of a web service for the AmassOutput type.
+type AmassHTTPInfo struct {
+	URL          string
+	StatusCode   int
+	Title        string
+	Server       string
+	Redirects    []string
+	Technologies []string
 }
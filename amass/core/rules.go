@@ -0,0 +1,151 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Rule matches DNS names using one of the following pattern forms:
+//
+//	example.com       the name and all subdomains of the name (label boundaries are respected)
+//	=example.com      only the exact name
+//	*.dev.example.com glob pattern where * and ? do not match across labels
+//	re:^db[0-9]+\.    regular expression matched against the entire name
+//
+// Patterns starting with ! are allow rules that take precedence over all other rules.
+type Rule struct {
+	Pattern string
+	Allow   bool
+
+	exact  bool
+	name   string
+	re     *regexp.Regexp
+	suffix bool
+}
+
+// ParseRule returns the Rule described by the pattern parameter.
+func ParseRule(pattern string) (*Rule, error) {
+	r := &Rule{Pattern: pattern}
+
+	p := strings.TrimSpace(pattern)
+	if strings.HasPrefix(p, "!") {
+		r.Allow = true
+		p = p[1:]
+	}
+
+	var err error
+	switch {
+	case strings.HasPrefix(p, "re:"):
+		r.re, err = regexp.Compile(p[3:])
+		if err != nil {
+			return nil, fmt.Errorf("The rule %s has an invalid regular expression: %v", pattern, err)
+		}
+	case strings.HasPrefix(p, "="):
+		r.exact = true
+		r.name = strings.ToLower(p[1:])
+	case strings.ContainsAny(p, "*?"):
+		r.re = globToRegexp(strings.ToLower(p))
+		r.suffix = true
+	default:
+		r.name = strings.ToLower(p)
+		r.suffix = true
+	}
+
+	if r.re == nil && r.name == "" {
+		return nil, fmt.Errorf("The rule %s does not provide a pattern", pattern)
+	}
+	return r, nil
+}
+
+// Match returns true when the DNS name provided matches the Rule.
+func (r *Rule) Match(name string) bool {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+
+	if r.exact {
+		return name == r.name
+	}
+	if !r.suffix {
+		return r.re.MatchString(name)
+	}
+	// Check the name and each of the parent names for a match
+	for sub := name; sub != ""; {
+		if r.re != nil && r.re.MatchString(sub) {
+			return true
+		} else if r.re == nil && sub == r.name {
+			return true
+		}
+
+		idx := strings.Index(sub, ".")
+		if idx == -1 {
+			break
+		}
+		sub = sub[idx+1:]
+	}
+	return false
+}
+
+func globToRegexp(glob string) *regexp.Regexp {
+	var expr string
+
+	for _, c := range glob {
+		switch c {
+		case '*':
+			expr += "[^.]*"
+		case '?':
+			expr += "[^.]"
+		default:
+			expr += regexp.QuoteMeta(string(c))
+		}
+	}
+	return regexp.MustCompile("^" + expr + "$")
+}
+
+// RuleSet evaluates a collection of rules with allow rules having precedence.
+type RuleSet struct {
+	rules []*Rule
+}
+
+// NewRuleSet returns a RuleSet containing the rules parsed from the patterns.
+func NewRuleSet(patterns []string) (*RuleSet, error) {
+	rs := new(RuleSet)
+
+	for _, p := range patterns {
+		if strings.TrimSpace(p) == "" {
+			continue
+		}
+
+		r, err := ParseRule(p)
+		if err != nil {
+			return nil, err
+		}
+		rs.rules = append(rs.rules, r)
+	}
+	return rs, nil
+}
+
+// Match returns true when the DNS name is matched by a rule and not by any allow rule.
+// The reason returned identifies the rule responsible for the result.
+func (rs *RuleSet) Match(name string) (bool, string) {
+	var deny *Rule
+
+	for _, r := range rs.rules {
+		if !r.Match(name) {
+			continue
+		}
+
+		if r.Allow {
+			return false, fmt.Sprintf("%s is allowed by the rule %s", name, r.Pattern)
+		} else if deny == nil {
+			deny = r
+		}
+	}
+
+	if deny == nil {
+		return false, ""
+	}
+	return true, fmt.Sprintf("%s is blacklisted by the rule %s", name, deny.Pattern)
+}
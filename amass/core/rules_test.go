@@ -0,0 +1,74 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package core
+
+import (
+	"testing"
+)
+
+func TestRuleSetMatch(t *testing.T) {
+	rules, err := NewRuleSet([]string{
+		"dev.example.com",
+		"=prod.example.com",
+		"*.stage.example.com",
+		"re:^db[0-9]+\\.example\\.com$",
+		"!www.dev.example.com",
+	})
+	if err != nil {
+		t.Fatalf("NewRuleSet failed: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		blacklisted bool
+	}{
+		{"dev.example.com", true},
+		{"api.dev.example.com", true},
+		{"mydev.example.com", false},
+		{"www.dev.example.com", false},
+		{"prod.example.com", true},
+		{"api.prod.example.com", false},
+		{"web.stage.example.com", true},
+		{"a.web.stage.example.com", true},
+		{"stage.example.com", false},
+		{"db12.example.com", true},
+		{"a.db12.example.com", false},
+		{"example.com", false},
+	}
+
+	for _, test := range tests {
+		if result, reason := rules.Match(test.name); result != test.blacklisted {
+			t.Errorf("%s: returned %t instead of %t (%s)", test.name, result, test.blacklisted, reason)
+		}
+	}
+}
+
+func TestParseRuleInvalid(t *testing.T) {
+	for _, p := range []string{"re:[", "!", "="} {
+		if _, err := ParseRule(p); err == nil {
+			t.Errorf("ParseRule did not return an error for %s", p)
+		}
+	}
+}
+
+func TestConfigBlacklistRules(t *testing.T) {
+	config := &AmassConfig{Blacklist: []string{"dev.example.com"}}
+
+	if !config.Blacklisted("api.dev.example.com") || config.Blacklisted("api.example.com") {
+		t.Fatal("The config blacklist did not match the names as expected")
+	}
+	// The rules are built again once the blacklist has been changed
+	config.Blacklist = append(config.Blacklist, "=api.example.com")
+	if !config.Blacklisted("api.example.com") {
+		t.Error("The rules were not built again after the blacklist was changed")
+	}
+
+	config.Blacklist = []string{"re:["}
+	if _, err := config.BlacklistRules(); err == nil {
+		t.Error("BlacklistRules did not return an error for the invalid pattern")
+	}
+	if !config.Blacklisted("www.example.com") {
+		t.Error("Names were not blacklisted when the rules could not be parsed")
+	}
+}
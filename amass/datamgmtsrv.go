@@ -4,8 +4,11 @@
 package amass
 
 import (
+	"net"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/OWASP/Amass/amass/core"
@@ -20,15 +23,23 @@ import (
 type DataManagerService struct {
 	core.BaseAmassService
 
-	bus      evbus.Bus
-	Handlers []handlers.DataHandler
-	domains  []string
+	bus        evbus.Bus
+	Handlers   []handlers.DataHandler
+	domains    []string
+	httpFilter *utils.StringFilter
+	rdapFilter *utils.StringFilter
+	// The RDAP requests that have not completed
+	rdapWait sync.WaitGroup
 }
 
 // NewDataManagerService requires the enumeration configuration and event bus as parameters.
 // The object returned is initialized, but has not yet been started.
 func NewDataManagerService(config *core.AmassConfig, bus evbus.Bus) *DataManagerService {
-	dms := &DataManagerService{bus: bus}
+	dms := &DataManagerService{
+		bus:        bus,
+		httpFilter: utils.NewStringFilter(),
+		rdapFilter: utils.NewStringFilter(),
+	}
 
 	dms.BaseAmassService = *core.NewBaseAmassService("Data Manager Service", config, dms)
 	return dms
@@ -39,6 +50,9 @@ func (dms *DataManagerService) OnStart() error {
 	dms.BaseAmassService.OnStart()
 
 	dms.bus.SubscribeAsync(core.CHECKED, dms.SendRequest, false)
+	dms.bus.SubscribeAsync(core.NEWCERT, dms.insertCertificate, false)
+	dms.bus.SubscribeAsync(core.HTTPINFO, dms.insertHTTPServices, false)
+	dms.bus.SubscribeAsync(core.SOURCEDATA, dms.insertDuplicateSourceData, false)
 
 	dms.Handlers = append(dms.Handlers, dms.Config().Graph())
 	if dms.Config().DataOptsWriter != nil {
@@ -64,6 +78,16 @@ func (dms *DataManagerService) OnStop() error {
 	dms.BaseAmassService.OnStop()
 
 	dms.bus.Unsubscribe(core.CHECKED, dms.SendRequest)
+	dms.bus.Unsubscribe(core.NEWCERT, dms.insertCertificate)
+	dms.bus.Unsubscribe(core.HTTPINFO, dms.insertHTTPServices)
+	dms.bus.Unsubscribe(core.SOURCEDATA, dms.insertDuplicateSourceData)
+	// The registration data is stored before the remaining output is sent
+	dms.rdapWait.Wait()
+	// Flush the output that is still being held for web service probes
+	dms.Config().Graph().ClearHTTPPending()
+	if out := dms.Config().Graph().GetNewOutput(); len(out) > 0 {
+		dms.sendOutput(out)
+	}
 	return nil
 }
 
@@ -105,16 +129,37 @@ func (dms *DataManagerService) sendOutput(output []*core.AmassOutput) {
 	dms.SetActive()
 	for _, o := range output {
 		if dms.Config().IsDomainInScope(o.Name) {
+			o.OutOfScope = dms.outOfScope(o)
 			dms.bus.Publish(core.OUTPUT, o)
 		}
 	}
 }
 
+// outOfScope returns true when none of the addresses of the output are within the address scope.
+func (dms *DataManagerService) outOfScope(o *core.AmassOutput) bool {
+	if len(o.Addresses) == 0 {
+		return false
+	}
+
+	for _, a := range o.Addresses {
+		if dms.Config().IsAddressInScope(a.Address.String()) {
+			return false
+		}
+	}
+	return true
+}
+
 func (dms *DataManagerService) manageData(req *core.AmassRequest) {
 	req.Name = strings.ToLower(req.Name)
 	req.Domain = strings.ToLower(req.Domain)
+	if dms.blacklisted(req.Name) {
+		return
+	}
 
 	dms.insertDomain(req.Domain)
+	if req.Evidence != nil {
+		dms.insertSourceData(req)
+	}
 	for i, r := range req.Records {
 		r.Name = strings.ToLower(r.Name)
 		r.Data = strings.ToLower(r.Data)
@@ -142,7 +187,18 @@ func (dms *DataManagerService) manageData(req *core.AmassRequest) {
 	}
 }
 
+func (dms *DataManagerService) blacklisted(name string) bool {
+	blacklisted, reason := dms.Config().BlacklistReason(name)
+	if blacklisted {
+		dms.Config().Log.Print(reason)
+	}
+	return blacklisted
+}
+
 func (dms *DataManagerService) publishRequest(req *core.AmassRequest) {
+	if dms.blacklisted(req.Name) {
+		return
+	}
 	dms.Config().MaxFlow.Acquire(1)
 	dms.bus.Publish(core.NEWNAME, req)
 }
@@ -172,6 +228,11 @@ func (dms *DataManagerService) insertDomain(domain string) {
 			dms.Config().Log.Printf("%s failed to insert domain: %v", handler, err)
 		}
 	}
+	if dms.Config().RDAP && !dms.rdapFilter.Duplicate(domain) {
+		dms.requestRegistration(domain, func() (*RDAPRecord, error) {
+			return RDAPDomainRequest(domain)
+		})
+	}
 	go dms.publishRequest(&core.AmassRequest{
 		Name:   domain,
 		Domain: domain,
@@ -180,6 +241,32 @@ func (dms *DataManagerService) insertDomain(domain string) {
 	})
 }
 
+func (dms *DataManagerService) insertSourceData(req *core.AmassRequest) {
+	e := req.Evidence
+	for _, handler := range dms.Handlers {
+		err := handler.InsertSourceData(req.Name, req.Domain, e.Addresses,
+			e.FirstSeen, e.LastSeen, e.URL, req.Tag, req.Source)
+		if err != nil {
+			dms.Config().Log.Printf("%s failed to insert data source findings: %v", handler, err)
+		}
+	}
+}
+
+// insertDuplicateSourceData records the findings of a data source for a name
+// that has already been resolved using the request of another data source.
+func (dms *DataManagerService) insertDuplicateSourceData(req *core.AmassRequest) {
+	dms.SetActive()
+
+	req.Name = strings.ToLower(req.Name)
+	req.Domain = strings.ToLower(req.Domain)
+	if dms.blacklisted(req.Name) {
+		return
+	}
+
+	dms.insertDomain(req.Domain)
+	dms.insertSourceData(req)
+}
+
 func (dms *DataManagerService) insertCNAME(req *core.AmassRequest, recidx int) {
 	target := removeLastDot(req.Records[recidx].Data)
 	if target == "" {
@@ -215,10 +302,15 @@ func (dms *DataManagerService) insertA(req *core.AmassRequest, recidx int) {
 		}
 	}
 	dms.insertInfrastructure(addr)
+	if !dms.Config().IsAddressInScope(addr) {
+		dms.Config().Log.Printf("%s resolved to %s outside of the address scope", req.Name, addr)
+		return
+	}
 	// Check if active certificate access should be used on this address
 	if dms.Config().Active && dms.Config().IsDomainInScope(req.Name) {
-		dms.bus.Publish(core.ACTIVECERT, addr)
+		dms.bus.Publish(core.ACTIVECERT, addr, req.Name)
 	}
+	dms.probeHTTP(req)
 }
 
 func (dms *DataManagerService) insertAAAA(req *core.AmassRequest, recidx int) {
@@ -232,10 +324,50 @@ func (dms *DataManagerService) insertAAAA(req *core.AmassRequest, recidx int) {
 		}
 	}
 	dms.insertInfrastructure(addr)
+	if !dms.Config().IsAddressInScope(addr) {
+		dms.Config().Log.Printf("%s resolved to %s outside of the address scope", req.Name, addr)
+		return
+	}
 	// Check if active certificate access should be used on this address
 	if dms.Config().Active && dms.Config().IsDomainInScope(req.Name) {
-		dms.bus.Publish(core.ACTIVECERT, addr)
+		dms.bus.Publish(core.ACTIVECERT, addr, req.Name)
+	}
+	dms.probeHTTP(req)
+}
+
+func (dms *DataManagerService) insertCertificate(c *core.CertificateData) {
+	dms.SetActive()
+	for _, handler := range dms.Handlers {
+		err := handler.InsertCertificate(c.Address, c.Port, c.Certificate, core.CERT, "Active Cert")
+		if err != nil {
+			dms.Config().Log.Printf("%s failed to insert the certificate: %v", handler, err)
+		}
+	}
+}
+
+// probeHTTP requests that the web services on the name be fingerprinted, while the
+// output for the name is held until the results have been inserted.
+func (dms *DataManagerService) probeHTTP(req *core.AmassRequest) {
+	if !dms.Config().HTTPProbe || !dms.Config().IsDomainInScope(req.Name) || dms.httpFilter.Duplicate(req.Name) {
+		return
+	}
+
+	dms.Config().Graph().SetHTTPPending(req.Name)
+	dms.bus.Publish(core.HTTPPROBE, req.Name, req.Domain)
+}
+
+func (dms *DataManagerService) insertHTTPServices(result *core.HTTPProbeResult) {
+	dms.SetActive()
+	for _, s := range result.Services {
+		for _, handler := range dms.Handlers {
+			err := handler.InsertHTTPService(result.Name, result.Domain, s.URL, s.StatusCode,
+				s.Title, s.Server, s.Redirects, s.Technologies, core.DNS, "HTTP Probe")
+			if err != nil {
+				dms.Config().Log.Printf("%s failed to insert the web service: %v", handler, err)
+			}
+		}
 	}
+	dms.Config().Graph().HTTPProbeDone(result.Name)
 }
 
 func (dms *DataManagerService) insertPTR(req *core.AmassRequest, recidx int) {
@@ -244,7 +376,7 @@ func (dms *DataManagerService) insertPTR(req *core.AmassRequest, recidx int) {
 		return
 	}
 	domain := dms.Config().WhichDomain(target)
-	if domain == "" {
+	if domain == "" || dms.blacklisted(target) {
 		return
 	}
 	dms.insertDomain(domain)
@@ -346,6 +478,9 @@ func (dms *DataManagerService) insertSPF(req *core.AmassRequest, recidx int) {
 func (dms *DataManagerService) findNamesAndAddresses(data string) {
 	ipre := regexp.MustCompile(utils.IPv4RE)
 	for _, ip := range ipre.FindAllString(data, -1) {
+		if !dms.Config().IsAddressInScope(ip) {
+			continue
+		}
 		if _, cidr, _, err := IPRequest(ip); err == nil {
 			dms.bus.Publish(core.DNSSWEEP, ip, cidr)
 		} else {
@@ -374,17 +509,82 @@ func (dms *DataManagerService) insertInfrastructure(addr string) {
 	asn, cidr, desc, err := IPRequest(addr)
 	if err != nil {
 		dms.Config().Log.Printf("%v", err)
+		// Addresses without netblock data are swept within their subnet
+		if subnet := utils.AddressSubnet(net.ParseIP(addr)); subnet != nil && dms.Config().IsAddressInScope(addr) {
+			dms.bus.Publish(core.DNSSWEEP, addr, subnet)
+		}
 		return
 	}
 
 	// Request the reverse DNS sweep for the addr
-	dms.bus.Publish(core.DNSSWEEP, addr, cidr)
+	if dms.Config().IsAddressInScope(addr) {
+		dms.bus.Publish(core.DNSSWEEP, addr, cidr)
+	}
 
+	// The cloud provider labels and location are stored before the infrastructure data causes the output
+	cr := CloudRequest(addr)
+	loc := GeoRequest(addr)
 	for _, handler := range dms.Handlers {
+		if cr != nil {
+			if err := handler.InsertCloudRange(addr, cr.CIDR, cr.Provider, cr.Service, cr.Region); err != nil {
+				dms.Config().Log.Printf("%s failed to insert the cloud range data: %v", handler, err)
+			}
+		}
+		if loc != nil {
+			err := handler.InsertGeolocation(addr, loc.Country, loc.City, loc.Located, loc.Latitude, loc.Longitude)
+			if err != nil {
+				dms.Config().Log.Printf("%s failed to insert the geolocation data: %v", handler, err)
+			}
+		}
 		if err := handler.InsertInfrastructure(addr, asn, cidr, desc); err != nil {
 			dms.Config().Log.Printf("%s failed to insert infrastructure data: %v", handler, err)
 		}
 	}
+	if !dms.Config().RDAP {
+		return
+	}
+	if nb := cidr.String(); !dms.rdapFilter.Duplicate(nb) {
+		dms.requestRegistration(nb, func() (*RDAPRecord, error) {
+			return RDAPIPRequest(addr)
+		})
+	}
+	if as := "AS" + strconv.Itoa(asn); !dms.rdapFilter.Duplicate(as) {
+		dms.requestRegistration(as, func() (*RDAPRecord, error) {
+			return RDAPASNRequest(asn)
+		})
+	}
+}
+
+// requestRegistration performs the RDAP request in a goroutine that is waited for when the service stops.
+func (dms *DataManagerService) requestRegistration(target string, request func() (*RDAPRecord, error)) {
+	dms.rdapWait.Add(1)
+	go dms.insertRegistration(target, request)
+}
+
+// insertRegistration stores the registration data obtained by the RDAP request with the target node.
+func (dms *DataManagerService) insertRegistration(target string, request func() (*RDAPRecord, error)) {
+	defer dms.rdapWait.Done()
+
+	dms.SetActive()
+	record, err := request()
+	// The request can take longer than the time an idle enumeration waits before stopping
+	dms.SetActive()
+	if err != nil {
+		dms.Config().Log.Printf("%v", err)
+		return
+	}
+
+	first, last := record.StartAddress, record.EndAddress
+	if record.StartASN != 0 {
+		first, last = "AS"+strconv.Itoa(record.StartASN), "AS"+strconv.Itoa(record.EndASN)
+	}
+	for _, handler := range dms.Handlers {
+		err := handler.InsertRegistration(target, record.Handle, record.Organization, record.Country,
+			record.AbuseEmails, first, last, record.Registration, record.Expiration, core.API, "RDAP")
+		if err != nil {
+			dms.Config().Log.Printf("%s failed to insert the registration data: %v", handler, err)
+		}
+	}
 }
 
 func removeLastDot(name string) string {
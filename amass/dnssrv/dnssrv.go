@@ -74,6 +74,19 @@ type DNSService struct {
 	wildcardRequests chan wildcardRequest
 
 	cidrBlacklist []*net.IPNet
+
+	// Ensures we do not sweep addresses or ip6.arpa zones more than once
+	sweepFilter *utils.StringFilter
+
+	// Paces the reverse DNS sweeps separately from forward resolution
+	sweepLimiter *utils.RateLimiter
+
+	// The netblocks that have been swept in their entirety
+	sweptNetblocks []*net.IPNet
+
+	// The lookups performed by the reverse DNS sweeps, which are replaced during testing
+	reverse func(addr string) (string, string, error)
+	resolve func(name, qtype string) ([]core.DNSAnswer, error)
 }
 
 // NewDNSService requires the enumeration configuration and event bus as parameters.
@@ -84,6 +97,9 @@ func NewDNSService(config *core.AmassConfig, bus evbus.Bus) *DNSService {
 		filter:           utils.NewStringFilter(),
 		wildcards:        make(map[string]*wildcard),
 		wildcardRequests: make(chan wildcardRequest),
+		sweepFilter:      utils.NewStringFilter(),
+		reverse:          Reverse,
+		resolve:          Resolve,
 	}
 
 	for _, n := range badSubnets {
@@ -100,11 +116,18 @@ func NewDNSService(config *core.AmassConfig, bus evbus.Bus) *DNSService {
 func (ds *DNSService) OnStart() error {
 	ds.BaseAmassService.OnStart()
 
+	rate := ds.Config().SweepRate
+	if rate == 0 {
+		rate = core.TimingToSweepsPerSecond(ds.Config().Timing)
+	}
+	ds.sweepLimiter = utils.NewRateLimiter(rate)
+
 	ds.bus.SubscribeAsync(core.NEWSUB, ds.newSubdomain, false)
 	ds.bus.SubscribeAsync(core.DNSQUERY, ds.addRequest, false)
 	ds.bus.SubscribeAsync(core.DNSSWEEP, ds.reverseDNSSweep, false)
 	go ds.processRequests()
 	go ds.processWildcardRequests()
+	go ds.sweepScopeNetblocks()
 	return nil
 }
 
@@ -115,11 +138,21 @@ func (ds *DNSService) OnStop() error {
 	ds.bus.Unsubscribe(core.NEWSUB, ds.newSubdomain)
 	ds.bus.Unsubscribe(core.DNSQUERY, ds.addRequest)
 	ds.bus.Unsubscribe(core.DNSSWEEP, ds.reverseDNSSweep)
+	ds.sweepLimiter.Stop()
 	return nil
 }
 
 func (ds *DNSService) addRequest(req *core.AmassRequest) {
-	if ds.filter.Duplicate(req.Name) || ds.Config().Blacklisted(req.Name) {
+	if ds.filter.Duplicate(req.Name) {
+		// The findings of another data source are still recorded for the name
+		if req.Evidence != nil {
+			ds.bus.Publish(core.SOURCEDATA, req)
+		}
+		ds.bus.Publish(core.RELEASEREQ)
+		return
+	}
+	if blacklisted, reason := ds.Config().BlacklistReason(req.Name); blacklisted {
+		ds.Config().Log.Print(reason)
 		ds.bus.Publish(core.RELEASEREQ)
 		return
 	}
@@ -189,6 +222,14 @@ func (ds *DNSService) performRequest(req *core.AmassRequest) {
 
 func (ds *DNSService) goodDNSRecords(records []core.DNSAnswer) bool {
 	for _, r := range records {
+		if r.Type != int(dns.TypeA) && r.Type != int(dns.TypeAAAA) {
+			continue
+		}
+		// Names resolving to excluded addresses are removed from the enumeration
+		if ds.Config().IsAddressExcluded(r.Data) {
+			ds.Config().Log.Printf("%s resolved to the excluded address %s", r.Name, r.Data)
+			return false
+		}
 		if r.Type != int(dns.TypeA) {
 			continue
 		}
@@ -302,52 +343,6 @@ func (ds *DNSService) queryServiceNames(subdomain, domain string) {
 	}
 }
 
-func (ds *DNSService) reverseDNSSweep(addr string, cidr *net.IPNet) {
-	var ips []net.IP
-
-	// Get information about nearby IP addresses
-	if ds.Config().Active {
-		ips = utils.CIDRSubset(cidr, addr, 500)
-	} else {
-		ips = utils.CIDRSubset(cidr, addr, 250)
-	}
-
-	for _, ip := range ips {
-		a := ip.String()
-		if ds.filter.Duplicate(a) {
-			continue
-		}
-		core.MaxConnections.Acquire(1)
-		go ds.reverseDNSRoutine(a)
-	}
-}
-
-func (ds *DNSService) reverseDNSRoutine(ip string) {
-	defer core.MaxConnections.Release(1)
-
-	ds.SetActive()
-	ptr, answer, err := Reverse(ip)
-	if err != nil {
-		return
-	}
-	domain := ds.Config().WhichDomain(answer)
-	if domain == "" {
-		return
-	}
-	ds.sendResolved(&core.AmassRequest{
-		Name:   ptr,
-		Domain: domain,
-		Records: []core.DNSAnswer{{
-			Name: ptr,
-			Type: 12,
-			TTL:  0,
-			Data: answer,
-		}},
-		Tag:    core.DNS,
-		Source: "Reverse DNS",
-	})
-}
-
 // MatchesWildcard returns true if the request provided resolved to a DNS wildcard.
 func (ds *DNSService) MatchesWildcard(req *core.AmassRequest) bool {
 	res := make(chan int)
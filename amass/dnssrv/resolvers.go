@@ -105,7 +105,10 @@ func (r *resolver) exchangeConn(conn net.Conn, name string, qtype uint16) ([]cor
 		if rd.Rcode == 3 {
 			again = false
 		}
-		return nil, again, fmt.Errorf("DNS query for %s, type %d returned error %d", name, qtype, rd.Rcode)
+		return nil, again, &ResolveError{
+			Err:   fmt.Sprintf("DNS query for %s, type %d returned error %d", name, qtype, rd.Rcode),
+			Rcode: rd.Rcode,
+		}
 	}
 
 	for _, a := range extractRawData(rd, qtype) {
@@ -118,7 +121,10 @@ func (r *resolver) exchangeConn(conn net.Conn, name string, qtype uint16) ([]cor
 	}
 
 	if len(answers) == 0 {
-		return nil, false, fmt.Errorf("DNS query for %s, type %d returned 0 records", name, qtype)
+		return nil, false, &ResolveError{
+			Err:   fmt.Sprintf("DNS query for %s, type %d returned 0 records", name, qtype),
+			Rcode: dns.RcodeSuccess,
+		}
 	}
 	return answers, false, nil
 }
@@ -204,6 +210,17 @@ func numInWindow(x, y time.Time, s []time.Time) int {
 	return count
 }
 
+// ResolveError contains the Rcode returned by the DNS server when a query is not successful.
+// An Rcode of RcodeSuccess indicates that the name exists without records of the requested type.
+type ResolveError struct {
+	Err   string
+	Rcode int
+}
+
+func (e *ResolveError) Error() string {
+	return e.Err
+}
+
 // nextResolver requests the next DNS resolution server
 func nextResolver() *resolver {
 	for {
@@ -0,0 +1,264 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package dnssrv
+
+import (
+	"net"
+	"strings"
+
+	"github.com/OWASP/Amass/amass/core"
+	"github.com/OWASP/Amass/amass/utils"
+	"github.com/miekg/dns"
+)
+
+const (
+	defaultSweepSize       = 250
+	defaultActiveSweepSize = 500
+
+	// The smallest IPv4 prefix length that will be swept in its entirety
+	minNetblockSweepPrefix = 16
+
+	// IPv6 nibble walks do not start above this prefix length unless netblocks are swept
+	defaultNibbleWalkPrefix = 64
+)
+
+const hexNibbles = "0123456789abcdef"
+
+func (ds *DNSService) sweepSize() int {
+	if size := ds.Config().SweepSize; size > 0 {
+		return size
+	}
+	if ds.Config().Active {
+		return defaultActiveSweepSize
+	}
+	return defaultSweepSize
+}
+
+func (ds *DNSService) reverseDNSSweep(addr string, cidr *net.IPNet) {
+	ip := net.ParseIP(addr)
+	if ip == nil || cidr == nil {
+		return
+	}
+
+	if ip.To4() == nil {
+		// IPv6 netblocks are too large to sweep, so only the nearby
+		// addresses are checked before walking the ip6.arpa zone
+		ds.sweepAddresses(utils.CIDRSubset(cidr, addr, ds.sweepSize()))
+
+		prefix := defaultNibbleWalkPrefix
+		if ones, _ := cidr.Mask.Size(); ones > prefix || ds.Config().SweepNetblocks {
+			prefix = ones
+		}
+		ds.walkIPv6Nibbles(ip, prefix)
+		return
+	}
+
+	if ds.Config().SweepNetblocks {
+		if ones, _ := cidr.Mask.Size(); ones >= minNetblockSweepPrefix {
+			if ds.claimNetblock(cidr) {
+				ds.sweepRange(utils.NewIPIterator(utils.CIDRHosts(cidr)))
+			}
+			return
+		}
+	}
+	// Get information about nearby IP addresses
+	ds.sweepAddresses(utils.CIDRSubset(cidr, addr, ds.sweepSize()))
+}
+
+// sweepScopeNetblocks performs reverse DNS sweeps across the netblocks in scope for the enumeration.
+func (ds *DNSService) sweepScopeNetblocks() {
+	for _, cidr := range ds.Config().ScopeCIDRs() {
+		ones, bits := cidr.Mask.Size()
+
+		if bits == 128 {
+			ds.walkIPv6Nibbles(cidr.IP, ones)
+			continue
+		}
+		if ones < minNetblockSweepPrefix {
+			ds.Config().Log.Printf("The netblock %s is too large to be swept", cidr)
+			continue
+		}
+		if ds.claimNetblock(cidr) {
+			ds.sweepRange(utils.NewIPIterator(utils.CIDRHosts(cidr)))
+		}
+	}
+}
+
+// claimNetblock returns false when the netblock is covered by a netblock that has already been swept.
+func (ds *DNSService) claimNetblock(cidr *net.IPNet) bool {
+	ds.Lock()
+	defer ds.Unlock()
+
+	ones, _ := cidr.Mask.Size()
+	for _, swept := range ds.sweptNetblocks {
+		if sones, _ := swept.Mask.Size(); sones <= ones && swept.Contains(cidr.IP) {
+			return false
+		}
+	}
+	ds.sweptNetblocks = append(ds.sweptNetblocks, cidr)
+	return true
+}
+
+func (ds *DNSService) sweepAddresses(ips []net.IP) {
+	for _, ip := range ips {
+		if !ds.sweepAddress(ip) {
+			return
+		}
+	}
+}
+
+// sweepRange obtains the addresses from the iterator as they are swept, so that
+// large netblocks are not held in memory.
+func (ds *DNSService) sweepRange(it *utils.IPIterator) {
+	for ip := it.Next(); ip != nil; ip = it.Next() {
+		if !ds.sweepAddress(ip) {
+			return
+		}
+	}
+}
+
+// sweepAddress starts the reverse DNS query for the address, and returns false when the sweep has been stopped.
+func (ds *DNSService) sweepAddress(ip net.IP) bool {
+	a := ip.String()
+	if !ds.Config().IsAddressInScope(a) || ds.sweepFilter.Duplicate(a) {
+		return true
+	}
+	if !ds.sweepLimiter.Take() {
+		return false
+	}
+	core.MaxConnections.Acquire(1)
+	go ds.reverseDNSRoutine(a)
+	return true
+}
+
+func (ds *DNSService) reverseDNSRoutine(ip string) {
+	defer core.MaxConnections.Release(1)
+
+	ds.SetActive()
+	ptr, answer, err := ds.reverse(ip)
+	if err != nil {
+		return
+	}
+	ds.sendReverseAnswer(ptr, answer)
+}
+
+func (ds *DNSService) sendReverseAnswer(ptr, answer string) {
+	domain := ds.Config().WhichDomain(answer)
+	if domain == "" {
+		return
+	}
+	ds.sendResolved(&core.AmassRequest{
+		Name:   ptr,
+		Domain: domain,
+		Records: []core.DNSAnswer{{
+			Name: ptr,
+			Type: 12,
+			TTL:  0,
+			Data: answer,
+		}},
+		Tag:    core.DNS,
+		Source: "Reverse DNS",
+	})
+}
+
+// walkIPv6Nibbles searches the ip6.arpa zone beneath the prefix of the address for PTR records.
+// Names returning NXDOMAIN have nothing beneath them, while NOERROR without answers
+// indicates an empty non-terminal that is worth descending into. The number of
+// queries performed is limited by the sweep size.
+func (ds *DNSService) walkIPv6Nibbles(ip net.IP, prefix int) {
+	// Round down to a nibble boundary, so that the entire netblock is walked
+	hex := utils.HexString(ip.To16())
+	start := hex[:prefix/4]
+
+	key := nibbleZone(start)
+	// Netblocks that share the nibble are walked separately
+	if prefix%4 != 0 {
+		mask := net.CIDRMask(prefix, 8*net.IPv6len)
+		key = (&net.IPNet{IP: ip.To16().Mask(mask), Mask: mask}).String()
+	}
+	if ds.sweepFilter.Duplicate(key) {
+		return
+	}
+
+	if !ds.sweepLimiter.Take() {
+		return
+	}
+	if found, exists := ds.queryNibble(start); found || !exists || len(start) == len(hex) {
+		return
+	}
+
+	// The nibbles following the start that are within the netblock
+	first, last := nibbleRange(hex[len(start)], prefix%4)
+	budget := ds.sweepSize() - 1
+
+	stack := []string{start}
+	for len(stack) > 0 && budget > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for i, n := range hexNibbles {
+			if budget <= 0 {
+				break
+			}
+			if cur == start && (i < first || i > last) {
+				continue
+			}
+
+			child := cur + string(n)
+			if len(child) < len(hex) && ds.sweepFilter.Duplicate(nibbleZone(child)) {
+				continue
+			}
+			budget--
+
+			if !ds.sweepLimiter.Take() {
+				return
+			}
+			if found, exists := ds.queryNibble(child); !found && exists && len(child) < len(hex) {
+				stack = append(stack, child)
+			}
+		}
+	}
+}
+
+// queryNibble returns true for found when a PTR record was discovered for the nibble prefix,
+// and true for exists when the name exists within the ip6.arpa zone.
+func (ds *DNSService) queryNibble(nibbles string) (bool, bool) {
+	core.MaxConnections.Acquire(1)
+	defer core.MaxConnections.Release(1)
+
+	ds.SetActive()
+	ptr := nibbleZone(nibbles)
+	answers, err := ds.resolve(ptr, "PTR")
+	if err != nil {
+		if re, ok := err.(*ResolveError); ok && re.Rcode == dns.RcodeSuccess {
+			return false, true
+		}
+		return false, false
+	}
+
+	for _, a := range answers {
+		if a.Type != int(dns.TypePTR) {
+			continue
+		}
+
+		name := removeLastDot(a.Data)
+		if name != "" && !strings.HasSuffix(name, ".ip6.arpa") {
+			ds.sendReverseAnswer(ptr, name)
+			return true, true
+		}
+	}
+	return false, true
+}
+
+// nibbleRange returns the nibble values that share the leading bits of the hex digit.
+func nibbleRange(digit byte, bits int) (int, int) {
+	n := strings.IndexByte(hexNibbles, digit)
+	mask := 0xf >> uint(bits)
+
+	return n &^ mask, n | mask
+}
+
+func nibbleZone(nibbles string) string {
+	return utils.IPv6NibbleFormat(nibbles) + ".ip6.arpa"
+}
@@ -0,0 +1,336 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package dnssrv
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/amass/core"
+	"github.com/OWASP/Amass/amass/utils"
+	evbus "github.com/asaskevich/EventBus"
+	"github.com/miekg/dns"
+)
+
+// testResolver answers the queries of the reverse DNS sweeps using the PTR records provided.
+type testResolver struct {
+	sync.Mutex
+	records   map[string]string
+	addresses []string
+	queries   []string
+}
+
+func (tr *testResolver) reverse(addr string) (string, string, error) {
+	tr.Lock()
+	defer tr.Unlock()
+
+	tr.addresses = append(tr.addresses, addr)
+	ptr := utils.ReverseIP(addr) + ".in-addr.arpa"
+	if ip := net.ParseIP(addr); ip.To4() == nil {
+		ptr = utils.IPv6NibbleFormat(utils.HexString(ip)) + ".ip6.arpa"
+	}
+
+	if name, found := tr.records[ptr]; found {
+		return ptr, name, nil
+	}
+	return ptr, "", errors.New("no PTR record")
+}
+
+func (tr *testResolver) resolve(name, qtype string) ([]core.DNSAnswer, error) {
+	tr.Lock()
+	defer tr.Unlock()
+
+	tr.queries = append(tr.queries, name)
+	if answer, found := tr.records[name]; found {
+		return []core.DNSAnswer{{Name: name, Type: int(dns.TypePTR), Data: answer + "."}}, nil
+	}
+	// Names above the records are empty non-terminals
+	for ptr := range tr.records {
+		if strings.HasSuffix(ptr, "."+name) {
+			return nil, &ResolveError{Err: "no answers", Rcode: dns.RcodeSuccess}
+		}
+	}
+	return nil, &ResolveError{Err: "NXDOMAIN", Rcode: dns.RcodeNameError}
+}
+
+// swept waits for the reverse DNS queries started by a sweep and returns the addresses, sorted.
+func (tr *testResolver) swept(expected int) []string {
+	waitFor(&tr.Mutex, func() bool { return len(tr.addresses) >= expected })
+
+	tr.Lock()
+	defer tr.Unlock()
+	addrs := append([]string{}, tr.addresses...)
+	sort.Slice(addrs, func(i, j int) bool {
+		return bytes.Compare(net.ParseIP(addrs[i]), net.ParseIP(addrs[j])) < 0
+	})
+	return addrs
+}
+
+// testNames collects the names provided by the PTR records that were discovered.
+type testNames struct {
+	sync.Mutex
+	names []string
+}
+
+func (tn *testNames) add(req *core.AmassRequest) {
+	tn.Lock()
+	defer tn.Unlock()
+
+	tn.names = append(tn.names, req.Records[0].Data)
+}
+
+// get waits for the expected number of names and returns them, sorted.
+func (tn *testNames) get(expected int) []string {
+	waitFor(&tn.Mutex, func() bool { return len(tn.names) >= expected })
+
+	tn.Lock()
+	defer tn.Unlock()
+	names := append([]string{}, tn.names...)
+	sort.Strings(names)
+	return names
+}
+
+// waitFor checks the condition while holding the lock, until it is met or a second has passed.
+func waitFor(lock *sync.Mutex, cond func() bool) {
+	for i := 0; i < 100; i++ {
+		lock.Lock()
+		met := cond()
+		lock.Unlock()
+
+		if met {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func newTestSweepService(config *core.AmassConfig, tr *testResolver) (*DNSService, *testNames) {
+	if config.Log == nil {
+		config.Log = log.New(new(bytes.Buffer), "", 0)
+	}
+	config.AddDomain("example.com")
+
+	names := new(testNames)
+	bus := evbus.New()
+	bus.Subscribe(core.RESOLVED, names.add)
+
+	ds := NewDNSService(config, bus)
+	ds.sweepLimiter = utils.NewRateLimiter(1000)
+	ds.reverse = tr.reverse
+	ds.resolve = tr.resolve
+	return ds, names
+}
+
+func TestReverseDNSSweep(t *testing.T) {
+	_, excluded, _ := net.ParseCIDR("192.0.2.100/31")
+
+	tests := []struct {
+		name      string
+		addr      string
+		cidr      string
+		size      int
+		netblocks bool
+		excluded  []*net.IPNet
+		first     string
+		last      string
+		count     int
+	}{
+		{"subset", "192.0.2.50", "192.0.2.0/24", 10, false, nil, "192.0.2.45", "192.0.2.55", 11},
+		{"netblock start", "192.0.2.2", "192.0.2.0/24", 10, false, nil, "192.0.2.0", "192.0.2.7", 8},
+		{"netblock end", "192.0.2.254", "192.0.2.0/24", 10, false, nil, "192.0.2.249", "192.0.2.255", 7},
+		{"excluded", "192.0.2.100", "192.0.2.0/24", 4, false, []*net.IPNet{excluded}, "192.0.2.98", "192.0.2.102", 3},
+		{"whole netblock", "192.0.2.2", "192.0.2.0/28", 4, true, nil, "192.0.2.1", "192.0.2.14", 14},
+		{"large netblock", "10.20.30.40", "10.0.0.0/8", 4, true, nil, "10.20.30.38", "10.20.30.42", 5},
+		{"ipv6 subnet", "2001:db8:0:1::5", "2001:db8::/32", 4, false, nil, "2001:db8:0:1::3", "2001:db8:0:1::7", 5},
+	}
+
+	for _, test := range tests {
+		_, cidr, _ := net.ParseCIDR(test.cidr)
+		tr := &testResolver{
+			records: map[string]string{utils.ReverseIP(test.first) + ".in-addr.arpa": "first.example.com"},
+		}
+		config := &core.AmassConfig{
+			SweepSize:      test.size,
+			SweepNetblocks: test.netblocks,
+			ExcludedCIDRs:  test.excluded,
+		}
+		ds, names := newTestSweepService(config, tr)
+
+		ds.reverseDNSSweep(test.addr, cidr)
+		addrs := tr.swept(test.count)
+		if len(addrs) != test.count || addrs[0] != test.first || addrs[len(addrs)-1] != test.last {
+			t.Errorf("%s: The sweep queried %v", test.name, addrs)
+			continue
+		}
+		for _, a := range addrs {
+			if config.IsAddressExcluded(a) {
+				t.Errorf("%s: The sweep queried the excluded address %s", test.name, a)
+			}
+		}
+		if net.ParseIP(test.first).To4() == nil {
+			continue
+		}
+		if got := names.get(1); len(got) != 1 || got[0] != "first.example.com" {
+			t.Errorf("%s: The sweep provided the names %v", test.name, got)
+		}
+	}
+}
+
+func TestSweepScopeNetblocks(t *testing.T) {
+	var buf bytes.Buffer
+	var scope []*net.IPNet
+	for _, n := range []string{"192.0.2.0/29", "192.0.2.0/30", "198.51.100.0/30", "10.0.0.0/8"} {
+		_, cidr, _ := net.ParseCIDR(n)
+		scope = append(scope, cidr)
+	}
+
+	tr := &testResolver{}
+	config := &core.AmassConfig{Log: log.New(&buf, "", 0)}
+	config.SetScope(scope, nil)
+	ds, _ := newTestSweepService(config, tr)
+
+	ds.sweepScopeNetblocks()
+	// The /30 is within the /29, and the /8 is too large to be swept
+	expected := "192.0.2.1 192.0.2.2 192.0.2.3 192.0.2.4 192.0.2.5 192.0.2.6 198.51.100.1 198.51.100.2"
+	if got := strings.Join(tr.swept(8), " "); got != expected {
+		t.Errorf("The scope sweep queried %s instead of %s", got, expected)
+	}
+	if !strings.Contains(buf.String(), "10.0.0.0/8 is too large") {
+		t.Errorf("The netblock that is too large to be swept was not logged: %q", buf.String())
+	}
+
+	// Netblocks covered by a netblock that has been swept are not swept again
+	_, cidr, _ := net.ParseCIDR("192.0.2.4/30")
+	if ds.claimNetblock(cidr) {
+		t.Errorf("claimNetblock returned true for a netblock that has been swept")
+	}
+}
+
+func TestWalkIPv6Nibbles(t *testing.T) {
+	records := map[string]string{
+		nibbleZone(utils.HexString(net.ParseIP("2001:db8::1"))):  "one.example.com",
+		nibbleZone(utils.HexString(net.ParseIP("2001:db8::20"))): "two.example.com",
+		// Within the 2001:db8:0:4::/62 and 2001:db8:0:8::/62 netblocks
+		nibbleZone(utils.HexString(net.ParseIP("2001:db8:0:7::1"))): "seven.example.com",
+		nibbleZone(utils.HexString(net.ParseIP("2001:db8:0:8::1"))): "eight.example.com",
+	}
+
+	tests := []struct {
+		name    string
+		addr    string
+		prefix  int
+		size    int
+		queries int
+		names   []string
+	}{
+		// The start, then 16 queries beneath each of the zones 0, 00, 000 and 002
+		{"walk", "2001:db8::", 112, 500, 81, []string{"one.example.com", "two.example.com"}},
+		{"budget", "2001:db8::", 112, 20, 20, []string{}},
+		{"start found", "2001:db8::20", 128, 500, 1, []string{"two.example.com"}},
+		{"start missing", "2001:db8:1::", 112, 500, 1, []string{}},
+		// The start, the four nibbles within the /62, then 16 queries at each of the remaining levels
+		{"partial nibble", "2001:db8:0:4::", 62, 500, 261, []string{"seven.example.com"}},
+	}
+
+	for _, test := range tests {
+		tr := &testResolver{records: records}
+		ds, names := newTestSweepService(&core.AmassConfig{SweepSize: test.size}, tr)
+
+		ds.walkIPv6Nibbles(net.ParseIP(test.addr), test.prefix)
+		if len(tr.queries) != test.queries {
+			t.Errorf("%s: The walk performed %d queries instead of %d", test.name, len(tr.queries), test.queries)
+		}
+		if got := names.get(len(test.names)); strings.Join(got, " ") != strings.Join(test.names, " ") {
+			t.Errorf("%s: The walk provided the names %v", test.name, got)
+		}
+
+		// The same zone is not walked twice
+		tr.queries = nil
+		ds.walkIPv6Nibbles(net.ParseIP(test.addr), test.prefix)
+		if len(tr.queries) != 0 {
+			t.Errorf("%s: The zone was walked a second time", test.name)
+		}
+	}
+
+	// Netblocks that share the nibble at the start of the walk are both walked
+	tr := &testResolver{records: records}
+	ds, names := newTestSweepService(&core.AmassConfig{SweepSize: 500}, tr)
+	ds.walkIPv6Nibbles(net.ParseIP("2001:db8:0:4::"), 62)
+	ds.walkIPv6Nibbles(net.ParseIP("2001:db8:0:8::"), 62)
+	if got := names.get(2); strings.Join(got, " ") != "eight.example.com seven.example.com" {
+		t.Errorf("The walks of the /62 netblocks provided the names %v", got)
+	}
+}
+
+func TestReverseDNSSweepIPv6(t *testing.T) {
+	tr := &testResolver{records: map[string]string{
+		nibbleZone(utils.HexString(net.ParseIP("2001:db8:0:1:0:1::9"))): "far.example.com",
+	}}
+	ds, names := newTestSweepService(&core.AmassConfig{SweepSize: 300}, tr)
+
+	// The address of a AAAA record is swept nearby within the /64 subnet, and then the subnet is walked
+	_, cidr, _ := net.ParseCIDR("2001:db8::/32")
+	ds.reverseDNSSweep("2001:db8:0:1::6", cidr)
+
+	addrs := tr.swept(157)
+	if len(addrs) != 157 || addrs[0] != "2001:db8:0:1::" || addrs[len(addrs)-1] != "2001:db8:0:1::9c" {
+		t.Errorf("The sweep queried %d addresses from %v to %v", len(addrs), addrs[0], addrs[len(addrs)-1])
+	}
+	if got := names.get(1); len(got) != 1 || got[0] != "far.example.com" {
+		t.Errorf("The walk provided the names %v", got)
+	}
+	// The start, then 16 queries at each level beneath the subnet
+	if zone := nibbleZone("20010db800000001"); len(tr.queries) != 257 || tr.queries[0] != zone {
+		t.Errorf("The walk performed %d queries starting with %v", len(tr.queries), tr.queries[:1])
+	}
+}
+
+func TestSweepSize(t *testing.T) {
+	tests := []struct {
+		size     int
+		active   bool
+		expected int
+	}{
+		{0, false, defaultSweepSize},
+		{0, true, defaultActiveSweepSize},
+		{100, true, 100},
+	}
+
+	for _, test := range tests {
+		ds := NewDNSService(&core.AmassConfig{SweepSize: test.size, Active: test.active}, evbus.New())
+		if got := ds.sweepSize(); got != test.expected {
+			t.Errorf("sweepSize returned %d instead of %d for the size %d", got, test.expected, test.size)
+		}
+	}
+}
+
+func TestClaimNetblock(t *testing.T) {
+	tests := []struct {
+		cidr     string
+		expected bool
+	}{
+		{"192.0.2.0/24", true},
+		{"192.0.2.0/24", false},
+		{"192.0.2.128/25", false},
+		// Larger netblocks are not covered by the smaller netblocks within them
+		{"192.0.0.0/16", true},
+		{"192.0.3.0/24", false},
+		{"198.51.100.0/24", true},
+	}
+
+	ds := NewDNSService(&core.AmassConfig{}, evbus.New())
+	for _, test := range tests {
+		_, cidr, _ := net.ParseCIDR(test.cidr)
+		if got := ds.claimNetblock(cidr); got != test.expected {
+			t.Errorf("claimNetblock(%s) returned %t", test.cidr, got)
+		}
+	}
+}
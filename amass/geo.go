@@ -0,0 +1,136 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/OWASP/Amass/amass/utils"
+)
+
+// GeoLocation is the location of an IP address provided by a local geolocation database.
+type GeoLocation struct {
+	// The ISO 3166-1 country code, or the English name when the code is not provided
+	Country string
+	City    string
+	// Located is false when the database did not provide coordinates for the address
+	Located   bool
+	Latitude  float64
+	Longitude float64
+}
+
+var (
+	// The geolocation database loaded using LoadGeoDatabase
+	geoLock sync.Mutex
+	geoDB   *utils.MMDBReader
+	// The file that provided the geolocation database
+	geoPath string
+)
+
+// LoadGeoDatabase opens the MaxMind DB file that provides the location of addresses,
+// such as the GeoLite2 City and Country databases. The file can be gzip compressed.
+func LoadGeoDatabase(path string) error {
+	key := databaseFileKey(path)
+
+	geoLock.Lock()
+	loaded := geoDB != nil && geoPath == key
+	geoLock.Unlock()
+	if loaded {
+		return nil
+	}
+
+	data, err := readDatabaseFile(path)
+	if err != nil {
+		return err
+	}
+
+	reader, err := utils.NewMMDBReader(data)
+	if err != nil {
+		return fmt.Errorf("Failed to load the geolocation database %s: %v", path, err)
+	}
+
+	geoLock.Lock()
+	defer geoLock.Unlock()
+
+	geoDB = reader
+	geoPath = key
+	return nil
+}
+
+// GeoRequest returns the location of the address, or nil when a geolocation
+// database has not been loaded or the database does not locate the address.
+func GeoRequest(addr string) *GeoLocation {
+	geoLock.Lock()
+	reader := geoDB
+	geoLock.Unlock()
+
+	ip := net.ParseIP(addr)
+	if reader == nil || ip == nil {
+		return nil
+	}
+
+	value, _, err := reader.Lookup(ip)
+	if err != nil || value == nil {
+		return nil
+	}
+	return geoFromRecord(value)
+}
+
+// geoFromRecord extracts the location from a record of the GeoLite2 City or Country databases.
+func geoFromRecord(value interface{}) *GeoLocation {
+	record, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	loc := new(GeoLocation)
+	// The registered country is used for addresses without a physical location
+	for _, key := range []string{"registered_country", "country"} {
+		if country := geoCountry(record[key]); country != "" {
+			loc.Country = country
+		}
+	}
+	if city, ok := record["city"].(map[string]interface{}); ok {
+		loc.City = geoName(city)
+	}
+
+	if l, ok := record["location"].(map[string]interface{}); ok {
+		lat, ok1 := l["latitude"].(float64)
+		lon, ok2 := l["longitude"].(float64)
+		if ok1 && ok2 {
+			loc.Latitude, loc.Longitude = lat, lon
+			loc.Located = true
+		}
+	}
+
+	if loc.Country == "" && loc.City == "" && !loc.Located {
+		return nil
+	}
+	return loc
+}
+
+func geoCountry(value interface{}) string {
+	country, ok := value.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	if code, ok := country["iso_code"].(string); ok && code != "" {
+		return code
+	}
+	return geoName(country)
+}
+
+// geoName returns the English name within the names map of the record.
+func geoName(record map[string]interface{}) string {
+	names, ok := record["names"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	name, _ := names["en"].(string)
+	return name
+}
@@ -0,0 +1,51 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"testing"
+)
+
+func TestGeoFromRecord(t *testing.T) {
+	names := func(name string) map[string]interface{} {
+		return map[string]interface{}{"en": name, "de": "x"}
+	}
+
+	record := map[string]interface{}{
+		"city":               map[string]interface{}{"names": names("Frankfurt am Main")},
+		"country":            map[string]interface{}{"iso_code": "DE", "names": names("Germany")},
+		"registered_country": map[string]interface{}{"iso_code": "US"},
+		"location":           map[string]interface{}{"latitude": 50.1188, "longitude": 8.6843},
+	}
+	loc := geoFromRecord(record)
+	if loc == nil || loc.Country != "DE" || loc.City != "Frankfurt am Main" || !loc.Located ||
+		loc.Latitude != 50.1188 || loc.Longitude != 8.6843 {
+		t.Errorf("geoFromRecord returned %+v", loc)
+	}
+
+	// Records of the Country databases only provide the registered country for some addresses
+	record = map[string]interface{}{
+		"registered_country": map[string]interface{}{"names": names("Netherlands")},
+	}
+	if loc := geoFromRecord(record); loc == nil || loc.Country != "Netherlands" || loc.City != "" || loc.Located {
+		t.Errorf("geoFromRecord returned %+v for the registered country", loc)
+	}
+
+	// Records of the Country databases provide the country without a location
+	record = map[string]interface{}{
+		"continent":          map[string]interface{}{"code": "EU", "names": names("Europe")},
+		"country":            map[string]interface{}{"iso_code": "NL", "names": names("Netherlands")},
+		"registered_country": map[string]interface{}{"iso_code": "NL", "names": names("Netherlands")},
+	}
+	if loc := geoFromRecord(record); loc == nil || loc.Country != "NL" || loc.Located ||
+		loc.Latitude != 0 || loc.Longitude != 0 {
+		t.Errorf("geoFromRecord returned %+v for the Country database record", loc)
+	}
+
+	for _, value := range []interface{}{map[string]interface{}{}, "string", nil} {
+		if loc := geoFromRecord(value); loc != nil {
+			t.Errorf("geoFromRecord returned %+v for %v", loc, value)
+		}
+	}
+}
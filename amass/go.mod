@@ -10,7 +10,7 @@ require (
 	github.com/temoto/robotstxt v0.0.0-20170603013557-9e4646fa7053 // indirect
 	github.com/temoto/robotstxt-go v0.0.0-20170603013557-9e4646fa7053 // indirect
 	golang.org/x/crypto v0.0.0-20180723164146-c126467f60eb // indirect
-	golang.org/x/net v0.0.0-20180724234803-3673e40ba225 // indirect
+	golang.org/x/net v0.0.0-20180724234803-3673e40ba225
 	golang.org/x/sys v0.0.0-20180724212812-e072cadbbdc8 // indirect
 	golang.org/x/text v0.3.0 // indirect
 	golang.org/x/tools v0.0.0-20180725152638-4d8a0ac9f66c // indirect
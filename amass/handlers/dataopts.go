@@ -4,9 +4,12 @@
 package handlers
 
 import (
+	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
 	"io"
 	"net"
+	"time"
 )
 
 type DataOptsHandler struct {
@@ -49,6 +52,49 @@ func DataOptsDriver(data []JSONFileFormat, handler DataHandler) error {
 			if _, ipnet, err = net.ParseCIDR(opt.CIDR); err == nil {
 				err = handler.InsertInfrastructure(opt.Address, opt.ASN, ipnet, opt.Description)
 			}
+		case OptSourceData:
+			var first, last time.Time
+
+			if opt.FirstSeen != nil {
+				first = *opt.FirstSeen
+			}
+			if opt.LastSeen != nil {
+				last = *opt.LastSeen
+			}
+			err = handler.InsertSourceData(opt.Name, opt.Domain, opt.Addresses,
+				first, last, opt.Evidence, opt.Tag, opt.Source)
+		case OptCertificate:
+			var der []byte
+			var cert *x509.Certificate
+
+			if der, err = base64.StdEncoding.DecodeString(opt.Certificate); err == nil {
+				if cert, err = x509.ParseCertificate(der); err == nil {
+					err = handler.InsertCertificate(opt.Address, opt.Port, cert, opt.Tag, opt.Source)
+				}
+			}
+		case OptRegistration:
+			var registered, expires time.Time
+
+			if opt.Registered != nil {
+				registered = *opt.Registered
+			}
+			if opt.Expires != nil {
+				expires = *opt.Expires
+			}
+			err = handler.InsertRegistration(opt.Name, opt.Handle, opt.Organization, opt.Country,
+				opt.AbuseEmails, opt.RangeStart, opt.RangeEnd, registered, expires, opt.Tag, opt.Source)
+		case OptHTTPService:
+			err = handler.InsertHTTPService(opt.Name, opt.Domain, opt.URL, opt.StatusCode,
+				opt.Title, opt.Server, opt.Redirects, opt.Technologies, opt.Tag, opt.Source)
+		case OptCloudRange:
+			if _, ipnet, err = net.ParseCIDR(opt.CIDR); err == nil {
+				err = handler.InsertCloudRange(opt.Address, ipnet, opt.Provider, opt.Service, opt.Region)
+			}
+		case OptGeolocation:
+			// Files written before the located field was added only provide coordinates
+			located := opt.Located || opt.Latitude != 0 || opt.Longitude != 0
+			err = handler.InsertGeolocation(opt.Address, opt.Country,
+				opt.City, located, opt.Latitude, opt.Longitude)
 		}
 		if err != nil {
 			break
@@ -174,3 +220,99 @@ func (d *DataOptsHandler) InsertInfrastructure(addr string, asn int, cidr *net.I
 		Description: desc,
 	})
 }
+
+func (d *DataOptsHandler) InsertSourceData(name, domain string, addrs []string, first, last time.Time, evidence, tag, source string) error {
+	opt := &JSONFileFormat{
+		Type:      OptSourceData,
+		Name:      name,
+		Domain:    domain,
+		Addresses: addrs,
+		Evidence:  evidence,
+		Tag:       tag,
+		Source:    source,
+	}
+
+	if !first.IsZero() {
+		opt.FirstSeen = &first
+	}
+	if !last.IsZero() {
+		opt.LastSeen = &last
+	}
+	return d.Enc.Encode(opt)
+}
+
+func (d *DataOptsHandler) InsertCertificate(addr string, port int, cert *x509.Certificate, tag, source string) error {
+	return d.Enc.Encode(&JSONFileFormat{
+		Type:        OptCertificate,
+		Address:     addr,
+		Port:        port,
+		Certificate: base64.StdEncoding.EncodeToString(cert.Raw),
+		Tag:         tag,
+		Source:      source,
+	})
+}
+
+func (d *DataOptsHandler) InsertHTTPService(name, domain, url string, status int, title, server string, redirects, techs []string, tag, source string) error {
+	return d.Enc.Encode(&JSONFileFormat{
+		Type:         OptHTTPService,
+		Name:         name,
+		Domain:       domain,
+		URL:          url,
+		StatusCode:   status,
+		Title:        title,
+		Server:       server,
+		Redirects:    redirects,
+		Technologies: techs,
+		Tag:          tag,
+		Source:       source,
+	})
+}
+
+func (d *DataOptsHandler) InsertRegistration(target, handle, org, country string, abuse []string, first, last string, registered, expires time.Time, tag, source string) error {
+	opt := &JSONFileFormat{
+		Type:         OptRegistration,
+		Name:         target,
+		Handle:       handle,
+		Organization: org,
+		Country:      country,
+		AbuseEmails:  abuse,
+		RangeStart:   first,
+		RangeEnd:     last,
+		Tag:          tag,
+		Source:       source,
+	}
+
+	if !registered.IsZero() {
+		opt.Registered = &registered
+	}
+	if !expires.IsZero() {
+		opt.Expires = &expires
+	}
+	return d.Enc.Encode(opt)
+}
+
+func (d *DataOptsHandler) InsertCloudRange(addr string, cidr *net.IPNet, provider, service, region string) error {
+	return d.Enc.Encode(&JSONFileFormat{
+		Type:     OptCloudRange,
+		Address:  addr,
+		CIDR:     cidr.String(),
+		Provider: provider,
+		Service:  service,
+		Region:   region,
+	})
+}
+
+func (d *DataOptsHandler) InsertGeolocation(addr, country, city string, located bool, lat, lon float64) error {
+	opt := &JSONFileFormat{
+		Type:    OptGeolocation,
+		Address: addr,
+		Country: country,
+		City:    city,
+	}
+	if located {
+		opt.Located = true
+		opt.Latitude = lat
+		opt.Longitude = lon
+	}
+	return d.Enc.Encode(opt)
+}
@@ -4,8 +4,10 @@
 package handlers
 
 import (
+	"crypto/x509"
 	"fmt"
 	"net"
+	"time"
 )
 
 const (
@@ -18,6 +20,12 @@ const (
 	OptNS             = "ns"
 	OptMX             = "mx"
 	OptInfrastructure = "infrastructure"
+	OptSourceData     = "source_data"
+	OptCertificate    = "certificate"
+	OptHTTPService    = "http_service"
+	OptRegistration   = "registration"
+	OptCloudRange     = "cloud_range"
+	OptGeolocation    = "geolocation"
 )
 
 type DataHandler interface {
@@ -40,19 +48,57 @@ type DataHandler interface {
 	InsertMX(name, domain, target, tdomain, tag, source string) error
 
 	InsertInfrastructure(addr string, asn int, cidr *net.IPNet, desc string) error
+
+	InsertSourceData(name, domain string, addrs []string, first, last time.Time, evidence, tag, source string) error
+
+	InsertCertificate(addr string, port int, cert *x509.Certificate, tag, source string) error
+
+	InsertHTTPService(name, domain, url string, status int, title, server string, redirects, techs []string, tag, source string) error
+
+	InsertRegistration(target, handle, org, country string, abuse []string, first, last string, registered, expires time.Time, tag, source string) error
+
+	InsertCloudRange(addr string, cidr *net.IPNet, provider, service, region string) error
+
+	InsertGeolocation(addr, country, city string, located bool, lat, lon float64) error
 }
 
 type JSONFileFormat struct {
-	Type         string `json:"type"`
-	Name         string `json:"name"`
-	Domain       string `json:"domain"`
-	Service      string `json:"service"`
-	TargetName   string `json:"target_name"`
-	TargetDomain string `json:"target_domain"`
-	Address      string `json:"addr"`
-	ASN          int    `json:"asn"`
-	CIDR         string `json:"cidr"`
-	Description  string `json:"desc"`
-	Tag          string `json:"tag"`
-	Source       string `json:"source"`
+	Type         string     `json:"type"`
+	Name         string     `json:"name"`
+	Domain       string     `json:"domain"`
+	Service      string     `json:"service"`
+	TargetName   string     `json:"target_name"`
+	TargetDomain string     `json:"target_domain"`
+	Address      string     `json:"addr"`
+	ASN          int        `json:"asn"`
+	CIDR         string     `json:"cidr"`
+	Description  string     `json:"desc"`
+	Tag          string     `json:"tag"`
+	Source       string     `json:"source"`
+	Addresses    []string   `json:"addrs,omitempty"`
+	FirstSeen    *time.Time `json:"first_seen,omitempty"`
+	LastSeen     *time.Time `json:"last_seen,omitempty"`
+	Evidence     string     `json:"evidence,omitempty"`
+	Port         int        `json:"port,omitempty"`
+	Certificate  string     `json:"cert,omitempty"`
+	URL          string     `json:"url,omitempty"`
+	StatusCode   int        `json:"status,omitempty"`
+	Title        string     `json:"title,omitempty"`
+	Server       string     `json:"server,omitempty"`
+	Redirects    []string   `json:"redirects,omitempty"`
+	Technologies []string   `json:"tech,omitempty"`
+	Handle       string     `json:"handle,omitempty"`
+	Organization string     `json:"org,omitempty"`
+	Country      string     `json:"country,omitempty"`
+	AbuseEmails  []string   `json:"abuse,omitempty"`
+	RangeStart   string     `json:"range_start,omitempty"`
+	RangeEnd     string     `json:"range_end,omitempty"`
+	Registered   *time.Time `json:"registered,omitempty"`
+	Expires      *time.Time `json:"expires,omitempty"`
+	Provider     string     `json:"provider,omitempty"`
+	Region       string     `json:"region,omitempty"`
+	City         string     `json:"city,omitempty"`
+	Located      bool       `json:"located,omitempty"`
+	Latitude     float64    `json:"lat,omitempty"`
+	Longitude    float64    `json:"lon,omitempty"`
 }
@@ -4,8 +4,13 @@
 package handlers
 
 import (
+	"crypto/x509"
 	"net"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/OWASP/Amass/amass/utils"
 	bolt "github.com/johnnadratowski/golang-neo4j-bolt-driver"
 	//"github.com/johnnadratowski/golang-neo4j-bolt-driver/structures/graph"
 )
@@ -358,3 +363,236 @@ func (n *Neo4j) InsertInfrastructure(addr string, asn int, cidr *net.IPNet, desc
 		"MERGE (as)-[:HAS_PREFIX]->(netblock)", params)
 	return err
 }
+
+func (n *Neo4j) InsertSourceData(name, domain string, addrs []string, first, last time.Time, evidence, tag, source string) error {
+	params := map[string]interface{}{
+		"name":     name,
+		"domain":   domain,
+		"evidence": evidence,
+		"tag":      tag,
+		"source":   source,
+	}
+
+	if name != domain {
+		_, err := n.conn.ExecNeo("MERGE (n:Subdomain {name: {name}}) "+
+			"ON CREATE SET n.tag = {tag}, n.source = {source}", params)
+		if err != nil {
+			return err
+		}
+
+		_, err = n.conn.ExecNeo("MATCH (domain:Domain {name: {domain}}) "+
+			"MATCH (target:Subdomain {name: {name}}) "+
+			"MERGE (domain)-[:ROOT_OF]->(target)", params)
+		if err != nil {
+			return err
+		}
+	}
+
+	if evidence != "" {
+		_, err := n.conn.ExecNeo("MATCH (n:Subdomain {name: {name}}) "+
+			"SET n.evidence = CASE WHEN n.evidence IS NULL THEN {evidence} "+
+			"WHEN n.evidence CONTAINS {evidence} THEN n.evidence "+
+			"ELSE n.evidence + ' ' + {evidence} END", params)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !first.IsZero() {
+		params["first"] = first.UTC().Format(time.RFC3339)
+
+		_, err := n.conn.ExecNeo("MATCH (n:Subdomain {name: {name}}) "+
+			"SET n.first_seen = CASE WHEN n.first_seen IS NULL OR n.first_seen > {first} "+
+			"THEN {first} ELSE n.first_seen END", params)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !last.IsZero() {
+		params["last"] = last.UTC().Format(time.RFC3339)
+
+		_, err := n.conn.ExecNeo("MATCH (n:Subdomain {name: {name}}) "+
+			"SET n.last_seen = CASE WHEN n.last_seen IS NULL OR n.last_seen < {last} "+
+			"THEN {last} ELSE n.last_seen END", params)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, addr := range addrs {
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			continue
+		}
+
+		params["addr"] = addr
+		params["type"] = "IPv6"
+		if ip.To4() != nil {
+			params["type"] = "IPv4"
+		}
+
+		_, err := n.conn.ExecNeo("MERGE (:IPAddress {addr: {addr}, type: {type}})", params)
+		if err != nil {
+			return err
+		}
+
+		_, err = n.conn.ExecNeo("MATCH (source:Subdomain {name: {name}}) "+
+			"MATCH (address:IPAddress {addr: {addr}, type: {type}}) "+
+			"MERGE (source)-[:SEEN_AT]->(address)", params)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *Neo4j) InsertCertificate(addr string, port int, cert *x509.Certificate, tag, source string) error {
+	ip := net.ParseIP(addr)
+	if ip == nil || cert == nil {
+		return nil
+	}
+
+	params := map[string]interface{}{
+		"addr":   addr,
+		"type":   "IPv6",
+		"port":   port,
+		"names":  strings.Join(utils.CertificateNames(cert), ","),
+		"tag":    tag,
+		"source": source,
+	}
+	if ip.To4() != nil {
+		params["type"] = "IPv4"
+	}
+	for k, v := range utils.CertificateProperties(cert) {
+		params[k] = v
+	}
+
+	_, err := n.conn.ExecNeo("MERGE (c:Certificate {fingerprint: {fingerprint}}) "+
+		"ON CREATE SET c.subject = {subject}, c.issuer = {issuer}, c.serial = {serial}, "+
+		"c.not_before = {not_before}, c.not_after = {not_after}, c.key_type = {key_type}, "+
+		"c.self_signed = {self_signed}, c.names = {names}, c.tag = {tag}, c.source = {source}", params)
+	if err != nil {
+		return err
+	}
+
+	_, err = n.conn.ExecNeo("MERGE (:IPAddress {addr: {addr}, type: {type}})", params)
+	if err != nil {
+		return err
+	}
+
+	_, err = n.conn.ExecNeo("MATCH (address:IPAddress {addr: {addr}, type: {type}}) "+
+		"MATCH (c:Certificate {fingerprint: {fingerprint}}) "+
+		"MERGE (address)-[:PRESENTS_CERT {port: {port}}]->(c)", params)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range utils.CertificateNames(cert) {
+		params["name"] = strings.ToLower(name)
+
+		_, err = n.conn.ExecNeo("MATCH (c:Certificate {fingerprint: {fingerprint}}) "+
+			"MATCH (target:Subdomain {name: {name}}) "+
+			"MERGE (c)-[:CERT_FOR]->(target)", params)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *Neo4j) InsertHTTPService(name, domain, url string, status int, title, server string, redirects, techs []string, tag, source string) error {
+	params := map[string]interface{}{
+		"name":      name,
+		"domain":    domain,
+		"url":       url,
+		"status":    status,
+		"title":     title,
+		"server":    server,
+		"redirects": strings.Join(redirects, " "),
+		"tech":      strings.Join(techs, ","),
+		"tag":       tag,
+		"source":    source,
+	}
+
+	_, err := n.conn.ExecNeo("MERGE (s:HTTPService {url: {url}}) "+
+		"SET s.status = {status}, s.title = {title}, s.server = {server}, "+
+		"s.redirects = {redirects}, s.tech = {tech}, s.tag = {tag}, s.source = {source}", params)
+	if err != nil {
+		return err
+	}
+
+	_, err = n.conn.ExecNeo("MATCH (source:Subdomain {name: {name}}) "+
+		"MATCH (s:HTTPService {url: {url}}) "+
+		"MERGE (source)-[:SERVES_HTTP]->(s)", params)
+	return err
+}
+
+func (n *Neo4j) InsertRegistration(target, handle, org, country string, abuse []string, first, last string, registered, expires time.Time, tag, source string) error {
+	params := map[string]interface{}{
+		"target":  target,
+		"handle":  handle,
+		"org":     org,
+		"country": country,
+		"abuse":   strings.Join(abuse, ","),
+		"range":   "",
+		"reg":     "",
+		"exp":     "",
+		"source":  source,
+	}
+	if first != "" && last != "" {
+		params["range"] = first + "-" + last
+	}
+	if !registered.IsZero() {
+		params["reg"] = registered.UTC().Format(time.RFC3339)
+	}
+	if !expires.IsZero() {
+		params["exp"] = expires.UTC().Format(time.RFC3339)
+	}
+
+	match := "MATCH (n:Subdomain {name: {target}}) "
+	if asn, err := strconv.Atoi(strings.TrimPrefix(target, "AS")); err == nil && strings.HasPrefix(target, "AS") {
+		params["asn"] = asn
+		match = "MATCH (n:AS {asn: {asn}}) "
+	} else if _, _, err := net.ParseCIDR(target); err == nil {
+		match = "MATCH (n:Netblock {cidr: {target}}) "
+	}
+
+	_, err := n.conn.ExecNeo(match+"SET n.rdap_handle = {handle}, n.rdap_org = {org}, "+
+		"n.rdap_country = {country}, n.rdap_abuse = {abuse}, n.rdap_range = {range}, "+
+		"n.rdap_registered = {reg}, n.rdap_expires = {exp}, n.rdap_source = {source}", params)
+	return err
+}
+
+func (n *Neo4j) InsertCloudRange(addr string, cidr *net.IPNet, provider, service, region string) error {
+	params := map[string]interface{}{
+		"addr":     addr,
+		"cidr":     cidr.String(),
+		"provider": provider,
+		"service":  service,
+		"region":   region,
+	}
+
+	_, err := n.conn.ExecNeo("MATCH (a:IPAddress {addr: {addr}}) "+
+		"SET a.cloud_provider = {provider}, a.cloud_service = {service}, "+
+		"a.cloud_region = {region}, a.cloud_cidr = {cidr}", params)
+	return err
+}
+
+func (n *Neo4j) InsertGeolocation(addr, country, city string, located bool, lat, lon float64) error {
+	params := map[string]interface{}{
+		"addr":    addr,
+		"country": country,
+		"city":    city,
+		"lat":     lat,
+		"lon":     lon,
+	}
+
+	coords := "a.geo_lat = {lat}, a.geo_lon = {lon}"
+	if !located {
+		coords = "a.geo_lat = null, a.geo_lon = null"
+	}
+	_, err := n.conn.ExecNeo("MATCH (a:IPAddress {addr: {addr}}) "+
+		"SET a.geo_country = {country}, a.geo_city = {city}, "+coords, params)
+	return err
+}
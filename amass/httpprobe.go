@@ -0,0 +1,323 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"html"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OWASP/Amass/amass/core"
+	"github.com/OWASP/Amass/amass/utils"
+	evbus "github.com/asaskevich/EventBus"
+)
+
+const (
+	defaultHTTPProbeTimeout = 10 * time.Second
+
+	// The maximum number of redirects followed for each web service
+	maxHTTPRedirects = 5
+
+	// The maximum number of response body bytes read while fingerprinting
+	maxHTTPBodySize = 1 << 20
+)
+
+// The ports that are always probed using plaintext HTTP or HTTPS
+var (
+	httpPorts  = map[int]bool{80: true, 8000: true, 8008: true, 8080: true}
+	httpsPorts = map[int]bool{443: true, 8443: true, 9443: true}
+)
+
+var titleRE = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// headerFingerprints identify technologies by the presence of response headers.
+var headerFingerprints = map[string]string{
+	"X-AspNet-Version":    "ASP.NET",
+	"X-AspNetMvc-Version": "ASP.NET MVC",
+	"X-Generator":         "",
+	"X-Jenkins":           "Jenkins",
+	"X-Drupal-Cache":      "Drupal",
+	"CF-RAY":              "Cloudflare",
+	"X-Amz-Cf-Id":         "Amazon CloudFront",
+	"X-Varnish":           "Varnish",
+	"X-Powered-By":        "",
+}
+
+// cookieFingerprints identify technologies by the names of the cookies that are set.
+var cookieFingerprints = map[string]string{
+	"PHPSESSID":         "PHP",
+	"JSESSIONID":        "Java",
+	"ASP.NET_SessionId": "ASP.NET",
+	"laravel_session":   "Laravel",
+	"wordpress_":        "WordPress",
+	"csrftoken":         "Django",
+}
+
+// bodyFingerprints identify technologies by markers within the response body.
+var bodyFingerprints = map[string]string{
+	"/wp-content/":                     "WordPress",
+	"Drupal.settings":                  "Drupal",
+	`content="Joomla`:                  "Joomla",
+	"__NEXT_DATA__":                    "Next.js",
+	"ng-version=":                      "Angular",
+	"data-reactroot":                   "React",
+	"/_nuxt/":                          "Nuxt.js",
+	`<meta name="generator" content="`: "",
+}
+
+// HTTPProbeService is the AmassService that handles the fingerprinting of web
+// services on the resolved names within the architecture.
+type HTTPProbeService struct {
+	core.BaseAmassService
+
+	bus    evbus.Bus
+	client *http.Client
+	queue  []*core.AmassRequest
+}
+
+// NewHTTPProbeService requires the enumeration configuration and event bus as parameters.
+// The object returned is initialized, but has not yet been started.
+func NewHTTPProbeService(config *core.AmassConfig, bus evbus.Bus) *HTTPProbeService {
+	hps := &HTTPProbeService{
+		bus:    bus,
+		client: newProbeClient(defaultHTTPProbeTimeout),
+	}
+
+	hps.BaseAmassService = *core.NewBaseAmassService("HTTP Probe Service", config, hps)
+	return hps
+}
+
+// OnStart implements the AmassService interface
+func (hps *HTTPProbeService) OnStart() error {
+	hps.BaseAmassService.OnStart()
+
+	if hps.Config().HTTPProbe {
+		hps.bus.SubscribeAsync(core.HTTPPROBE, hps.queueName, false)
+	}
+	go hps.processRequests()
+	return nil
+}
+
+// OnStop implements the AmassService interface
+func (hps *HTTPProbeService) OnStop() error {
+	hps.BaseAmassService.OnStop()
+
+	if hps.Config().HTTPProbe {
+		hps.bus.Unsubscribe(core.HTTPPROBE, hps.queueName)
+	}
+	return nil
+}
+
+func (hps *HTTPProbeService) queueName(name, domain string) {
+	hps.Lock()
+	defer hps.Unlock()
+
+	hps.queue = append(hps.queue, &core.AmassRequest{Name: name, Domain: domain})
+}
+
+func (hps *HTTPProbeService) nextRequest() *core.AmassRequest {
+	hps.Lock()
+	defer hps.Unlock()
+
+	if len(hps.queue) == 0 {
+		return nil
+	}
+
+	next := hps.queue[0]
+	// Remove the first slice element
+	if len(hps.queue) > 1 {
+		hps.queue = hps.queue[1:]
+	} else {
+		hps.queue = []*core.AmassRequest{}
+	}
+	return next
+}
+
+func (hps *HTTPProbeService) processRequests() {
+	for {
+		select {
+		case <-hps.PauseChan():
+			<-hps.ResumeChan()
+		case <-hps.Quit():
+			return
+		default:
+			if req := hps.nextRequest(); req != nil {
+				go hps.performRequest(req)
+			} else {
+				time.Sleep(100 * time.Millisecond)
+			}
+		}
+	}
+}
+
+func (hps *HTTPProbeService) performRequest(req *core.AmassRequest) {
+	result := &core.HTTPProbeResult{
+		Name:   req.Name,
+		Domain: req.Domain,
+	}
+	for _, port := range hps.Config().Ports {
+		// The enumeration must not end while output is held for this name
+		hps.SetActive()
+		if info := hps.probePort(req.Name, port); info != nil {
+			result.Services = append(result.Services, *info)
+		}
+	}
+	// The result is always published, so the output for the name is no longer held
+	hps.bus.Publish(core.HTTPINFO, result)
+}
+
+func (hps *HTTPProbeService) probePort(name string, port int) *core.AmassHTTPInfo {
+	core.MaxConnections.Acquire(1)
+	defer core.MaxConnections.Release(1)
+
+	for _, u := range probeURLs(name, port) {
+		if info, err := probeURL(hps.client, u, hps.SetActive); err == nil {
+			return info
+		}
+	}
+	return nil
+}
+
+// probeURLs returns the URLs that will be attempted for the name and port, in order.
+func probeURLs(name string, port int) []string {
+	host := net.JoinHostPort(name, strconv.Itoa(port))
+
+	switch {
+	case port == 80:
+		return []string{"http://" + name + "/"}
+	case port == 443:
+		return []string{"https://" + name + "/"}
+	case httpPorts[port]:
+		return []string{"http://" + host + "/"}
+	case httpsPorts[port]:
+		return []string{"https://" + host + "/"}
+	}
+	return []string{"https://" + host + "/", "http://" + host + "/"}
+}
+
+// newProbeClient returns a client that honors the settings for all outbound web requests,
+// but does not follow redirects, since each location is recorded by probeURL. Certificates
+// are not verified, so services with self-signed or expired certificates are still fingerprinted.
+func newProbeClient(timeout time.Duration) *http.Client {
+	client := utils.NewInsecureHTTPClient(timeout)
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return client
+}
+
+// probeURL requests the URL and follows redirects, recording each location, before
+// the final response is fingerprinted. The active function is called before each request.
+func probeURL(client *http.Client, u string, active func()) (*core.AmassHTTPInfo, error) {
+	info := &core.AmassHTTPInfo{URL: u}
+
+	current := u
+	for i := 0; ; i++ {
+		active()
+
+		req, err := http.NewRequest("GET", current, nil)
+		if err != nil {
+			return nil, err
+		}
+		utils.SetHTTPRequestHeaders(req, nil)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		var next *url.URL
+		if loc := resp.Header.Get("Location"); resp.StatusCode >= 300 && resp.StatusCode < 400 && loc != "" {
+			next, _ = resp.Request.URL.Parse(loc)
+		}
+		if next == nil || i >= maxHTTPRedirects {
+			fingerprintResponse(info, resp)
+			resp.Body.Close()
+			return info, nil
+		}
+		io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxHTTPBodySize))
+		resp.Body.Close()
+
+		current = next.String()
+		info.Redirects = append(info.Redirects, current)
+	}
+}
+
+func fingerprintResponse(info *core.AmassHTTPInfo, resp *http.Response) {
+	info.StatusCode = resp.StatusCode
+	info.Server = resp.Header.Get("Server")
+
+	techs := make(map[string]struct{})
+	if info.Server != "" {
+		techs[productName(info.Server)] = struct{}{}
+	}
+	for header, tech := range headerFingerprints {
+		value := resp.Header.Get(header)
+		if value == "" {
+			continue
+		}
+		// Headers without a fixed technology name provide the name within the value
+		if tech == "" {
+			tech = productName(value)
+		}
+		techs[tech] = struct{}{}
+	}
+	for _, cookie := range resp.Cookies() {
+		for prefix, tech := range cookieFingerprints {
+			if strings.HasPrefix(cookie.Name, prefix) {
+				techs[tech] = struct{}{}
+			}
+		}
+	}
+
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxHTTPBodySize))
+	page := string(body)
+	if m := titleRE.FindStringSubmatch(page); len(m) > 1 {
+		info.Title = strings.Join(strings.Fields(html.UnescapeString(m[1])), " ")
+	}
+	for marker, tech := range bodyFingerprints {
+		idx := strings.Index(page, marker)
+		if idx == -1 {
+			continue
+		}
+		if tech == "" {
+			content := page[idx+len(marker):]
+			if end := strings.IndexByte(content, '"'); end != -1 {
+				tech = productName(content[:end])
+			}
+		}
+		if tech != "" {
+			techs[tech] = struct{}{}
+		}
+	}
+
+	for tech := range techs {
+		info.Technologies = append(info.Technologies, tech)
+	}
+	sort.Strings(info.Technologies)
+}
+
+// productName removes the version and comments from a product description, such as "nginx/1.14.0 (Ubuntu)".
+func productName(value string) string {
+	name := strings.TrimSpace(value)
+	if idx := strings.IndexAny(name, "/("); idx > 0 {
+		name = name[:idx]
+	}
+	name = strings.TrimSpace(name)
+	// Remove a trailing version number, such as "WordPress 4.9.8"
+	if fields := strings.Fields(name); len(fields) > 1 {
+		if last := fields[len(fields)-1]; last[0] >= '0' && last[0] <= '9' {
+			name = strings.Join(fields[:len(fields)-1], " ")
+		}
+	}
+	return name
+}
@@ -0,0 +1,100 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProbeURL(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/login", http.StatusFound)
+	})
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "nginx/1.14.0 (Ubuntu)")
+		w.Header().Set("X-Powered-By", "PHP/7.2.10")
+		http.SetCookie(w, &http.Cookie{Name: "wordpress_test_cookie", Value: "1"})
+		io.WriteString(w, "<html><head><title>\n  Log In &amp; Out\n</title>"+
+			`<link rel="stylesheet" href="/wp-content/style.css"></head></html>`)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	info, err := probeURL(newProbeClient(5*time.Second), ts.URL+"/", func() {})
+	if err != nil {
+		t.Fatalf("probeURL failed: %v", err)
+	}
+	if info.StatusCode != http.StatusOK {
+		t.Errorf("probeURL returned the status code %d instead of 200", info.StatusCode)
+	}
+	if info.Title != "Log In & Out" {
+		t.Errorf("probeURL returned the title %q", info.Title)
+	}
+	if info.Server != "nginx/1.14.0 (Ubuntu)" {
+		t.Errorf("probeURL returned the server %q", info.Server)
+	}
+	if len(info.Redirects) != 1 || info.Redirects[0] != ts.URL+"/login" {
+		t.Errorf("probeURL returned the redirects %v", info.Redirects)
+	}
+	if techs := strings.Join(info.Technologies, ","); techs != "PHP,WordPress,nginx" {
+		t.Errorf("probeURL returned the technologies %s", techs)
+	}
+}
+
+func TestProbeURLRedirectLimit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, r.URL.Path+"a", http.StatusMovedPermanently)
+	}))
+	defer ts.Close()
+
+	info, err := probeURL(newProbeClient(5*time.Second), ts.URL+"/", func() {})
+	if err != nil {
+		t.Fatalf("probeURL failed: %v", err)
+	}
+	if info.StatusCode != http.StatusMovedPermanently || len(info.Redirects) != maxHTTPRedirects {
+		t.Errorf("probeURL followed %d redirects and returned the status code %d",
+			len(info.Redirects), info.StatusCode)
+	}
+}
+
+func TestProbeURLUntrustedCertificate(t *testing.T) {
+	// The test server presents a self-signed certificate for the wrong name
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "<title>Self-Signed</title>")
+	}))
+	defer ts.Close()
+
+	info, err := probeURL(newProbeClient(5*time.Second), ts.URL+"/", func() {})
+	if err != nil {
+		t.Fatalf("probeURL failed for the untrusted certificate: %v", err)
+	}
+	if info.Title != "Self-Signed" {
+		t.Errorf("probeURL returned the title %q", info.Title)
+	}
+}
+
+func TestProbeURLs(t *testing.T) {
+	tests := []struct {
+		port int
+		urls string
+	}{
+		{80, "http://www.example.com/"},
+		{443, "https://www.example.com/"},
+		{8080, "http://www.example.com:8080/"},
+		{8443, "https://www.example.com:8443/"},
+		{3000, "https://www.example.com:3000/ http://www.example.com:3000/"},
+	}
+
+	for _, test := range tests {
+		if urls := strings.Join(probeURLs("www.example.com", test.port), " "); urls != test.urls {
+			t.Errorf("Port %d returned the URLs %s instead of %s", test.port, urls, test.urls)
+		}
+	}
+}
@@ -0,0 +1,330 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"errors"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OWASP/Amass/amass/core"
+	"github.com/OWASP/Amass/amass/dnssrv"
+	"github.com/OWASP/Amass/amass/utils"
+)
+
+// The types of evidence that associate a root domain name with the infrastructure
+const (
+	IntelCertificate  = "certificate"
+	IntelReverseDNS   = "reverse_dns"
+	IntelReverseWhois = "reverse_whois"
+)
+
+// The confidence each type of evidence provides that a root domain belongs to the organization
+var intelWeights = map[string]int{
+	IntelCertificate:  40,
+	IntelReverseDNS:   30,
+	IntelReverseWhois: 25,
+}
+
+// The confidence added for each additional address the root domain was discovered on
+const (
+	intelAddressBonus    = 5
+	intelMaxAddressBonus = 20
+)
+
+// IntelOutput is a registered root domain name discovered on the infrastructure in scope.
+type IntelOutput struct {
+	Domain string
+
+	// The likelihood, from 1 to 100, that the domain belongs to the organization
+	Confidence int
+
+	// The types of evidence that discovered the domain
+	Sources []string
+
+	// The addresses in scope that provided the evidence
+	Addresses []string
+
+	// The autonomous systems announcing those addresses
+	ASNs []int
+}
+
+// IntelCollection discovers the root domain names of an organization by following its
+// autonomous systems to the announced netblocks, and the netblocks to the names found
+// using reverse DNS and the certificates served on the addresses.
+type IntelCollection struct {
+	// The search string matched against the AS descriptions
+	Org string
+
+	// The autonomous systems whose announced netblocks are in scope
+	ASNs []int
+
+	// The IP address ranges in scope
+	CIDRs []*net.IPNet
+
+	// The IP address ranges in scope, such as individual addresses
+	Ranges []utils.IPRange
+
+	// Limits the collection to shard Shard (starting at zero) of Shards shards of the addresses
+	Shard  int
+	Shards int
+
+	// Will the addresses be investigated in sequential order instead of a random order?
+	Sequential bool
+
+	// The ports checked for certificates
+	Ports []int
+
+	// Will the discovered domains be run through reverse whois?
+	ReverseWhois bool
+
+	// The channel that will receive the results, ordered by decreasing confidence
+	Output chan *IntelOutput
+
+	// Closing the channel halts the collection
+	Done chan struct{}
+
+	lock     sync.Mutex
+	evidence map[string]*intelEvidence
+}
+
+// intelEvidence collects everything that associated a root domain with the infrastructure.
+type intelEvidence struct {
+	sources map[string]struct{}
+	addrs   map[string]struct{}
+	asns    map[int]struct{}
+}
+
+// NewIntelCollection returns an initialized IntelCollection object.
+func NewIntelCollection() *IntelCollection {
+	return &IntelCollection{
+		Ports:    []int{443},
+		Output:   make(chan *IntelOutput, 100),
+		Done:     make(chan struct{}),
+		evidence: make(map[string]*intelEvidence),
+	}
+}
+
+// Start begins the collection and returns once all the results have been sent on the output channel.
+func (ic *IntelCollection) Start() error {
+	defer close(ic.Output)
+
+	if ic.Org != "" {
+		records, err := LookupASNsByName(ic.Org)
+		if err != nil {
+			return err
+		}
+		for _, r := range records {
+			ic.ASNs = append(ic.ASNs, r.ASN)
+		}
+	}
+
+	ranges, asns := ic.scope()
+	it := utils.NewIPIterator(ranges...)
+	if it.Total().Sign() == 0 {
+		return errors.New("The parameters identified no hosts")
+	}
+	if ic.Shards > 0 {
+		if err := it.Shard(ic.Shard, ic.Shards); err != nil {
+			return err
+		}
+	}
+	if !ic.Sequential {
+		it.Randomize(time.Now().UnixNano())
+	}
+
+	var wg sync.WaitGroup
+	maxPulls := utils.NewSemaphore(100)
+loop:
+	for ip := it.Next(); ip != nil; ip = it.Next() {
+		select {
+		case <-ic.Done:
+			break loop
+		default:
+		}
+
+		var asn int
+		if r := asns.Search(ip); r != nil {
+			asn = r.ASN
+		}
+
+		core.MaxConnections.Acquire(1)
+		maxPulls.Acquire(1)
+		wg.Add(1)
+		go func(addr string, asn int) {
+			defer wg.Done()
+			defer core.MaxConnections.Release(1)
+			defer maxPulls.Release(1)
+
+			ic.investigate(addr, asn)
+		}(ip.String(), asn)
+	}
+	wg.Wait()
+
+	if ic.ReverseWhois {
+		ic.reverseWhois()
+	}
+
+	for _, out := range ic.results() {
+		select {
+		case <-ic.Done:
+			return nil
+		case ic.Output <- out:
+		}
+	}
+	return nil
+}
+
+// scope returns the address ranges in scope, and the index of the autonomous systems announcing them.
+func (ic *IntelCollection) scope() ([]utils.IPRange, *rangeIndex) {
+	var announced []*ipRange
+	ranges := append([]utils.IPRange{}, ic.Ranges...)
+
+	for _, asn := range ic.ASNs {
+		record, err := ASNRequest(asn)
+		if err != nil {
+			continue
+		}
+
+		for _, cidr := range record.Netblocks {
+			if _, ipnet, err := net.ParseCIDR(cidr); err == nil {
+				r := utils.CIDRHosts(ipnet)
+
+				ranges = append(ranges, r)
+				announced = append(announced, &ipRange{First: r.First.To16(), Last: r.Last.To16(), ASN: asn})
+			}
+		}
+	}
+	for _, cidr := range ic.CIDRs {
+		ranges = append(ranges, utils.CIDRHosts(cidr))
+	}
+	return utils.MergeIPRanges(ranges), newRangeIndex(announced)
+}
+
+// investigate obtains the root domains from the reverse DNS and certificates of the address.
+func (ic *IntelCollection) investigate(addr string, asn int) {
+	if _, answer, err := dnssrv.Reverse(addr); err == nil {
+		if d := strings.TrimSpace(SubdomainToDomain(answer)); d != "" {
+			ic.addEvidence(d, IntelReverseDNS, addr, asn)
+		}
+	}
+
+	for _, r := range PullCertificateNames(addr, ic.Ports) {
+		if d := strings.TrimSpace(r.Domain); d != "" {
+			ic.addEvidence(d, IntelCertificate, addr, asn)
+		}
+	}
+}
+
+// reverseWhois adds the domains registered with the same details as the discovered domains.
+func (ic *IntelCollection) reverseWhois() {
+	ic.lock.Lock()
+	var domains []string
+	for d := range ic.evidence {
+		domains = append(domains, d)
+	}
+	ic.lock.Unlock()
+	sort.Strings(domains)
+
+	for _, d := range domains {
+		select {
+		case <-ic.Done:
+			return
+		default:
+		}
+
+		related, err := ReverseWhois(d)
+		if err != nil {
+			continue
+		}
+		for _, r := range related {
+			if name := strings.ToLower(strings.TrimSpace(r)); name != "" {
+				ic.addEvidence(name, IntelReverseWhois, "", 0)
+			}
+		}
+	}
+}
+
+func (ic *IntelCollection) addEvidence(domain, source, addr string, asn int) {
+	ic.lock.Lock()
+	defer ic.lock.Unlock()
+
+	domain = strings.ToLower(domain)
+	e, found := ic.evidence[domain]
+	if !found {
+		e = &intelEvidence{
+			sources: make(map[string]struct{}),
+			addrs:   make(map[string]struct{}),
+			asns:    make(map[int]struct{}),
+		}
+		ic.evidence[domain] = e
+	}
+
+	e.sources[source] = struct{}{}
+	if addr != "" {
+		e.addrs[addr] = struct{}{}
+	}
+	if asn != 0 {
+		e.asns[asn] = struct{}{}
+	}
+}
+
+// results returns the deduplicated domains ordered by decreasing confidence.
+func (ic *IntelCollection) results() []*IntelOutput {
+	ic.lock.Lock()
+	defer ic.lock.Unlock()
+
+	var results []*IntelOutput
+	for domain, e := range ic.evidence {
+		out := &IntelOutput{Domain: domain}
+
+		for s := range e.sources {
+			out.Sources = append(out.Sources, s)
+		}
+		for a := range e.addrs {
+			out.Addresses = append(out.Addresses, a)
+		}
+		for asn := range e.asns {
+			out.ASNs = append(out.ASNs, asn)
+		}
+		sort.Strings(out.Sources)
+		sort.Strings(out.Addresses)
+		sort.Ints(out.ASNs)
+
+		out.Confidence = intelConfidence(out.Sources, len(out.Addresses))
+		results = append(results, out)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Confidence != results[j].Confidence {
+			return results[i].Confidence > results[j].Confidence
+		}
+		return results[i].Domain < results[j].Domain
+	})
+	return results
+}
+
+// intelConfidence scores the evidence: each type of evidence adds its weight, and
+// discovering the domain on several addresses adds a smaller amount for each address.
+func intelConfidence(sources []string, addrs int) int {
+	var score int
+
+	for _, s := range sources {
+		score += intelWeights[s]
+	}
+	if addrs > 1 {
+		bonus := (addrs - 1) * intelAddressBonus
+		if bonus > intelMaxAddressBonus {
+			bonus = intelMaxAddressBonus
+		}
+		score += bonus
+	}
+	if score > 100 {
+		score = 100
+	}
+	return score
+}
@@ -0,0 +1,60 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIntelConfidence(t *testing.T) {
+	tests := []struct {
+		sources []string
+		addrs   int
+		score   int
+	}{
+		{[]string{IntelReverseDNS}, 1, 30},
+		{[]string{IntelCertificate, IntelReverseDNS}, 3, 80},
+		{[]string{IntelReverseWhois}, 0, 25},
+		{[]string{IntelCertificate, IntelReverseDNS, IntelReverseWhois}, 50, 100},
+	}
+
+	for _, test := range tests {
+		if score := intelConfidence(test.sources, test.addrs); score != test.score {
+			t.Errorf("%v on %d addresses: intelConfidence returned %d instead of %d",
+				test.sources, test.addrs, score, test.score)
+		}
+	}
+}
+
+func TestIntelResults(t *testing.T) {
+	ic := NewIntelCollection()
+
+	ic.addEvidence("Example.com", IntelCertificate, "192.0.2.1", 64500)
+	ic.addEvidence("example.com", IntelReverseDNS, "192.0.2.1", 64500)
+	ic.addEvidence("example.com", IntelCertificate, "192.0.2.2", 64501)
+	ic.addEvidence("example.net", IntelReverseDNS, "192.0.2.3", 64500)
+	ic.addEvidence("example.org", IntelReverseWhois, "", 0)
+
+	results := ic.results()
+	if len(results) != 3 {
+		t.Fatalf("results returned %d domains instead of 3", len(results))
+	}
+
+	var order []string
+	for _, r := range results {
+		order = append(order, r.Domain)
+	}
+	if got := strings.Join(order, " "); got != "example.com example.net example.org" {
+		t.Errorf("results returned the domains in the order %s", got)
+	}
+
+	first := results[0]
+	if first.Confidence != 75 || strings.Join(first.Sources, " ") != "certificate reverse_dns" {
+		t.Errorf("results returned the confidence %d and sources %v", first.Confidence, first.Sources)
+	}
+	if len(first.Addresses) != 2 || len(first.ASNs) != 2 || first.ASNs[0] != 64500 {
+		t.Errorf("results returned the addresses %v and ASNs %v", first.Addresses, first.ASNs)
+	}
+}
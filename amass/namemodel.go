@@ -0,0 +1,313 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	modelStart = "^"
+	modelEnd   = "$"
+
+	// The maximum number of tokens within a generated label
+	maxModelTokens = 8
+
+	// The number of values beyond the largest observed number that will be attempted
+	numberLookahead = 2
+)
+
+// nameModel learns the naming structure of discovered labels using a bigram
+// model over label tokens. Runs of digits are replaced by classes that
+// preserve the width of the number, so numbering schemes can be extended.
+type nameModel struct {
+	transitions map[string]map[string]int
+	totals      map[string]int
+	numbers     map[int][]int
+	parents     map[string]int
+	observed    map[string]struct{}
+}
+
+type nameCandidate struct {
+	Label string
+	Prob  float64
+}
+
+func newNameModel() *nameModel {
+	return &nameModel{
+		transitions: make(map[string]map[string]int),
+		totals:      make(map[string]int),
+		numbers:     make(map[int][]int),
+		parents:     make(map[string]int),
+		observed:    make(map[string]struct{}),
+	}
+}
+
+// Train adds the first label of the name to the model. The remainder of the name
+// is tracked as a parent that generated labels can be attached to.
+func (m *nameModel) Train(name, domain string) {
+	if name == domain || !strings.HasSuffix(name, "."+domain) {
+		return
+	}
+
+	parts := strings.SplitN(name, ".", 2)
+	label := parts[0]
+	if _, found := m.observed[label]; found {
+		m.parents[parts[1]]++
+		return
+	}
+	m.observed[label] = struct{}{}
+	m.parents[parts[1]]++
+
+	prev := modelStart
+	for _, t := range m.tokenize(label) {
+		m.addTransition(prev, t)
+		prev = t
+	}
+	m.addTransition(prev, modelEnd)
+}
+
+func (m *nameModel) addTransition(from, to string) {
+	if _, found := m.transitions[from]; !found {
+		m.transitions[from] = make(map[string]int)
+	}
+	m.transitions[from][to]++
+	m.totals[from]++
+}
+
+// tokenize splits the label into runs of letters, runs of digits and separators.
+// Runs of digits are returned as number classes, and the values are recorded.
+func (m *nameModel) tokenize(label string) []string {
+	var tokens []string
+	var cur []rune
+	var curDigits bool
+
+	flush := func() {
+		if len(cur) == 0 {
+			return
+		}
+		t := string(cur)
+		if curDigits {
+			if n, err := strconv.Atoi(t); err == nil {
+				m.addNumber(len(t), n)
+			}
+			t = numberClass(len(t))
+		}
+		tokens = append(tokens, t)
+		cur = []rune{}
+	}
+
+	for _, c := range label {
+		switch {
+		case c == '-' || c == '_':
+			flush()
+			tokens = append(tokens, string(c))
+		case unicode.IsDigit(c):
+			if !curDigits {
+				flush()
+			}
+			curDigits = true
+			cur = append(cur, c)
+		default:
+			if curDigits {
+				flush()
+			}
+			curDigits = false
+			cur = append(cur, c)
+		}
+	}
+	flush()
+	return tokens
+}
+
+func (m *nameModel) addNumber(width, n int) {
+	for _, v := range m.numbers[width] {
+		if v == n {
+			return
+		}
+	}
+	m.numbers[width] = append(m.numbers[width], n)
+}
+
+func numberClass(width int) string {
+	return "#" + strconv.Itoa(width)
+}
+
+func numberClassWidth(token string) int {
+	if len(token) < 2 || token[0] != '#' {
+		return 0
+	}
+
+	width, err := strconv.Atoi(token[1:])
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
+// Parents returns up to max of the most common parent names observed during training.
+func (m *nameModel) Parents(max int) []string {
+	var parents []string
+
+	for p := range m.parents {
+		parents = append(parents, p)
+	}
+	sort.Slice(parents, func(i, j int) bool {
+		if m.parents[parents[i]] == m.parents[parents[j]] {
+			return parents[i] < parents[j]
+		}
+		return m.parents[parents[i]] > m.parents[parents[j]]
+	})
+
+	if len(parents) > max {
+		parents = parents[:max]
+	}
+	return parents
+}
+
+// Generate returns up to limit labels that were not observed during training,
+// ranked by the likelihood assigned by the model.
+func (m *nameModel) Generate(limit int) []*nameCandidate {
+	var results []*nameCandidate
+
+	seen := make(map[string]struct{})
+	pq := &sequenceQueue{{tokens: []string{modelStart}, prob: 1}}
+	// Bound the amount of work performed by the search
+	for steps := 0; pq.Len() > 0 && len(results) < limit && steps < limit*100; steps++ {
+		seq := heap.Pop(pq).(*tokenSequence)
+		last := seq.tokens[len(seq.tokens)-1]
+
+		if last == modelEnd {
+			for _, c := range m.expandNumbers(seq) {
+				if _, found := m.observed[c.Label]; found {
+					continue
+				}
+				if _, found := seen[c.Label]; found || c.Label == "" {
+					continue
+				}
+				seen[c.Label] = struct{}{}
+				results = append(results, c)
+			}
+			continue
+		}
+		if len(seq.tokens) > maxModelTokens {
+			continue
+		}
+
+		total := float64(m.totals[last])
+		for next, count := range m.transitions[last] {
+			tokens := make([]string, len(seq.tokens), len(seq.tokens)+1)
+			copy(tokens, seq.tokens)
+
+			heap.Push(pq, &tokenSequence{
+				tokens: append(tokens, next),
+				prob:   seq.prob * float64(count) / total,
+			})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Prob > results[j].Prob
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// expandNumbers replaces the number classes within the sequence using the observed
+// values and the values that follow the largest observed number.
+func (m *nameModel) expandNumbers(seq *tokenSequence) []*nameCandidate {
+	candidates := []*nameCandidate{{Prob: seq.prob}}
+
+	for _, t := range seq.tokens {
+		if t == modelStart || t == modelEnd {
+			continue
+		}
+
+		width := numberClassWidth(t)
+		if width == 0 {
+			for _, c := range candidates {
+				c.Label += t
+			}
+			continue
+		}
+
+		values := m.numberValues(width)
+		var expanded []*nameCandidate
+		for _, c := range candidates {
+			for _, v := range values {
+				expanded = append(expanded, &nameCandidate{
+					Label: c.Label + fmt.Sprintf("%0*d", width, v),
+					Prob:  c.Prob / float64(len(values)),
+				})
+			}
+		}
+		candidates = expanded
+	}
+	return candidates
+}
+
+func (m *nameModel) numberValues(width int) []int {
+	values := append([]int{}, m.numbers[width]...)
+	sort.Ints(values)
+
+	max := 0
+	if len(values) > 0 {
+		max = values[len(values)-1]
+	}
+	// Fill the gaps within small numbering schemes
+	if max < 20 {
+		values = []int{}
+		for i := 0; i <= max; i++ {
+			values = append(values, i)
+		}
+	}
+	for i := 1; i <= numberLookahead; i++ {
+		values = append(values, max+i)
+	}
+
+	var results []int
+	limit := 1
+	for i := 0; i < width; i++ {
+		limit *= 10
+	}
+	for _, v := range values {
+		if v < limit {
+			results = append(results, v)
+		}
+	}
+	return results
+}
+
+type tokenSequence struct {
+	tokens []string
+	prob   float64
+}
+
+// sequenceQueue implements heap.Interface with the most likely sequence at the top.
+type sequenceQueue []*tokenSequence
+
+func (q sequenceQueue) Len() int { return len(q) }
+
+func (q sequenceQueue) Less(i, j int) bool { return q[i].prob > q[j].prob }
+
+func (q sequenceQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *sequenceQueue) Push(x interface{}) {
+	*q = append(*q, x.(*tokenSequence))
+}
+
+func (q *sequenceQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	*q = old[:n-1]
+	return item
+}
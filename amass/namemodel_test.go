@@ -0,0 +1,48 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"testing"
+)
+
+func TestNameModelGenerate(t *testing.T) {
+	domain := "example.com"
+	model := newNameModel()
+
+	for _, name := range []string{
+		"web01-us-east.example.com",
+		"web02-us-east.example.com",
+		"web03-us-west.example.com",
+		"db01-us-east.example.com",
+		"api.example.com",
+	} {
+		model.Train(name, domain)
+	}
+
+	candidates := model.Generate(50)
+	if len(candidates) == 0 {
+		t.Fatal("Generate did not return any candidates")
+	}
+
+	var found bool
+	for i, c := range candidates {
+		if i > 0 && c.Prob > candidates[i-1].Prob {
+			t.Errorf("The candidates were not ranked by likelihood")
+		}
+		if c.Label == "web01-us-east" {
+			t.Errorf("Generate returned the observed label %s", c.Label)
+		}
+		if c.Label == "web04-us-east" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Generate did not extend the numbering scheme: %v", candidates)
+	}
+
+	if parents := model.Parents(3); len(parents) != 1 || parents[0] != domain {
+		t.Errorf("Parents returned %v instead of [%s]", parents, domain)
+	}
+}
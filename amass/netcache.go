@@ -0,0 +1,395 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultInfraCacheTTL is the amount of time infrastructure data is used before it is refreshed.
+const DefaultInfraCacheTTL = 7 * 24 * time.Hour
+
+// The version of the infrastructure cache file format
+const infraCacheVersion = 1
+
+// prefixNode is a node of the binary trie that indexes netblocks by their prefix bits.
+type prefixNode struct {
+	child [2]*prefixNode
+	// The value stored for the netblock ending at this node, or nil
+	value interface{}
+}
+
+// prefixTrie finds the most specific netblock containing an address using a longest prefix match.
+type prefixTrie struct {
+	ipv4 *prefixNode
+	ipv6 *prefixNode
+}
+
+func newPrefixTrie() *prefixTrie {
+	return &prefixTrie{
+		ipv4: &prefixNode{},
+		ipv6: &prefixNode{},
+	}
+}
+
+func (t *prefixTrie) root(ip net.IP) (*prefixNode, net.IP) {
+	if ip4 := ip.To4(); ip4 != nil {
+		return t.ipv4, ip4
+	}
+	return t.ipv6, ip.To16()
+}
+
+// insert returns the node for the netblock, and creates the nodes leading to it when necessary.
+func (t *prefixTrie) insert(ipnet *net.IPNet) *prefixNode {
+	root, ip := t.root(ipnet.IP)
+	ones, _ := ipnet.Mask.Size()
+
+	n := root
+	for i := 0; i < ones; i++ {
+		bit := int(ip[i/8]>>uint(7-i%8)) & 1
+		if n.child[bit] == nil {
+			n.child[bit] = &prefixNode{}
+		}
+		n = n.child[bit]
+	}
+	return n
+}
+
+// node returns the trie node for the netblock, if it exists.
+func (t *prefixTrie) node(ipnet *net.IPNet) *prefixNode {
+	root, ip := t.root(ipnet.IP)
+	ones, _ := ipnet.Mask.Size()
+
+	n := root
+	for i := 0; i < ones && n != nil; i++ {
+		n = n.child[int(ip[i/8]>>uint(7-i%8))&1]
+	}
+	return n
+}
+
+// match returns the node storing a value for the smallest netblock containing the address, or nil.
+func (t *prefixTrie) match(ip net.IP) *prefixNode {
+	var match *prefixNode
+
+	root, b := t.root(ip)
+	for i, n := 0, root; n != nil; i++ {
+		if n.value != nil {
+			match = n
+		}
+		if i >= len(b)*8 {
+			break
+		}
+		n = n.child[int(b[i/8]>>uint(7-i%8))&1]
+	}
+	return match
+}
+
+// infraNetblock is the value stored in the trie for a netblock of a cached record.
+type infraNetblock struct {
+	asn  int
+	cidr *net.IPNet
+}
+
+// infraCache stores the autonomous system records obtained from online sources, and
+// indexes their netblocks so addresses are found using a longest prefix match.
+type infraCache struct {
+	sync.Mutex
+	ttl     time.Duration
+	path    string
+	dirty   bool
+	records map[int]*infraCacheEntry
+	trie    *prefixTrie
+}
+
+type infraCacheEntry struct {
+	Record  *ASRecord
+	Updated time.Time
+}
+
+// The infrastructure data shared by all enumerations within the process
+var netCache = newInfraCache(DefaultInfraCacheTTL)
+
+func newInfraCache(ttl time.Duration) *infraCache {
+	return &infraCache{
+		ttl:     ttl,
+		records: make(map[int]*infraCacheEntry),
+		trie:    newPrefixTrie(),
+	}
+}
+
+func (c *infraCache) expired(e *infraCacheEntry) bool {
+	return c.ttl > 0 && time.Since(e.Updated) > c.ttl
+}
+
+// insert adds the record to the cache and indexes all of its netblocks.
+// Records that are replaced keep their age, since only netblocks were added.
+func (c *infraCache) insert(record *ASRecord) {
+	c.Lock()
+	defer c.Unlock()
+
+	updated := time.Now()
+	if e, found := c.records[record.ASN]; found {
+		updated = e.Updated
+	}
+	c.add(record, updated)
+	c.dirty = true
+}
+
+func (c *infraCache) add(record *ASRecord, updated time.Time) {
+	if old, found := c.records[record.ASN]; found {
+		c.unindex(old.Record)
+	}
+
+	c.records[record.ASN] = &infraCacheEntry{
+		Record:  record,
+		Updated: updated,
+	}
+	for _, nb := range record.Netblocks {
+		_, ipnet, err := net.ParseCIDR(nb)
+		if err != nil {
+			continue
+		}
+
+		c.trie.insert(ipnet).value = &infraNetblock{
+			asn:  record.ASN,
+			cidr: ipnet,
+		}
+	}
+}
+
+// unindex removes the netblocks of the record from the trie.
+func (c *infraCache) unindex(record *ASRecord) {
+	for _, nb := range record.Netblocks {
+		_, ipnet, err := net.ParseCIDR(nb)
+		if err != nil {
+			continue
+		}
+
+		if n := c.trie.node(ipnet); n != nil {
+			if nb, ok := n.value.(*infraNetblock); ok && nb.asn == record.ASN {
+				n.value = nil
+			}
+		}
+	}
+}
+
+// remove deletes the expired record from the cache.
+func (c *infraCache) remove(asn int) {
+	if e, found := c.records[asn]; found {
+		c.unindex(e.Record)
+		delete(c.records, asn)
+		c.dirty = true
+	}
+}
+
+// lookupIP returns the ASN, smallest netblock and description for the address.
+func (c *infraCache) lookupIP(addr string) (int, *net.IPNet, string) {
+	c.Lock()
+	defer c.Unlock()
+
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return 0, nil, ""
+	}
+
+	for {
+		match := c.trie.match(ip)
+		if match == nil {
+			return 0, nil, ""
+		}
+
+		nb := match.value.(*infraNetblock)
+		e := c.records[nb.asn]
+		if e == nil || c.expired(e) {
+			// Expired data is removed, so less specific netblocks are considered
+			c.remove(nb.asn)
+			match.value = nil
+			continue
+		}
+		return nb.asn, nb.cidr, e.Record.Description
+	}
+}
+
+// lookupCIDR returns the ASN and description for the netblock.
+func (c *infraCache) lookupCIDR(cidr *net.IPNet) (int, string) {
+	c.Lock()
+	defer c.Unlock()
+
+	n := c.trie.node(cidr)
+	if n == nil || n.value == nil {
+		return 0, ""
+	}
+
+	nb := n.value.(*infraNetblock)
+	e := c.records[nb.asn]
+	if e == nil || c.expired(e) {
+		c.remove(nb.asn)
+		return 0, ""
+	}
+	return nb.asn, e.Record.Description
+}
+
+// record returns the cached ASRecord for the ASN, or nil when the data is missing or expired.
+func (c *infraCache) record(asn int) *ASRecord {
+	c.Lock()
+	defer c.Unlock()
+
+	e, found := c.records[asn]
+	if !found {
+		return nil
+	}
+	if c.expired(e) {
+		c.remove(asn)
+		return nil
+	}
+	return e.Record
+}
+
+// infraCacheFile is the format of the cache persisted between runs.
+type infraCacheFile struct {
+	Version int                `json:"version"`
+	Records []infraCacheRecord `json:"records"`
+}
+
+type infraCacheRecord struct {
+	ASN            int       `json:"asn"`
+	Prefix         string    `json:"prefix"`
+	CC             string    `json:"cc"`
+	Registry       string    `json:"registry"`
+	AllocationDate time.Time `json:"allocation_date"`
+	Description    string    `json:"desc"`
+	Netblocks      []string  `json:"netblocks"`
+	Updated        time.Time `json:"updated"`
+}
+
+// load reads the records within the cache file that have not expired.
+func (c *infraCache) load(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("Failed to read the infrastructure cache %s: %v", path, err)
+	}
+
+	var file infraCacheFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("Failed to parse the infrastructure cache %s: %v", path, err)
+	}
+	// Caches created by other versions are replaced
+	if file.Version != infraCacheVersion {
+		return nil
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	for _, r := range file.Records {
+		e := &infraCacheEntry{Updated: r.Updated}
+		if c.expired(e) {
+			continue
+		}
+
+		c.add(&ASRecord{
+			ASN:            r.ASN,
+			Prefix:         r.Prefix,
+			CC:             r.CC,
+			Registry:       r.Registry,
+			AllocationDate: r.AllocationDate,
+			Description:    r.Description,
+			Netblocks:      r.Netblocks,
+		}, r.Updated)
+	}
+	return nil
+}
+
+// save writes the records that have not expired to the cache file.
+func (c *infraCache) save(path string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if !c.dirty {
+		return nil
+	}
+
+	file := infraCacheFile{Version: infraCacheVersion}
+	for _, e := range c.records {
+		if c.expired(e) {
+			continue
+		}
+
+		r := e.Record
+		file.Records = append(file.Records, infraCacheRecord{
+			ASN:            r.ASN,
+			Prefix:         r.Prefix,
+			CC:             r.CC,
+			Registry:       r.Registry,
+			AllocationDate: r.AllocationDate,
+			Description:    r.Description,
+			Netblocks:      r.Netblocks,
+			Updated:        e.Updated,
+		})
+	}
+
+	data, err := json.Marshal(&file)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("Failed to create the infrastructure cache directory: %v", err)
+	}
+	// Write a temporary file first, so an interrupted run does not corrupt the cache
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("Failed to write the infrastructure cache %s: %v", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("Failed to write the infrastructure cache %s: %v", path, err)
+	}
+	c.dirty = false
+	return nil
+}
+
+// DefaultInfraCachePath returns the location of the infrastructure cache within the user cache directory.
+func DefaultInfraCachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "amass", "infrastructure.json")
+}
+
+// OpenInfrastructureCache loads the ASN and netblock data cached by previous runs from the
+// file, which is also where SaveInfrastructureCache stores the data. Cached data older than
+// the ttl is refreshed from the online sources (DefaultInfraCacheTTL is used when zero).
+func OpenInfrastructureCache(path string, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = DefaultInfraCacheTTL
+	}
+
+	netCache.Lock()
+	netCache.ttl = ttl
+	netCache.path = path
+	netCache.Unlock()
+	return netCache.load(path)
+}
+
+// SaveInfrastructureCache writes the ASN and netblock data to the file provided to
+// OpenInfrastructureCache. Nothing is written when a cache file has not been opened.
+func SaveInfrastructureCache() error {
+	netCache.Lock()
+	path := netCache.path
+	netCache.Unlock()
+
+	if path == "" {
+		return nil
+	}
+	return netCache.save(path)
+}
@@ -0,0 +1,113 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestInfraCacheLookup(t *testing.T) {
+	c := newInfraCache(time.Hour)
+	c.insert(&ASRecord{
+		ASN:         64500,
+		Description: "EXAMPLE-LARGE",
+		Netblocks:   []string{"10.0.0.0/8", "2001:db8::/32"},
+	})
+	c.insert(&ASRecord{
+		ASN:         64501,
+		Description: "EXAMPLE-SMALL",
+		Netblocks:   []string{"10.1.0.0/16"},
+	})
+
+	tests := []struct {
+		addr string
+		asn  int
+		cidr string
+	}{
+		{"10.1.2.3", 64501, "10.1.0.0/16"},
+		{"10.2.0.1", 64500, "10.0.0.0/8"},
+		{"2001:db8::1", 64500, "2001:db8::/32"},
+		{"11.0.0.1", 0, ""},
+	}
+	for _, test := range tests {
+		asn, cidr, _ := c.lookupIP(test.addr)
+		if asn != test.asn || (cidr != nil && cidr.String() != test.cidr) {
+			t.Errorf("%s: lookupIP returned AS%d and %v", test.addr, asn, cidr)
+		}
+	}
+
+	_, ipnet, _ := net.ParseCIDR("10.1.0.0/16")
+	if asn, desc := c.lookupCIDR(ipnet); asn != 64501 || desc != "EXAMPLE-SMALL" {
+		t.Errorf("lookupCIDR returned AS%d and %s", asn, desc)
+	}
+	_, ipnet, _ = net.ParseCIDR("10.1.0.0/24")
+	if asn, _ := c.lookupCIDR(ipnet); asn != 0 {
+		t.Errorf("lookupCIDR returned AS%d for a netblock that is not cached", asn)
+	}
+
+	// Replacing a record removes the netblocks it no longer has
+	c.insert(&ASRecord{ASN: 64501, Netblocks: []string{"192.0.2.0/24"}})
+	if asn, _, _ := c.lookupIP("10.1.2.3"); asn != 64500 {
+		t.Errorf("lookupIP returned AS%d after the record was replaced", asn)
+	}
+}
+
+func TestInfraCacheExpiry(t *testing.T) {
+	c := newInfraCache(time.Hour)
+	c.add(&ASRecord{ASN: 64500, Netblocks: []string{"10.0.0.0/8"}}, time.Now())
+	c.add(&ASRecord{ASN: 64501, Netblocks: []string{"10.1.0.0/16"}}, time.Now().Add(-2*time.Hour))
+
+	// The expired netblock is removed and the less specific one is used
+	if asn, cidr, _ := c.lookupIP("10.1.2.3"); asn != 64500 || cidr.String() != "10.0.0.0/8" {
+		t.Errorf("lookupIP returned AS%d and %v", asn, cidr)
+	}
+	if r := c.record(64501); r != nil {
+		t.Errorf("record returned the expired record %v", r)
+	}
+}
+
+func TestInfraCachePersistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "netcache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "amass", "infrastructure.json")
+
+	c := newInfraCache(time.Hour)
+	c.insert(&ASRecord{
+		ASN:         64500,
+		CC:          "US",
+		Description: "EXAMPLE",
+		Netblocks:   []string{"192.0.2.0/24"},
+	})
+	c.add(&ASRecord{ASN: 64501, Netblocks: []string{"198.51.100.0/24"}}, time.Now().Add(-2*time.Hour))
+	if err := c.save(path); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	loaded := newInfraCache(time.Hour)
+	if err := loaded.load(path); err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if r := loaded.record(64500); r == nil || r.CC != "US" || r.Description != "EXAMPLE" {
+		t.Errorf("load returned the unexpected record %v", r)
+	}
+	if asn, _, _ := loaded.lookupIP("192.0.2.1"); asn != 64500 {
+		t.Errorf("lookupIP returned AS%d after the cache was loaded", asn)
+	}
+	if asn, _, _ := loaded.lookupIP("198.51.100.1"); asn != 0 {
+		t.Errorf("The expired record was saved to the cache file")
+	}
+
+	// A missing file is an empty cache
+	if err := loaded.load(filepath.Join(dir, "missing.json")); err != nil {
+		t.Errorf("load returned an error for a missing file: %v", err)
+	}
+}
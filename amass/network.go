@@ -19,6 +19,7 @@ import (
 	"github.com/OWASP/Amass/amass/core"
 	"github.com/OWASP/Amass/amass/dnssrv"
 	"github.com/OWASP/Amass/amass/utils"
+	"golang.org/x/net/publicsuffix"
 )
 
 // ASRecord stores all autonomous system information needed by Amass
@@ -33,52 +34,28 @@ type ASRecord struct {
 }
 
 var (
-	// Cache for the infrastructure data collected from online sources
-	netDataLock  sync.Mutex
-	netDataCache map[int]*ASRecord
-	// Domains discovered by the SubdomainToDomain method call
-	domainLock  sync.Mutex
-	domainCache map[string]struct{}
+	// Serializes the online lookups for infrastructure data
+	netDataLock sync.Mutex
+	// Performs the DNS queries of the online lookups, which is replaced during testing
+	resolveName = dnssrv.Resolve
+	// The list of autonomous systems searched by LookupASNsByName
+	asnListLock sync.Mutex
+	asnList     []ASRecord
 )
 
-func init() {
-	netDataCache = make(map[int]*ASRecord)
-	domainCache = make(map[string]struct{})
-}
-
-// SubdomainToDomain returns the first subdomain name of the provided
-// parameter that responds to a DNS query for the NS record type.
+// SubdomainToDomain returns the registered domain name of the provided parameter, as
+// determined by the embedded Public Suffix List, including the private suffixes. An empty
+// string is returned for IP addresses and names that are public suffixes themselves.
 func SubdomainToDomain(name string) string {
-	domainLock.Lock()
-	defer domainLock.Unlock()
-
-	var domain string
-	// Obtain all parts of the subdomain name
-	labels := strings.Split(strings.TrimSpace(name), ".")
-	// Check the cache for all parts of the name
-	for i := len(labels); i >= 0; i-- {
-		sub := strings.Join(labels[i:], ".")
-
-		if _, ok := domainCache[sub]; ok {
-			domain = sub
-			break
-		}
-	}
-	if domain != "" {
-		return domain
+	name = strings.ToLower(strings.Trim(strings.TrimSpace(name), "."))
+	name = strings.TrimPrefix(name, "*.")
+	if name == "" || net.ParseIP(name) != nil {
+		return ""
 	}
-	// Check the DNS for all parts of the name
-	for i := 0; i < len(labels)-1; i++ {
-		sub := strings.Join(labels[i:], ".")
 
-		core.MaxConnections.Acquire(1)
-		defer core.MaxConnections.Release(1)
-		if ns, err := dnssrv.Resolve(sub, "NS"); err == nil {
-			pieces := strings.Split(ns[0].Data, ",")
-			domainCache[pieces[0]] = struct{}{}
-			domain = pieces[0]
-			break
-		}
+	domain, err := publicsuffix.EffectiveTLDPlusOne(name)
+	if err != nil {
+		return ""
 	}
 	return domain
 }
@@ -88,19 +65,23 @@ func IPRequest(addr string) (int, *net.IPNet, string, error) {
 	netDataLock.Lock()
 	defer netDataLock.Unlock()
 
-	// Is the data already available in the cache?
-	asn, cidr, desc := ipSearch(addr)
+	// Is the data available from the local ASN databases provided by the user?
+	asn, cidr, desc := localASNData.lookupIP(addr)
 	if asn != 0 {
 		return asn, cidr, desc, nil
 	}
+	// Is the data already available in the cache?
+	if asn, cidr, desc = netCache.lookupIP(addr); asn != 0 {
+		return asn, cidr, desc, nil
+	}
 
 	record, err := fetchOnlineData(addr, 0)
 	if err != nil {
 		return 0, nil, "", err
 	}
 
-	netDataCache[record.ASN] = record
-	asn, cidr, desc = ipSearch(addr)
+	netCache.insert(record)
+	asn, cidr, desc = netCache.lookupIP(addr)
 	if asn == 0 {
 		return 0, nil, "", fmt.Errorf("IPRequest failed to find data for %s after an online search", addr)
 	}
@@ -112,15 +93,18 @@ func ASNRequest(asn int) (*ASRecord, error) {
 	netDataLock.Lock()
 	defer netDataLock.Unlock()
 
-	var err error
-	record, found := netDataCache[asn]
-	if !found {
-		record, err = fetchOnlineData("", asn)
-		if err != nil {
-			return nil, err
-		}
-		netDataCache[record.ASN] = record
+	if record := localASNData.record(asn); record != nil {
+		return record, nil
+	}
+	if record := netCache.record(asn); record != nil {
+		return record, nil
 	}
+
+	record, err := fetchOnlineData("", asn)
+	if err != nil {
+		return nil, err
+	}
+	netCache.insert(record)
 	return record, nil
 }
 
@@ -129,80 +113,27 @@ func CIDRRequest(cidr *net.IPNet) (int, string, error) {
 	netDataLock.Lock()
 	defer netDataLock.Unlock()
 
-	asn, desc := cidrSearch(cidr)
+	asn, desc := localASNData.lookupCIDR(cidr)
 	if asn != 0 {
 		return asn, desc, nil
 	}
+	if asn, desc = netCache.lookupCIDR(cidr); asn != 0 {
+		return asn, desc, nil
+	}
 
 	record, err := fetchOnlineData(cidr.IP.String(), 0)
 	if err != nil {
 		return 0, "", err
 	}
 
-	netDataCache[record.ASN] = record
-	asn, desc = cidrSearch(cidr)
+	netCache.insert(record)
+	asn, desc = netCache.lookupCIDR(cidr)
 	if asn == 0 {
 		return 0, "", fmt.Errorf("CIDRRequest failed to find data for %s after an online search", cidr)
 	}
 	return asn, desc, nil
 }
 
-func cidrSearch(ipnet *net.IPNet) (int, string) {
-	var a int
-	var desc string
-loop:
-	for asn, record := range netDataCache {
-		for _, netblock := range record.Netblocks {
-			if netblock == ipnet.String() {
-				a = asn
-				desc = record.Description
-				break loop
-			}
-		}
-	}
-	return a, desc
-}
-
-func compareCIDRSizes(first, second *net.IPNet) int {
-	var result int
-
-	s1, _ := first.Mask.Size()
-	s2, _ := second.Mask.Size()
-	if s1 > s2 {
-		result = 1
-	} else if s2 > s1 {
-		result = -1
-	}
-	return result
-}
-
-func ipSearch(addr string) (int, *net.IPNet, string) {
-	var a int
-	var cidr *net.IPNet
-	var desc string
-
-	ip := net.ParseIP(addr)
-	for asn, record := range netDataCache {
-		for _, netblock := range record.Netblocks {
-			_, ipnet, err := net.ParseCIDR(netblock)
-			if err != nil {
-				continue
-			}
-
-			if ipnet.Contains(ip) {
-				// Select the smallest CIDR
-				if cidr != nil && compareCIDRSizes(cidr, ipnet) == 1 {
-					continue
-				}
-				a = asn
-				cidr = ipnet
-				desc = record.Description
-			}
-		}
-	}
-	return a, cidr, desc
-}
-
 func fetchOnlineData(addr string, asn int) (*ASRecord, error) {
 	if addr == "" && asn == 0 {
 		return nil, fmt.Errorf("fetchOnlineData params are insufficient: addr: %s asn: %d", addr, asn)
@@ -217,8 +148,13 @@ func fetchOnlineData(addr string, asn int) (*ASRecord, error) {
 		}
 	}
 
-	record, ok := netDataCache[asn]
-	if !ok {
+	record := netCache.record(asn)
+	if record != nil {
+		// The cached record is shared, so a copy receives the new netblock
+		r := *record
+		r.Netblocks = append([]string{}, record.Netblocks...)
+		record = &r
+	} else {
 		// Get the ASN record from the online source
 		record, err = asnLookup(asn)
 		if err != nil {
@@ -242,28 +178,51 @@ func originLookup(addr string) (int, string, error) {
 	core.MaxConnections.Acquire(1)
 	defer core.MaxConnections.Release(1)
 
-	var err error
-	var name string
-	var answers []core.DNSAnswer
-	if ip := net.ParseIP(addr); len(ip.To4()) == net.IPv4len {
-		name = utils.ReverseIP(addr) + ".origin.asn.cymru.com"
-	} else if len(ip) == net.IPv6len {
-		name = utils.IPv6NibbleFormat(utils.HexString(ip)) + ".origin6.asn.cymru.com"
-	} else {
-		return 0, "", fmt.Errorf("originLookup param is insufficient: addr: %s", ip)
+	name, err := originName(net.ParseIP(addr))
+	if err != nil {
+		return 0, "", err
 	}
 
-	answers, err = dnssrv.Resolve(name, "TXT")
+	answers, err := resolveName(name, "TXT")
 	if err != nil {
 		return 0, "", fmt.Errorf("originLookup: DNS TXT record query error: %s: %v", name, err)
 	}
-	// Retrieve the ASN
-	fields := strings.Split(answers[0].Data, " | ")
-	asn, err := strconv.Atoi(fields[0])
+	return parseOriginInfo(answers[0].Data)
+}
+
+// originName returns the Team Cymru IP to ASN mapping name for the address.
+func originName(ip net.IP) (string, error) {
+	// IPv4-mapped IPv6 addresses are looked up as IPv4 addresses
+	if ip4 := ip.To4(); ip4 != nil {
+		return utils.ReverseIP(ip4.String()) + ".origin.asn.cymru.com", nil
+	} else if ip16 := ip.To16(); ip16 != nil {
+		return utils.IPv6NibbleFormat(utils.HexString(ip16)) + ".origin6.asn.cymru.com", nil
+	}
+	return "", fmt.Errorf("originLookup param is insufficient: addr: %s", ip)
+}
+
+// parseOriginInfo extracts the ASN and the announced prefix from the data of the origin TXT record.
+func parseOriginInfo(data string) (int, string, error) {
+	fields := strings.Split(data, " | ")
+	if len(fields) < 2 {
+		return 0, "", fmt.Errorf("originLookup: Failed to parse data: %s", data)
+	}
+	// Prefixes announced by multiple autonomous systems list each ASN
+	origins := strings.Fields(fields[0])
+	if len(origins) == 0 {
+		return 0, "", fmt.Errorf("originLookup: Failed to extract the ASN: %s", data)
+	}
+
+	asn, err := strconv.Atoi(origins[0])
 	if err != nil {
-		return 0, "", fmt.Errorf("originLookup: Failed to extract the ASN: %s: %v", fields[0], err)
+		return 0, "", fmt.Errorf("originLookup: Failed to extract the ASN: %s: %v", origins[0], err)
 	}
-	return asn, strings.TrimSpace(fields[1]), nil
+
+	_, ipnet, err := net.ParseCIDR(strings.TrimSpace(fields[1]))
+	if err != nil {
+		return 0, "", fmt.Errorf("originLookup: Failed to extract the prefix: %s: %v", fields[1], err)
+	}
+	return asn, ipnet.String(), nil
 }
 
 func asnLookup(asn int) (*ASRecord, error) {
@@ -274,7 +233,7 @@ func asnLookup(asn int) (*ASRecord, error) {
 	var answers []core.DNSAnswer
 	name := "AS" + strconv.Itoa(asn) + ".asn.cymru.com"
 
-	answers, err = dnssrv.Resolve(name, "TXT")
+	answers, err = resolveName(name, "TXT")
 	if err != nil {
 		return nil, fmt.Errorf("asnLookup: DNS TXT record query error: %s: %v", name, err)
 	}
@@ -328,7 +287,7 @@ func nameToAddress(name string) string {
 	core.MaxConnections.Acquire(1)
 	defer core.MaxConnections.Release(1)
 
-	answers, err := dnssrv.Resolve(name, "A")
+	answers, err := resolveName(name, "A")
 	if err != nil {
 		return ""
 	}
@@ -363,38 +322,73 @@ func parseASNInfo(line string) *ASRecord {
 // LookupASNsByName returns ASRecord objects for autonomous systems with
 // descriptions that contain the string provided by the parameter.
 func LookupASNsByName(s string) ([]ASRecord, error) {
-	var asns []int
 	var records []ASRecord
 
+	// Search the local ASN databases before the online list
+	asns := localASNData.searchDescriptions(s)
+	if len(asns) == 0 {
+		var err error
+
+		asns, err = lookupOnlineASNsByName(s)
+		if err != nil {
+			return records, err
+		}
+	}
+
+	for _, asn := range asns {
+		if a, err := ASNRequest(asn); err == nil {
+			records = append(records, *a)
+		}
+	}
+	return records, nil
+}
+
+func lookupOnlineASNsByName(s string) ([]int, error) {
+	var asns []int
+
+	list, err := onlineASNList()
+	if err != nil {
+		return asns, err
+	}
+
 	s = strings.ToLower(s)
+	for _, entry := range list {
+		if strings.Contains(strings.ToLower(entry.Description), s) {
+			asns = append(asns, entry.ASN)
+		}
+	}
+	return asns, nil
+}
+
+// onlineASNList returns the list of autonomous systems, which is only downloaded once.
+func onlineASNList() ([]ASRecord, error) {
+	asnListLock.Lock()
+	defer asnListLock.Unlock()
+
+	if asnList != nil {
+		return asnList, nil
+	}
+
 	url := "https://raw.githubusercontent.com/OWASP/Amass/master/wordlists/asnlist.txt"
 	page, err := utils.RequestWebPage(url, nil, nil, "", "")
 	if err != nil {
-		return records, err
+		return nil, err
 	}
 
+	list := []ASRecord{}
 	scanner := bufio.NewScanner(strings.NewReader(page))
 	for scanner.Scan() {
-		line := scanner.Text()
-
-		if err := scanner.Err(); err == nil {
-			parts := strings.Split(strings.TrimSpace(line), ",")
-
-			if strings.Contains(strings.ToLower(parts[1]), s) {
-				a, err := strconv.Atoi(parts[0])
-				if err == nil {
-					asns = append(asns, a)
-				}
-			}
+		parts := strings.SplitN(strings.TrimSpace(scanner.Text()), ",", 2)
+		if len(parts) != 2 {
+			continue
 		}
-	}
 
-	for _, asn := range asns {
-		if a, err := ASNRequest(asn); err == nil {
-			records = append(records, *a)
+		if a, err := strconv.Atoi(parts[0]); err == nil {
+			list = append(list, ASRecord{ASN: a, Description: parts[1]})
 		}
 	}
-	return records, nil
+	asnList = list
+	return asnList, nil
 }
 
 // LookupIPHistory attempts to obtain IP addresses used by a root domain name
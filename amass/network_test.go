@@ -0,0 +1,164 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net"
+	"testing"
+
+	"github.com/OWASP/Amass/amass/core"
+	evbus "github.com/asaskevich/EventBus"
+)
+
+func TestSubdomainToDomain(t *testing.T) {
+	tests := map[string]string{
+		"www.example.com":            "example.com",
+		"a.b.delegated.example.com.": "example.com",
+		"WWW.Example.CO.UK":          "example.co.uk",
+		"*.api.example.com":          "example.com",
+		"project.github.io":          "project.github.io",
+		"bucket.s3.amazonaws.com":    "bucket.s3.amazonaws.com",
+		"host.example.unknowntld":    "example.unknowntld",
+		"example.com":                "example.com",
+		"co.uk":                      "",
+		"192.0.2.1":                  "",
+		"":                           "",
+	}
+
+	for name, expected := range tests {
+		if domain := SubdomainToDomain(name); domain != expected {
+			t.Errorf("SubdomainToDomain(%q) returned %q instead of %q", name, domain, expected)
+		}
+	}
+}
+
+func TestOriginName(t *testing.T) {
+	tests := map[string]string{
+		"192.0.2.10":        "10.2.0.192.origin.asn.cymru.com",
+		"::ffff:192.0.2.10": "10.2.0.192.origin.asn.cymru.com",
+		"2001:db8::1": "1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0." +
+			"8.b.d.0.1.0.0.2.origin6.asn.cymru.com",
+	}
+
+	for addr, expected := range tests {
+		if name, err := originName(net.ParseIP(addr)); err != nil || name != expected {
+			t.Errorf("originName(%s) returned %s and %v instead of %s", addr, name, err, expected)
+		}
+	}
+	if _, err := originName(nil); err == nil {
+		t.Errorf("originName did not return an error for an invalid address")
+	}
+}
+
+func TestParseOriginInfo(t *testing.T) {
+	tests := []struct {
+		data string
+		asn  int
+		cidr string
+	}{
+		{"64500 | 192.0.2.0/24 | US | arin | 2010-01-01", 64500, "192.0.2.0/24"},
+		{"64501 | 2001:db8::/32 | NL | ripencc | 2004-05-06", 64501, "2001:db8::/32"},
+		// The first origin of prefixes announced by multiple autonomous systems is used
+		{"64502 64503 | 2001:db8:1::/48 | US | arin | ", 64502, "2001:db8:1::/48"},
+	}
+
+	for _, test := range tests {
+		asn, cidr, err := parseOriginInfo(test.data)
+		if err != nil || asn != test.asn || cidr != test.cidr {
+			t.Errorf("parseOriginInfo(%q) returned %d, %s and %v", test.data, asn, cidr, err)
+		}
+	}
+	for _, data := range []string{"", "NA | 192.0.2.0/24", "64500 | not-a-prefix"} {
+		if _, _, err := parseOriginInfo(data); err == nil {
+			t.Errorf("parseOriginInfo(%q) did not return an error", data)
+		}
+	}
+}
+
+// useTestInfrastructure replaces the online DNS lookups with the TXT records provided, and
+// uses an empty cache until the returned function restores the infrastructure data.
+func useTestInfrastructure(records map[string]string) func() {
+	cache, resolve := netCache, resolveName
+
+	netCache = newInfraCache(DefaultInfraCacheTTL)
+	resolveName = func(name, qtype string) ([]core.DNSAnswer, error) {
+		if data, found := records[name]; found && qtype == "TXT" {
+			return []core.DNSAnswer{{Name: name, Type: 16, Data: data}}, nil
+		}
+		return nil, errors.New("NXDOMAIN")
+	}
+	return func() { netCache, resolveName = cache, resolve }
+}
+
+func TestIPv6OriginLookup(t *testing.T) {
+	name, _ := originName(net.ParseIP("2001:db8:1::5"))
+	defer useTestInfrastructure(map[string]string{
+		name: "64501 | 2001:db8::/32 | NL | ripencc | 2004-05-06",
+	})()
+	// The record of the autonomous system has been obtained for another netblock
+	netCache.insert(&ASRecord{ASN: 64501, Description: "EXAMPLE-NET", Netblocks: []string{"198.51.100.0/24"}})
+
+	if asn, cidr, err := originLookup("2001:db8:1::5"); err != nil || asn != 64501 || cidr != "2001:db8::/32" {
+		t.Errorf("originLookup returned AS%d, %s and %v", asn, cidr, err)
+	}
+
+	asn, cidr, desc, err := IPRequest("2001:db8:1::5")
+	if err != nil || asn != 64501 || cidr.String() != "2001:db8::/32" || desc != "EXAMPLE-NET" {
+		t.Errorf("IPRequest returned AS%d, %v, %s and %v", asn, cidr, desc, err)
+	}
+	if r := netCache.record(64501); r == nil || len(r.Netblocks) != 2 {
+		t.Errorf("The IPv6 prefix was not added to the ASN record: %+v", r)
+	}
+	// Other addresses within the prefix do not require another lookup
+	if asn, cidr, _, err := IPRequest("2001:db8:ffff::1"); err != nil || asn != 64501 || cidr.String() != "2001:db8::/32" {
+		t.Errorf("IPRequest returned AS%d and %v for another address in the prefix: %v", asn, cidr, err)
+	}
+
+	if _, _, _, err := IPRequest("2001:db9::1"); err == nil {
+		t.Errorf("IPRequest did not return an error for an address without an origin")
+	}
+}
+
+func TestIPv6InfrastructureSweep(t *testing.T) {
+	name, _ := originName(net.ParseIP("2001:db8:1::5"))
+	defer useTestInfrastructure(map[string]string{
+		name: "64501 | 2001:db8::/32 | NL | ripencc | 2004-05-06",
+	})()
+	netCache.insert(&ASRecord{ASN: 64501, Description: "EXAMPLE-NET", Netblocks: []string{"198.51.100.0/24"}})
+
+	var buf bytes.Buffer
+	swept := make(map[string]string)
+	bus := evbus.New()
+	bus.Subscribe(core.DNSSWEEP, func(addr string, cidr *net.IPNet) {
+		swept[addr] = cidr.String()
+	})
+	dms := NewDataManagerService(&core.AmassConfig{Log: log.New(&buf, "", 0)}, bus)
+
+	tests := []struct {
+		addr string
+		cidr string
+	}{
+		// The announced prefix is swept
+		{"2001:db8:1::5", "2001:db8::/32"},
+		// Addresses without an origin are swept within their /64 subnet
+		{"2001:db9:0:1::5", "2001:db9:0:1::/64"},
+	}
+
+	for _, test := range tests {
+		dms.insertAAAA(&core.AmassRequest{
+			Name:    "www.example.com",
+			Domain:  "example.com",
+			Records: []core.DNSAnswer{{Name: "www.example.com", Type: 28, Data: test.addr}},
+			Tag:     core.DNS,
+			Source:  "Forward DNS",
+		}, 0)
+
+		if cidr := swept[test.addr]; cidr != test.cidr {
+			t.Errorf("The AAAA record for %s caused a sweep of %q instead of %s", test.addr, cidr, test.cidr)
+		}
+	}
+}
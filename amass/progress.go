@@ -0,0 +1,146 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// The number of seconds used to calculate the recent rates
+const progressWindow = 10
+
+// PhaseProgress describes the work performed by a phase of the enumeration.
+type PhaseProgress struct {
+	// The number of names that have been attempted
+	Tried uint64
+
+	// The number of names expected to be attempted, including the names already tried
+	Total uint64
+
+	// The number of items waiting to be processed by the phase
+	Queued int
+
+	// The number of names attempted each second recently
+	Rate float64
+
+	// The number of names that have not been attempted yet
+	Remaining uint64
+
+	// The estimated time required to attempt the remaining names (zero when unknown)
+	ETA time.Duration
+}
+
+// Progress is a snapshot of the work performed by an enumeration.
+type Progress struct {
+	BruteForce  PhaseProgress
+	Alterations PhaseProgress
+
+	// The number of names that have been resolved
+	Resolved uint64
+
+	// The number of names resolved each second recently
+	ResolvedRate float64
+}
+
+// progressCounter tracks the completed work and the recent rate of completion.
+type progressCounter struct {
+	sync.Mutex
+
+	start   time.Time
+	done    uint64
+	total   uint64
+	buckets [progressWindow]uint64
+	stamps  [progressWindow]int64
+}
+
+func newProgressCounter() *progressCounter {
+	return &progressCounter{start: time.Now()}
+}
+
+// AddTotal increases the amount of work expected to be completed.
+func (pc *progressCounter) AddTotal(n uint64) {
+	pc.Lock()
+	defer pc.Unlock()
+
+	// Saturate instead of overflowing
+	if pc.total > math.MaxUint64-n {
+		pc.total = math.MaxUint64
+		return
+	}
+	pc.total += n
+}
+
+// Inc records the completion of one unit of work.
+func (pc *progressCounter) Inc() {
+	pc.Lock()
+	defer pc.Unlock()
+
+	now := time.Now().Unix()
+	idx := now % progressWindow
+	if pc.stamps[idx] != now {
+		pc.stamps[idx] = now
+		pc.buckets[idx] = 0
+	}
+	pc.buckets[idx]++
+	pc.done++
+}
+
+// Done returns the amount of completed work.
+func (pc *progressCounter) Done() uint64 {
+	pc.Lock()
+	defer pc.Unlock()
+
+	return pc.done
+}
+
+// Rate returns the average amount of work completed each second, not including the current second.
+func (pc *progressCounter) Rate() float64 {
+	pc.Lock()
+	defer pc.Unlock()
+
+	now := time.Now()
+	var sum uint64
+	for i, stamp := range pc.stamps {
+		if age := now.Unix() - stamp; age >= 1 && age <= progressWindow {
+			sum += pc.buckets[i]
+		}
+	}
+
+	secs := int64(now.Sub(pc.start).Seconds())
+	if secs > progressWindow {
+		secs = progressWindow
+	}
+	if secs < 1 {
+		return 0
+	}
+	return float64(sum) / float64(secs)
+}
+
+// Snapshot returns the progress of the phase, where queued additional names are expected.
+func (pc *progressCounter) Snapshot(queued int, extra uint64) PhaseProgress {
+	rate := pc.Rate()
+
+	pc.Lock()
+	defer pc.Unlock()
+
+	p := PhaseProgress{
+		Tried:  pc.done,
+		Total:  pc.total + extra,
+		Queued: queued,
+		Rate:   rate,
+	}
+	if p.Total < pc.total {
+		p.Total = math.MaxUint64
+	}
+	if p.Total < p.Tried {
+		p.Total = p.Tried
+	}
+	p.Remaining = p.Total - p.Tried
+	if secs := float64(p.Remaining) / rate; rate > 0 && secs < float64(math.MaxInt64/int64(time.Second)) {
+		p.ETA = time.Duration(secs) * time.Second
+	}
+	return p
+}
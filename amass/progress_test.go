@@ -0,0 +1,57 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"math"
+	"testing"
+
+	"github.com/OWASP/Amass/amass/core"
+	evbus "github.com/asaskevich/EventBus"
+)
+
+func TestProgressCounterSnapshot(t *testing.T) {
+	pc := newProgressCounter()
+	pc.AddTotal(10)
+	for i := 0; i < 4; i++ {
+		pc.Inc()
+	}
+
+	p := pc.Snapshot(2, 5)
+	if p.Tried != 4 || p.Total != 15 || p.Remaining != 11 || p.Queued != 2 {
+		t.Errorf("Snapshot returned unexpected progress: %+v", p)
+	}
+
+	// Large word generators must not cause the totals to overflow
+	pc.AddTotal(math.MaxUint64)
+	if p = pc.Snapshot(0, 1); p.Total != math.MaxUint64 {
+		t.Errorf("Snapshot returned the total %d instead of saturating", p.Total)
+	}
+}
+
+func TestBruteForceProgressTotal(t *testing.T) {
+	bfs := NewBruteForceService(&core.AmassConfig{BruteForcing: true}, evbus.New())
+	bfs.generators = []WordGenerator{NewWordlistGenerator([]string{"a", "b", "c"})}
+
+	// The total is unknown until the words have been counted
+	bfs.addTotal(bfs.generators)
+	if p := bfs.Progress(); p.Total != math.MaxUint64 {
+		t.Errorf("Progress returned the total %d before the words were counted", p.Total)
+	}
+
+	bfs.countWords()
+	if p := bfs.Progress(); p.Total != 3 {
+		t.Errorf("Progress returned the total %d instead of 3 after the words were counted", p.Total)
+	}
+	bfs.addTotal(bfs.generators)
+	if p := bfs.Progress(); p.Total != 6 {
+		t.Errorf("Progress returned the total %d instead of 6", p.Total)
+	}
+
+	// The total is zero without brute forcing
+	bfs = NewBruteForceService(&core.AmassConfig{}, evbus.New())
+	if p := bfs.Progress(); p.Total != 0 {
+		t.Errorf("Progress returned the total %d without brute forcing", p.Total)
+	}
+}
@@ -0,0 +1,576 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OWASP/Amass/amass/core"
+	"github.com/OWASP/Amass/amass/utils"
+)
+
+// DefaultRDAPBootstrapURL is the location of the IANA RDAP bootstrap files.
+const DefaultRDAPBootstrapURL = "https://data.iana.org/rdap/"
+
+const (
+	rdapTimeout = 30 * time.Second
+
+	// The time before a bootstrap file that could not be obtained is requested again
+	rdapBootstrapRetry = 5 * time.Minute
+
+	// The largest RDAP response that will be read
+	maxRDAPResponseSize = 4 << 20
+)
+
+// RDAPRecord stores the registration data returned by an RDAP server for an IP
+// network, autonomous system or domain name.
+type RDAPRecord struct {
+	// The RDAP object class: ip network, autnum or domain
+	Type         string
+	Handle       string
+	Name         string
+	Country      string
+	Organization string
+	AbuseEmails  []string
+	Status       []string
+
+	// The allocation range of IP networks
+	StartAddress string
+	EndAddress   string
+	Netblocks    []string
+
+	// The allocation range of autonomous systems
+	StartASN int
+	EndASN   int
+
+	// The name servers of domain names
+	Nameservers []string
+
+	Registration time.Time
+	Expiration   time.Time
+	LastChanged  time.Time
+
+	// The URL that provided the registration data
+	URL string
+}
+
+// RDAPClient retrieves registration data from the RDAP servers listed in the IANA bootstrap files.
+type RDAPClient struct {
+	sync.Mutex
+
+	// The location of the dns.json, asn.json, ipv4.json and ipv6.json bootstrap files
+	BootstrapURL string
+
+	bootstrap map[string]*rdapBootstrap
+}
+
+// rdapBootstrap holds the entries of a bootstrap file, or the error from the last attempt to download it.
+type rdapBootstrap struct {
+	// Closed when the download in progress has completed
+	done     chan struct{}
+	loaded   bool
+	services []rdapService
+	err      error
+	retry    time.Time
+}
+
+// rdapService is an entry of a bootstrap file, which maps the entries to the RDAP server URLs.
+type rdapService struct {
+	Entries []string
+	URLs    []string
+}
+
+// The client used by the package level RDAP request functions
+var defaultRDAPClient = NewRDAPClient(DefaultRDAPBootstrapURL)
+
+// NewRDAPClient returns a client that uses the bootstrap files at the URL provided.
+func NewRDAPClient(bootstrapURL string) *RDAPClient {
+	return &RDAPClient{
+		BootstrapURL: bootstrapURL,
+		bootstrap:    make(map[string]*rdapBootstrap),
+	}
+}
+
+// RDAPIPRequest returns the registration data for the IP network containing the address.
+func RDAPIPRequest(addr string) (*RDAPRecord, error) {
+	return defaultRDAPClient.IP(addr)
+}
+
+// RDAPASNRequest returns the registration data for the autonomous system.
+func RDAPASNRequest(asn int) (*RDAPRecord, error) {
+	return defaultRDAPClient.ASN(asn)
+}
+
+// RDAPDomainRequest returns the registration data for the domain name.
+func RDAPDomainRequest(domain string) (*RDAPRecord, error) {
+	return defaultRDAPClient.Domain(domain)
+}
+
+// IP returns the registration data for the IP network containing the address.
+func (c *RDAPClient) IP(addr string) (*RDAPRecord, error) {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return nil, fmt.Errorf("RDAP: %s is not a valid IP address", addr)
+	}
+
+	file := "ipv6.json"
+	if ip.To4() != nil {
+		file = "ipv4.json"
+	}
+	services, err := c.services(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var base string
+	var longest int
+	for _, s := range services {
+		for _, entry := range s.Entries {
+			_, ipnet, err := net.ParseCIDR(entry)
+			if err != nil || !ipnet.Contains(ip) {
+				continue
+			}
+			if ones, _ := ipnet.Mask.Size(); base == "" || ones > longest {
+				base = preferredRDAPURL(s.URLs)
+				longest = ones
+			}
+		}
+	}
+	if base == "" {
+		return nil, fmt.Errorf("RDAP: No server was found for %s", addr)
+	}
+	return c.query(base + "ip/" + ip.String())
+}
+
+// ASN returns the registration data for the autonomous system.
+func (c *RDAPClient) ASN(asn int) (*RDAPRecord, error) {
+	services, err := c.services("asn.json")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range services {
+		for _, entry := range s.Entries {
+			first, last, err := parseASNRange(entry)
+			if err == nil && asn >= first && asn <= last {
+				return c.query(preferredRDAPURL(s.URLs) + "autnum/" + strconv.Itoa(asn))
+			}
+		}
+	}
+	return nil, fmt.Errorf("RDAP: No server was found for AS%d", asn)
+}
+
+// Domain returns the registration data for the domain name. The registrar RDAP server
+// is consulted when the registry does not provide the registrant organization.
+func (c *RDAPClient) Domain(domain string) (*RDAPRecord, error) {
+	domain = strings.ToLower(strings.Trim(domain, "."))
+
+	services, err := c.services("dns.json")
+	if err != nil {
+		return nil, err
+	}
+
+	var base, suffix string
+	for _, s := range services {
+		for _, entry := range s.Entries {
+			entry = strings.ToLower(entry)
+			if (domain == entry || strings.HasSuffix(domain, "."+entry)) && len(entry) > len(suffix) {
+				base = preferredRDAPURL(s.URLs)
+				suffix = entry
+			}
+		}
+	}
+	if base == "" {
+		return nil, fmt.Errorf("RDAP: No server was found for %s", domain)
+	}
+
+	obj, err := c.fetch(base + "domain/" + domain)
+	if err != nil {
+		return nil, err
+	}
+	record := parseRDAPObject(obj)
+	record.URL = base + "domain/" + domain
+	if record.Organization != "" {
+		return record, nil
+	}
+
+	// Thin registries refer to the registrar for the registrant details
+	if related := obj.link("related"); related != "" {
+		if robj, err := c.fetch(related); err == nil {
+			if r := parseRDAPObject(robj); r.Organization != "" {
+				record.Organization = r.Organization
+				record.AbuseEmails = utils.UniqueAppend(record.AbuseEmails, r.AbuseEmails...)
+			}
+		}
+	}
+	return record, nil
+}
+
+func (c *RDAPClient) query(url string) (*RDAPRecord, error) {
+	obj, err := c.fetch(url)
+	if err != nil {
+		return nil, err
+	}
+
+	record := parseRDAPObject(obj)
+	record.URL = url
+	return record, nil
+}
+
+func (c *RDAPClient) fetch(url string) (*rdapObject, error) {
+	body, err := c.get(url)
+	if err != nil {
+		return nil, err
+	}
+
+	var obj rdapObject
+	if err := json.Unmarshal(body, &obj); err != nil {
+		return nil, fmt.Errorf("RDAP: Failed to parse the response from %s: %v", url, err)
+	}
+	if obj.ErrorCode != 0 {
+		return nil, fmt.Errorf("RDAP: %s returned error %d: %s", url, obj.ErrorCode, obj.Title)
+	}
+	return &obj, nil
+}
+
+func (c *RDAPClient) get(url string) ([]byte, error) {
+	core.MaxConnections.Acquire(1)
+	defer core.MaxConnections.Release(1)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	utils.SetHTTPRequestHeaders(req, map[string]string{"Accept": "application/rdap+json, application/json"})
+
+	// The client is obtained for each request, so the proxy and CA bundle configured later are honored
+	resp, err := utils.NewHTTPClient(rdapTimeout).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("RDAP: %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(ioutil.Discard, resp.Body)
+		return nil, fmt.Errorf("RDAP: %s returned %s", url, resp.Status)
+	}
+	return ioutil.ReadAll(io.LimitReader(resp.Body, maxRDAPResponseSize))
+}
+
+// services returns the entries of the bootstrap file, which is only downloaded once.
+// Callers wait for a download in progress, and failures are reused until the retry time.
+func (c *RDAPClient) services(file string) ([]rdapService, error) {
+	c.Lock()
+	b, found := c.bootstrap[file]
+	if !found {
+		b = new(rdapBootstrap)
+		c.bootstrap[file] = b
+	}
+	for b.done != nil {
+		done := b.done
+		c.Unlock()
+		<-done
+		c.Lock()
+	}
+	if b.loaded {
+		c.Unlock()
+		return b.services, nil
+	}
+	if b.err != nil && time.Now().Before(b.retry) {
+		c.Unlock()
+		return nil, b.err
+	}
+	done := make(chan struct{})
+	b.done = done
+	c.Unlock()
+
+	// The lock is not held during the download, so requests for other files are not delayed
+	services, err := c.download(file)
+
+	c.Lock()
+	if err != nil {
+		b.err = err
+		b.retry = time.Now().Add(rdapBootstrapRetry)
+	} else {
+		b.loaded = true
+		b.services = services
+		b.err = nil
+	}
+	b.done = nil
+	close(done)
+	c.Unlock()
+	return services, err
+}
+
+// download obtains and parses the bootstrap file.
+func (c *RDAPClient) download(file string) ([]rdapService, error) {
+	url := strings.TrimRight(c.BootstrapURL, "/") + "/" + file
+	body, err := c.get(url)
+	if err != nil {
+		return nil, err
+	}
+
+	var bootstrap struct {
+		Services [][][]string `json:"services"`
+	}
+	if err := json.Unmarshal(body, &bootstrap); err != nil {
+		return nil, fmt.Errorf("RDAP: Failed to parse the bootstrap file %s: %v", url, err)
+	}
+
+	var services []rdapService
+	for _, s := range bootstrap.Services {
+		if len(s) < 2 {
+			continue
+		}
+		services = append(services, rdapService{Entries: s[0], URLs: s[1]})
+	}
+	return services, nil
+}
+
+// preferredRDAPURL selects the HTTPS server URL when available.
+func preferredRDAPURL(urls []string) string {
+	var selected string
+
+	for _, u := range urls {
+		if selected == "" || strings.HasPrefix(u, "https://") {
+			selected = u
+		}
+		if strings.HasPrefix(selected, "https://") {
+			break
+		}
+	}
+	if selected != "" && !strings.HasSuffix(selected, "/") {
+		selected += "/"
+	}
+	return selected
+}
+
+// parseASNRange parses the ASN bootstrap entries, such as 1-1876 or 64512.
+func parseASNRange(entry string) (int, int, error) {
+	parts := strings.SplitN(entry, "-", 2)
+
+	first, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, err
+	}
+	last := first
+	if len(parts) == 2 {
+		if last, err = strconv.Atoi(strings.TrimSpace(parts[1])); err != nil {
+			return 0, 0, err
+		}
+	}
+	if last < first {
+		return 0, 0, errors.New("The ASN range is invalid")
+	}
+	return first, last, nil
+}
+
+// rdapObject contains the fields of the RDAP responses for IP networks, autonomous systems and domains.
+type rdapObject struct {
+	ObjectClassName string        `json:"objectClassName"`
+	Handle          string        `json:"handle"`
+	Name            string        `json:"name"`
+	LDHName         string        `json:"ldhName"`
+	Country         string        `json:"country"`
+	StartAddress    string        `json:"startAddress"`
+	EndAddress      string        `json:"endAddress"`
+	StartAutnum     int           `json:"startAutnum"`
+	EndAutnum       int           `json:"endAutnum"`
+	Status          []string      `json:"status"`
+	Entities        []rdapEntity  `json:"entities"`
+	Events          []rdapEvent   `json:"events"`
+	Links           []rdapLink    `json:"links"`
+	Nameservers     []rdapObject  `json:"nameservers"`
+	CIDRs           []rdapCIDR    `json:"cidr0_cidrs"`
+	ErrorCode       int           `json:"errorCode"`
+	Title           string        `json:"title"`
+	VCardArray      []interface{} `json:"vcardArray"`
+}
+
+type rdapEntity struct {
+	Handle     string        `json:"handle"`
+	Roles      []string      `json:"roles"`
+	VCardArray []interface{} `json:"vcardArray"`
+	Entities   []rdapEntity  `json:"entities"`
+}
+
+type rdapEvent struct {
+	Action string `json:"eventAction"`
+	Date   string `json:"eventDate"`
+}
+
+type rdapLink struct {
+	Rel  string `json:"rel"`
+	Href string `json:"href"`
+	Type string `json:"type"`
+}
+
+type rdapCIDR struct {
+	V4Prefix string `json:"v4prefix"`
+	V6Prefix string `json:"v6prefix"`
+	Length   int    `json:"length"`
+}
+
+// link returns the RDAP URL of the link with the relation type provided.
+func (o *rdapObject) link(rel string) string {
+	for _, l := range o.Links {
+		if l.Rel == rel && l.Href != "" && (l.Type == "" || strings.Contains(l.Type, "rdap")) {
+			return l.Href
+		}
+	}
+	return ""
+}
+
+func parseRDAPObject(obj *rdapObject) *RDAPRecord {
+	record := &RDAPRecord{
+		Type:         obj.ObjectClassName,
+		Handle:       obj.Handle,
+		Name:         obj.Name,
+		Country:      obj.Country,
+		Status:       obj.Status,
+		StartAddress: obj.StartAddress,
+		EndAddress:   obj.EndAddress,
+		StartASN:     obj.StartAutnum,
+		EndASN:       obj.EndAutnum,
+	}
+	if obj.LDHName != "" {
+		record.Name = strings.ToLower(obj.LDHName)
+	}
+
+	for _, ns := range obj.Nameservers {
+		if ns.LDHName != "" {
+			record.Nameservers = append(record.Nameservers, strings.ToLower(strings.Trim(ns.LDHName, ".")))
+		}
+	}
+
+	for _, e := range obj.Events {
+		t, err := time.Parse(time.RFC3339, e.Date)
+		if err != nil {
+			continue
+		}
+
+		switch e.Action {
+		case "registration":
+			record.Registration = t
+		case "expiration":
+			record.Expiration = t
+		case "last changed":
+			record.LastChanged = t
+		}
+	}
+
+	for _, c := range obj.CIDRs {
+		prefix := c.V4Prefix
+		if prefix == "" {
+			prefix = c.V6Prefix
+		}
+		if _, ipnet, err := net.ParseCIDR(prefix + "/" + strconv.Itoa(c.Length)); err == nil {
+			record.Netblocks = append(record.Netblocks, ipnet.String())
+		}
+	}
+	if len(record.Netblocks) == 0 && obj.StartAddress != "" && obj.EndAddress != "" {
+		first, last := net.ParseIP(obj.StartAddress), net.ParseIP(obj.EndAddress)
+		if first != nil && last != nil {
+			for _, cidr := range rangeToCIDRs(first, last) {
+				record.Netblocks = append(record.Netblocks, cidr.String())
+			}
+		}
+	}
+
+	record.Organization = registrantOrganization(obj.Entities)
+	record.AbuseEmails = abuseEmails(obj.Entities)
+	sort.Strings(record.AbuseEmails)
+	return record
+}
+
+// registrantOrganization returns the organization of the registrant entity.
+func registrantOrganization(entities []rdapEntity) string {
+	for _, e := range entities {
+		if !hasRDAPRole(e, "registrant") {
+			continue
+		}
+		if org := vcardValue(e.VCardArray, "org"); org != "" {
+			return org
+		}
+		if fn := vcardValue(e.VCardArray, "fn"); fn != "" {
+			return fn
+		}
+	}
+	// The registrant can be nested within other entities
+	for _, e := range entities {
+		if org := registrantOrganization(e.Entities); org != "" {
+			return org
+		}
+	}
+	return ""
+}
+
+// abuseEmails returns the email addresses of all the abuse entities, including nested entities.
+func abuseEmails(entities []rdapEntity) []string {
+	var emails []string
+
+	for _, e := range entities {
+		if hasRDAPRole(e, "abuse") {
+			for _, email := range vcardValues(e.VCardArray, "email") {
+				emails = utils.UniqueAppend(emails, email)
+			}
+		}
+		emails = utils.UniqueAppend(emails, abuseEmails(e.Entities)...)
+	}
+	return emails
+}
+
+func hasRDAPRole(e rdapEntity, role string) bool {
+	for _, r := range e.Roles {
+		if strings.EqualFold(r, role) {
+			return true
+		}
+	}
+	return false
+}
+
+func vcardValue(vcard []interface{}, property string) string {
+	if values := vcardValues(vcard, property); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// vcardValues returns the text values of the jCard property, such as ["fn", {}, "text", "Example Inc."].
+func vcardValues(vcard []interface{}, property string) []string {
+	var values []string
+
+	if len(vcard) < 2 {
+		return values
+	}
+	props, ok := vcard[1].([]interface{})
+	if !ok {
+		return values
+	}
+
+	for _, p := range props {
+		prop, ok := p.([]interface{})
+		if !ok || len(prop) < 4 {
+			continue
+		}
+		if name, ok := prop[0].(string); !ok || !strings.EqualFold(name, property) {
+			continue
+		}
+		if value, ok := prop[3].(string); ok && strings.TrimSpace(value) != "" {
+			values = append(values, strings.TrimSpace(value))
+		}
+	}
+	return values
+}
@@ -0,0 +1,262 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/amass/utils"
+)
+
+const testRDAPNetwork = `{
+  "objectClassName": "ip network",
+  "handle": "NET-192-0-2-0-1",
+  "startAddress": "192.0.2.0",
+  "endAddress": "192.0.2.255",
+  "name": "EXAMPLE-NET",
+  "country": "US",
+  "cidr0_cidrs": [{"v4prefix": "192.0.2.0", "length": 24}],
+  "events": [{"eventAction": "registration", "eventDate": "2009-01-02T03:04:05Z"}],
+  "entities": [{
+    "objectClassName": "entity",
+    "handle": "EXAMPLE-ORG",
+    "roles": ["registrant"],
+    "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "Example Networks, Inc."]]],
+    "entities": [{
+      "objectClassName": "entity",
+      "roles": ["abuse"],
+      "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["email", {}, "text", "abuse@example.net"]]]
+    }]
+  }]
+}`
+
+const testRDAPAutnum = `{
+  "objectClassName": "autnum",
+  "handle": "AS64500",
+  "startAutnum": 64500,
+  "endAutnum": 64500,
+  "name": "EXAMPLE-AS",
+  "entities": [{
+    "roles": ["registrant", "administrative"],
+    "vcardArray": ["vcard", [["org", {}, "text", "Example AS Holdings"]]]
+  }, {
+    "roles": ["abuse"],
+    "vcardArray": ["vcard", [["email", {}, "text", "noc@example.net"], ["email", {}, "text", "abuse@example.net"]]]
+  }]
+}`
+
+// The registry does not provide the registrant, which is found at the registrar
+const testRDAPDomain = `{
+  "objectClassName": "domain",
+  "handle": "123_DOMAIN_COM-VRSN",
+  "ldhName": "EXAMPLE.COM",
+  "status": ["client transfer prohibited"],
+  "nameservers": [{"objectClassName": "nameserver", "ldhName": "A.IANA-SERVERS.NET"}],
+  "events": [
+    {"eventAction": "registration", "eventDate": "1995-08-14T04:00:00Z"},
+    {"eventAction": "expiration", "eventDate": "2030-08-13T04:00:00Z"}
+  ],
+  "links": [{"rel": "related", "type": "application/rdap+json", "href": "%s/registrar/domain/example.com"}]
+}`
+
+const testRDAPRegistrar = `{
+  "objectClassName": "domain",
+  "ldhName": "example.com",
+  "entities": [{
+    "roles": ["registrant"],
+    "vcardArray": ["vcard", [["fn", {}, "text", "Example Registrant"]]]
+  }]
+}`
+
+func newTestRDAPServer() *httptest.Server {
+	var ts *httptest.Server
+
+	mux := http.NewServeMux()
+	bootstrap := func(entries string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"version": "1.0", "services": [[[%s], ["%s/rdap"]]]}`, entries, ts.URL)
+		}
+	}
+	mux.HandleFunc("/bootstrap/ipv4.json", bootstrap(`"192.0.0.0/8"`))
+	mux.HandleFunc("/bootstrap/ipv6.json", bootstrap(`"2001:db8::/32"`))
+	mux.HandleFunc("/bootstrap/asn.json", bootstrap(`"64496-64511"`))
+	mux.HandleFunc("/bootstrap/dns.json", bootstrap(`"com", "net"`))
+	mux.HandleFunc("/rdap/ip/192.0.2.10", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, testRDAPNetwork)
+	})
+	mux.HandleFunc("/rdap/autnum/64500", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, testRDAPAutnum)
+	})
+	mux.HandleFunc("/rdap/domain/example.com", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, testRDAPDomain, ts.URL)
+	})
+	mux.HandleFunc("/registrar/domain/example.com", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, testRDAPRegistrar)
+	})
+	mux.HandleFunc("/rdap/domain/missing.com", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, `{"errorCode": 404, "title": "Not Found"}`)
+	})
+
+	ts = httptest.NewServer(mux)
+	return ts
+}
+
+func TestRDAPIP(t *testing.T) {
+	ts := newTestRDAPServer()
+	defer ts.Close()
+	c := NewRDAPClient(ts.URL + "/bootstrap")
+
+	record, err := c.IP("192.0.2.10")
+	if err != nil {
+		t.Fatalf("IP failed: %v", err)
+	}
+	if record.Handle != "NET-192-0-2-0-1" || record.Country != "US" || record.Organization != "Example Networks, Inc." {
+		t.Errorf("IP returned the unexpected record %+v", record)
+	}
+	if len(record.AbuseEmails) != 1 || record.AbuseEmails[0] != "abuse@example.net" {
+		t.Errorf("IP returned the abuse contacts %v", record.AbuseEmails)
+	}
+	if len(record.Netblocks) != 1 || record.Netblocks[0] != "192.0.2.0/24" || record.Registration.Year() != 2009 {
+		t.Errorf("IP returned the netblocks %v and registration %v", record.Netblocks, record.Registration)
+	}
+
+	if _, err := c.IP("10.0.0.1"); err == nil {
+		t.Errorf("IP did not return an error for an address without an RDAP server")
+	}
+}
+
+func TestRDAPASN(t *testing.T) {
+	ts := newTestRDAPServer()
+	defer ts.Close()
+	c := NewRDAPClient(ts.URL + "/bootstrap")
+
+	record, err := c.ASN(64500)
+	if err != nil {
+		t.Fatalf("ASN failed: %v", err)
+	}
+	if record.StartASN != 64500 || record.Organization != "Example AS Holdings" {
+		t.Errorf("ASN returned the unexpected record %+v", record)
+	}
+	if strings.Join(record.AbuseEmails, ",") != "abuse@example.net,noc@example.net" {
+		t.Errorf("ASN returned the abuse contacts %v", record.AbuseEmails)
+	}
+}
+
+func TestRDAPDomain(t *testing.T) {
+	ts := newTestRDAPServer()
+	defer ts.Close()
+	c := NewRDAPClient(ts.URL + "/bootstrap")
+
+	record, err := c.Domain("www.Example.com.")
+	if err == nil {
+		t.Errorf("Domain did not return an error for a name unknown to the server")
+	}
+
+	record, err = c.Domain("example.com")
+	if err != nil {
+		t.Fatalf("Domain failed: %v", err)
+	}
+	if record.Name != "example.com" || record.Organization != "Example Registrant" {
+		t.Errorf("Domain returned the unexpected record %+v", record)
+	}
+	if len(record.Nameservers) != 1 || record.Nameservers[0] != "a.iana-servers.net" {
+		t.Errorf("Domain returned the name servers %v", record.Nameservers)
+	}
+	if record.Registration.Year() != 1995 || record.Expiration.Year() != 2030 {
+		t.Errorf("Domain returned the events %v and %v", record.Registration, record.Expiration)
+	}
+
+	if _, err := c.Domain("missing.com"); err == nil {
+		t.Errorf("Domain did not return an error for a missing domain")
+	}
+}
+
+func TestPreferredRDAPURL(t *testing.T) {
+	urls := []string{"http://rdap.example.net/rdap/", "https://rdap.example.net/rdap"}
+
+	if u := preferredRDAPURL(urls); u != "https://rdap.example.net/rdap/" {
+		t.Errorf("preferredRDAPURL returned %s", u)
+	}
+	if u := preferredRDAPURL(urls[:1]); u != "http://rdap.example.net/rdap/" {
+		t.Errorf("preferredRDAPURL returned %s", u)
+	}
+}
+
+func TestRDAPBootstrapFailure(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		// Keep the download in progress while the other lookups arrive
+		time.Sleep(100 * time.Millisecond)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+	c := NewRDAPClient(ts.URL + "/bootstrap")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.ASN(64500); err == nil {
+				t.Errorf("ASN did not return an error without the bootstrap file")
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The failure is reused until the retry time
+	if _, err := c.ASN(64501); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("ASN returned the error %v instead of the cached failure", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("The bootstrap file was requested %d times instead of once", n)
+	}
+
+	c.bootstrap["asn.json"].retry = time.Now()
+	c.ASN(64500)
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("The bootstrap file was requested %d times after the retry time", n)
+	}
+}
+
+func TestRDAPProxy(t *testing.T) {
+	ts := newTestRDAPServer()
+	defer ts.Close()
+	target, _ := url.Parse(ts.URL)
+
+	var requests int32
+	rp := httputil.NewSingleHostReverseProxy(target)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		rp.ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+
+	// The proxy is configured after the client has been created
+	c := NewRDAPClient("http://rdap.example.com/bootstrap")
+	if err := utils.SetHTTPProxy(proxy.URL); err != nil {
+		t.Fatalf("SetHTTPProxy failed: %v", err)
+	}
+	defer utils.SetHTTPProxy("")
+
+	if _, err := c.IP("192.0.2.10"); err != nil {
+		t.Fatalf("IP failed through the proxy: %v", err)
+	}
+	// The bootstrap file and the registration data were requested through the proxy
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("The proxy received %d requests instead of 2", n)
+	}
+}
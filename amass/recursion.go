@@ -0,0 +1,96 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"container/heap"
+	"strings"
+)
+
+// Labels that commonly identify subdomains with many names beneath them
+var interestingLabels = []string{
+	"admin", "api", "beta", "corp", "dev", "int", "internal", "lab", "mgmt",
+	"preprod", "prod", "qa", "stage", "staging", "test", "uat", "vpn",
+}
+
+// recursionTarget is a subdomain that is a candidate for recursive brute forcing.
+type recursionTarget struct {
+	Name     string
+	Domain   string
+	Depth    int
+	Children int
+	index    int
+}
+
+// Score returns the priority of the target. Subdomains that have produced many names
+// and have interesting labels are preferred, while deeper subdomains are penalized.
+func (t *recursionTarget) Score() float64 {
+	score := float64(t.Children)
+
+	if interestingLabel(strings.Split(t.Name, ".")[0]) {
+		score *= 2
+	}
+	if t.Depth > 1 {
+		score /= float64(t.Depth)
+	}
+	return score
+}
+
+func interestingLabel(label string) bool {
+	for _, l := range interestingLabels {
+		if strings.Contains(label, l) {
+			return true
+		}
+	}
+	return false
+}
+
+// subdomainDepth returns the number of labels the subdomain has beneath the root domain.
+func subdomainDepth(sub, domain string) int {
+	if sub == domain || !strings.HasSuffix(sub, "."+domain) {
+		return 0
+	}
+	return len(strings.Split(strings.TrimSuffix(sub, "."+domain), "."))
+}
+
+// recursionQueue implements heap.Interface with the highest scored target at the top.
+type recursionQueue []*recursionTarget
+
+func (q recursionQueue) Len() int { return len(q) }
+
+func (q recursionQueue) Less(i, j int) bool {
+	if si, sj := q[i].Score(), q[j].Score(); si != sj {
+		return si > sj
+	}
+	return q[i].Name < q[j].Name
+}
+
+func (q recursionQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *recursionQueue) Push(x interface{}) {
+	t := x.(*recursionTarget)
+	t.index = len(*q)
+	*q = append(*q, t)
+}
+
+func (q *recursionQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	t := old[n-1]
+	t.index = -1
+	*q = old[:n-1]
+	return t
+}
+
+// update changes the number of children for a target within the queue.
+func (q *recursionQueue) update(t *recursionTarget, children int) {
+	t.Children = children
+	if t.index >= 0 {
+		heap.Fix(q, t.index)
+	}
+}
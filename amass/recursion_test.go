@@ -0,0 +1,48 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"container/heap"
+	"testing"
+)
+
+func TestSubdomainDepth(t *testing.T) {
+	tests := []struct {
+		sub   string
+		depth int
+	}{
+		{"example.com", 0},
+		{"dev.example.com", 1},
+		{"a.b.dev.example.com", 3},
+		{"example.org", 0},
+	}
+
+	for _, test := range tests {
+		if d := subdomainDepth(test.sub, "example.com"); d != test.depth {
+			t.Errorf("%s: returned %d instead of %d", test.sub, d, test.depth)
+		}
+	}
+}
+
+func TestRecursionQueue(t *testing.T) {
+	var q recursionQueue
+
+	shallow := &recursionTarget{Name: "www.example.com", Depth: 1, Children: 2}
+	deep := &recursionTarget{Name: "a.b.c.example.com", Depth: 3, Children: 4}
+	interesting := &recursionTarget{Name: "dev.example.com", Depth: 1, Children: 2}
+	for _, target := range []*recursionTarget{shallow, deep, interesting} {
+		heap.Push(&q, target)
+	}
+
+	// More children discovered beneath the subdomain increases the priority
+	q.update(shallow, 5)
+
+	expected := []string{"www.example.com", "dev.example.com", "a.b.c.example.com"}
+	for _, name := range expected {
+		if target := heap.Pop(&q).(*recursionTarget); target.Name != name {
+			t.Errorf("Popped %s instead of %s", target.Name, name)
+		}
+	}
+}
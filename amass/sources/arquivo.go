@@ -3,7 +3,11 @@
 
 package sources
 
-import "github.com/OWASP/Amass/amass/core"
+import (
+	"context"
+
+	"github.com/OWASP/Amass/amass/core"
+)
 
 // Arquivo is data source object type that implements the DataSource interface.
 type Arquivo struct {
@@ -21,15 +25,21 @@ func NewArquivo(srv core.AmassService) DataSource {
 
 // Query returns the subdomain names discovered when querying this data source.
 func (a *Arquivo) Query(domain, sub string) []string {
+	return a.namesFromResults(a.QueryContext(context.Background(), domain, sub))
+}
+
+// QueryContext returns the results discovered when querying this data source.
+func (a *Arquivo) QueryContext(ctx context.Context, domain, sub string) []*Result {
 	if sub == "" {
-		return []string{}
+		return nil
 	}
 
-	names, err := a.crawl(a.baseURL, domain, sub)
+	names, err := a.crawl(ctx, a.baseURL, domain, sub)
+	results := NamesToResults(names)
 	if err != nil {
-		a.Service.Config().Log.Printf("%v", err)
+		results = append(results, &Result{Err: err})
 	}
-	return names
+	return results
 }
 
 // Subdomains returns true when the data source can query for subdomain names.
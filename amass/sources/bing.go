@@ -4,6 +4,8 @@
 package sources
 
 import (
+	"context"
+	"fmt"
 	"net/url"
 	"strconv"
 	"time"
@@ -32,10 +34,16 @@ func NewBing(srv core.AmassService) DataSource {
 
 // Query returns the subdomain names discovered when querying this data source.
 func (b *Bing) Query(domain, sub string) []string {
+	return b.namesFromResults(b.QueryContext(context.Background(), domain, sub))
+}
+
+// QueryContext returns the results discovered when querying this data source.
+func (b *Bing) QueryContext(ctx context.Context, domain, sub string) []*Result {
 	var unique []string
+	var results []*Result
 
 	if domain != sub {
-		return unique
+		return results
 	}
 
 	re := utils.SubdomainRegex(domain)
@@ -47,13 +55,16 @@ loop:
 		b.Service.SetActive()
 
 		select {
+		case <-ctx.Done():
+			results = append(results, &Result{Err: ctx.Err()})
+			break loop
 		case <-b.Service.Quit():
 			break loop
 		case <-t.C:
 			u := b.urlByPageNum(domain, i)
-			page, err := utils.RequestWebPage(u, nil, nil, "", "")
+			page, err := utils.RequestWebPageContext(ctx, u, nil, nil, "", "")
 			if err != nil {
-				b.Service.Config().Log.Printf("%s: %v", u, err)
+				results = append(results, &Result{Err: fmt.Errorf("%s: %v", u, err)})
 				break
 			}
 
@@ -64,7 +75,7 @@ loop:
 			}
 		}
 	}
-	return unique
+	return append(results, NamesToResults(unique)...)
 }
 
 func (b *Bing) urlByPageNum(domain string, page int) string {
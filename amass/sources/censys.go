@@ -5,9 +5,10 @@ package sources
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
 
+	"encoding/json"
 	"github.com/OWASP/Amass/amass/core"
 	"github.com/OWASP/Amass/amass/utils"
 )
@@ -27,10 +28,15 @@ func NewCensys(srv core.AmassService) DataSource {
 
 // Query returns the subdomain names discovered when querying this data source.
 func (c *Censys) Query(domain, sub string) []string {
+	return c.namesFromResults(c.QueryContext(context.Background(), domain, sub))
+}
+
+// QueryContext returns the results discovered when querying this data source.
+func (c *Censys) QueryContext(ctx context.Context, domain, sub string) []*Result {
 	var unique []string
 
 	if domain != sub {
-		return []string{}
+		return nil
 	}
 
 	var err error
@@ -40,20 +46,19 @@ func (c *Censys) Query(domain, sub string) []string {
 
 		jsonStr, err := json.Marshal(map[string]string{"query": sub})
 		if err != nil {
-			return unique
+			return []*Result{{Err: err}}
 		}
 		body := bytes.NewBuffer(jsonStr)
 		headers := map[string]string{"Content-Type": "application/json"}
-		page, err = utils.RequestWebPage(url, body, headers, uid, secret)
+		page, err = utils.RequestWebPageContext(ctx, url, body, headers, uid, secret)
 	} else {
 		url = c.webURL(sub)
 
-		page, err = utils.RequestWebPage(url, nil, nil, "", "")
+		page, err = utils.RequestWebPageContext(ctx, url, nil, nil, "", "")
 	}
 
 	if err != nil {
-		c.Service.Config().Log.Printf("%s: %v", url, err)
-		return unique
+		return []*Result{{Err: fmt.Errorf("%s: %v", url, err)}}
 	}
 
 	c.Service.SetActive()
@@ -63,7 +68,7 @@ func (c *Censys) Query(domain, sub string) []string {
 			unique = append(unique, u...)
 		}
 	}
-	return unique
+	return NamesToResults(unique)
 }
 
 func (c *Censys) webURL(domain string) string {
@@ -4,7 +4,9 @@
 package sources
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/url"
 
 	"github.com/OWASP/Amass/amass/core"
@@ -26,24 +28,27 @@ func NewCertDB(srv core.AmassService) DataSource {
 
 // Query returns the subdomain names discovered when querying this data source.
 func (c *CertDB) Query(domain, sub string) []string {
+	return c.namesFromResults(c.QueryContext(context.Background(), domain, sub))
+}
+
+// QueryContext returns the results discovered when querying this data source.
+func (c *CertDB) QueryContext(ctx context.Context, domain, sub string) []*Result {
 	var unique []string
 
 	if domain != sub {
-		return unique
+		return nil
 	}
 
 	u := c.getURL(domain)
-	page, err := utils.RequestWebPage(u, nil, nil, "", "")
+	page, err := utils.RequestWebPageContext(ctx, u, nil, nil, "", "")
 	if err != nil {
-		c.Service.Config().Log.Printf("%s: %v", u, err)
-		return unique
+		return []*Result{{Err: fmt.Errorf("%s: %v", u, err)}}
 	}
 
 	c.Service.SetActive()
 	var names []string
 	if err := json.Unmarshal([]byte(page), &names); err != nil {
-		c.Service.Config().Log.Printf("Failed to unmarshal JSON: %v", err)
-		return unique
+		return []*Result{{Err: fmt.Errorf("Failed to unmarshal JSON: %v", err)}}
 	}
 
 	re := utils.SubdomainRegex(domain)
@@ -52,7 +57,7 @@ func (c *CertDB) Query(domain, sub string) []string {
 			unique = utils.UniqueAppend(unique, n)
 		}
 	}
-	return unique
+	return NamesToResults(unique)
 }
 
 func (c *CertDB) getURL(domain string) string {
@@ -4,6 +4,7 @@
 package sources
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/OWASP/Amass/amass/core"
@@ -25,17 +26,21 @@ func NewCertSpotter(srv core.AmassService) DataSource {
 
 // Query returns the subdomain names discovered when querying this data source.
 func (c *CertSpotter) Query(domain, sub string) []string {
+	return c.namesFromResults(c.QueryContext(context.Background(), domain, sub))
+}
+
+// QueryContext returns the results discovered when querying this data source.
+func (c *CertSpotter) QueryContext(ctx context.Context, domain, sub string) []*Result {
 	var unique []string
 
 	if domain != sub {
-		return unique
+		return nil
 	}
 
 	url := c.getURL(domain)
-	page, err := utils.RequestWebPage(url, nil, nil, "", "")
+	page, err := utils.RequestWebPageContext(ctx, url, nil, nil, "", "")
 	if err != nil {
-		c.Service.Config().Log.Printf("%s: %v", url, err)
-		return unique
+		return []*Result{{Err: fmt.Errorf("%s: %v", url, err)}}
 	}
 
 	c.Service.SetActive()
@@ -45,7 +50,7 @@ func (c *CertSpotter) Query(domain, sub string) []string {
 			unique = append(unique, u...)
 		}
 	}
-	return unique
+	return NamesToResults(unique)
 }
 
 func (c *CertSpotter) getURL(domain string) string {
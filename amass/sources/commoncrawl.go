@@ -4,6 +4,8 @@
 package sources
 
 import (
+	"context"
+	"fmt"
 	"net/url"
 	"time"
 
@@ -42,10 +44,16 @@ func NewCommonCrawl(srv core.AmassService) DataSource {
 
 // Query returns the subdomain names discovered when querying this data source.
 func (cc *CommonCrawl) Query(domain, sub string) []string {
+	return cc.namesFromResults(cc.QueryContext(context.Background(), domain, sub))
+}
+
+// QueryContext returns the results discovered when querying this data source.
+func (cc *CommonCrawl) QueryContext(ctx context.Context, domain, sub string) []*Result {
 	var unique []string
+	var results []*Result
 
 	if domain != sub {
-		return []string{}
+		return results
 	}
 
 	re := utils.SubdomainRegex(domain)
@@ -56,13 +64,16 @@ loop:
 		cc.Service.SetActive()
 
 		select {
+		case <-ctx.Done():
+			results = append(results, &Result{Err: ctx.Err()})
+			break loop
 		case <-cc.Service.Quit():
 			break loop
 		case <-t.C:
 			u := cc.getURL(index, domain)
-			page, err := utils.RequestWebPage(u, nil, nil, "", "")
+			page, err := utils.RequestWebPageContext(ctx, u, nil, nil, "", "")
 			if err != nil {
-				cc.Service.Config().Log.Printf("%s: %v", u, err)
+				results = append(results, &Result{Err: fmt.Errorf("%s: %v", u, err)})
 				continue
 			}
 
@@ -73,7 +84,7 @@ loop:
 			}
 		}
 	}
-	return unique
+	return append(results, NamesToResults(unique)...)
 }
 
 func (cc *CommonCrawl) getURL(index, domain string) string {
@@ -4,10 +4,12 @@
 package sources
 
 import (
-	"encoding/json"
+	"context"
+	"fmt"
 	"io"
 	"strings"
 
+	"encoding/json"
 	"github.com/OWASP/Amass/amass/core"
 	"github.com/OWASP/Amass/amass/utils"
 )
@@ -37,17 +39,21 @@ func NewCrtsh(srv core.AmassService) DataSource {
 
 // Query returns the subdomain names discovered when querying this data source.
 func (c *Crtsh) Query(domain, sub string) []string {
+	return c.namesFromResults(c.QueryContext(context.Background(), domain, sub))
+}
+
+// QueryContext returns the results discovered when querying this data source.
+func (c *Crtsh) QueryContext(ctx context.Context, domain, sub string) []*Result {
 	var unique []string
 
 	if domain != sub {
-		return unique
+		return nil
 	}
 	// Pull the page that lists all certs for this domain
 	url := c.getURL(domain)
-	page, err := utils.RequestWebPage(url, nil, nil, "", "")
+	page, err := utils.RequestWebPageContext(ctx, url, nil, nil, "", "")
 	if err != nil {
-		c.Service.Config().Log.Printf("%s: %v", url, err)
-		return unique
+		return []*Result{{Err: fmt.Errorf("%s: %v", url, err)}}
 	}
 	c.Service.SetActive()
 
@@ -57,11 +63,11 @@ func (c *Crtsh) Query(domain, sub string) []string {
 		if err := lines.Decode(&line); err == io.EOF {
 			break
 		} else if err != nil {
-			c.Service.Config().Log.Printf("%s: %v", url, err)
+			return append(NamesToResults(unique), &Result{Err: fmt.Errorf("%s: %v", url, err)})
 		}
 		unique = utils.UniqueAppend(unique, line.Name)
 	}
-	return unique
+	return NamesToResults(unique)
 }
 
 func (c *Crtsh) getURL(domain string) string {
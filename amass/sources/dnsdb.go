@@ -4,6 +4,7 @@
 package sources
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strings"
@@ -31,12 +32,18 @@ func NewDNSDB(srv core.AmassService) DataSource {
 
 // Query returns the subdomain names discovered when querying this data source.
 func (d *DNSDB) Query(domain, sub string) []string {
+	return d.namesFromResults(d.QueryContext(context.Background(), domain, sub))
+}
+
+// QueryContext returns the results discovered when querying this data source.
+func (d *DNSDB) QueryContext(ctx context.Context, domain, sub string) []*Result {
 	d.Lock()
 	defer d.Unlock()
 
 	var unique []string
+	var results []*Result
 	if domain != sub {
-		return unique
+		return results
 	}
 
 	dparts := strings.Split(domain, ".")
@@ -48,15 +55,14 @@ func (d *DNSDB) Query(domain, sub string) []string {
 	}
 
 	if n, ok := d.filter[name]; ok {
-		return n
+		return NamesToResults(n)
 	}
 	d.filter[name] = unique
 
 	url := d.getURL(domain, sub)
-	page, err := utils.RequestWebPage(url, nil, nil, "", "")
+	page, err := utils.RequestWebPageContext(ctx, url, nil, nil, "", "")
 	if err != nil {
-		d.Service.Config().Log.Printf("%s: %v", url, err)
-		return unique
+		return []*Result{{Err: fmt.Errorf("%s: %v", url, err)}}
 	}
 	d.Service.SetActive()
 
@@ -74,12 +80,15 @@ loop:
 		d.Service.SetActive()
 
 		select {
+		case <-ctx.Done():
+			results = append(results, &Result{Err: ctx.Err()})
+			break loop
 		case <-d.Service.Quit():
 			break loop
 		case <-t.C:
-			another, err := utils.RequestWebPage(url+rel, nil, nil, "", "")
+			another, err := utils.RequestWebPageContext(ctx, url+rel, nil, nil, "", "")
 			if err != nil {
-				d.Service.Config().Log.Printf("%s: %v", url+rel, err)
+				results = append(results, &Result{Err: fmt.Errorf("%s: %v", url+rel, err)})
 				continue
 			}
 
@@ -91,7 +100,7 @@ loop:
 		}
 	}
 	d.filter[name] = unique
-	return unique
+	return append(results, NamesToResults(unique)...)
 }
 
 func (d *DNSDB) getURL(domain, sub string) string {
@@ -4,8 +4,9 @@
 package sources
 
 import (
+	"context"
+	"fmt"
 	"io/ioutil"
-	"net"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -16,6 +17,8 @@ import (
 	"github.com/OWASP/Amass/amass/utils"
 )
 
+var dnsdumpsterRowRE = regexp.MustCompile("(?s)<tr[^>]*>.*?</tr>")
+
 // DNSDumpster is data source object type that implements the DataSource interface.
 type DNSDumpster struct {
 	BaseDataSource
@@ -31,40 +34,64 @@ func NewDNSDumpster(srv core.AmassService) DataSource {
 
 // Query returns the subdomain names discovered when querying this data source.
 func (d *DNSDumpster) Query(domain, sub string) []string {
-	var unique []string
+	return d.namesFromResults(d.QueryContext(context.Background(), domain, sub))
+}
+
+// QueryContext returns the results discovered when querying this data source.
+func (d *DNSDumpster) QueryContext(ctx context.Context, domain, sub string) []*Result {
+	var results []*Result
 
 	if domain != sub {
-		return unique
+		return results
 	}
 
 	u := "https://dnsdumpster.com/"
-	page, err := utils.RequestWebPage(u, nil, nil, "", "")
+	page, err := utils.RequestWebPageContext(ctx, u, nil, nil, "", "")
 	if err != nil {
-		d.Service.Config().Log.Printf("%s: %v", u, err)
-		return unique
+		return []*Result{{Err: fmt.Errorf("%s: %v", u, err)}}
 	}
 
 	token := d.getCSRFToken(page)
 	if token == "" {
-		d.Service.Config().Log.Printf("%s: Failed to obtain the CSRF token", u)
-		return unique
+		return []*Result{{Err: fmt.Errorf("%s: Failed to obtain the CSRF token", u)}}
 	}
 	d.Service.SetActive()
 
-	page, err = d.postForm(token, domain)
+	page, err = d.postForm(ctx, token, domain)
 	if err != nil {
-		d.Service.Config().Log.Printf("%s: %v", u, err)
-		return unique
+		return []*Result{{Err: fmt.Errorf("%s: %v", u, err)}}
 	}
 	d.Service.SetActive()
 
+	filter := make(map[string]*Result)
 	re := utils.SubdomainRegex(domain)
+	ipre := regexp.MustCompile(utils.IPv4RE)
+	// Each table row provides a host name and the addresses it resolved to
+	for _, row := range dnsdumpsterRowRE.FindAllString(page, -1) {
+		name := strings.ToLower(re.FindString(row))
+		if name == "" {
+			continue
+		}
+
+		r, found := filter[name]
+		if !found {
+			r = &Result{Name: name, Evidence: u}
+			filter[name] = r
+			results = append(results, r)
+		}
+		r.Addresses = utils.UniqueAppend(r.Addresses, ipre.FindAllString(row, -1)...)
+	}
+	// Catch the names that were not found within the table rows
 	for _, sd := range re.FindAllString(page, -1) {
-		if u := utils.NewUniqueElements(unique, sd); len(u) > 0 {
-			unique = append(unique, u...)
+		name := strings.ToLower(sd)
+
+		if _, found := filter[name]; !found {
+			r := &Result{Name: name, Evidence: u}
+			filter[name] = r
+			results = append(results, r)
 		}
 	}
-	return unique
+	return results
 }
 
 func (d *DNSDumpster) getCSRFToken(page string) string {
@@ -76,14 +103,8 @@ func (d *DNSDumpster) getCSRFToken(page string) string {
 	return ""
 }
 
-func (d *DNSDumpster) postForm(token, domain string) (string, error) {
-	dial := net.Dialer{}
-	client := &http.Client{
-		Transport: &http.Transport{
-			DialContext:         dial.DialContext,
-			TLSHandshakeTimeout: 10 * time.Second,
-		},
-	}
+func (d *DNSDumpster) postForm(ctx context.Context, token, domain string) (string, error) {
+	client := utils.NewHTTPClient(30 * time.Second)
 	params := url.Values{
 		"csrfmiddlewaretoken": {token},
 		"targetip":            {domain},
@@ -91,9 +112,9 @@ func (d *DNSDumpster) postForm(token, domain string) (string, error) {
 
 	req, err := http.NewRequest("POST", "https://dnsdumpster.com/", strings.NewReader(params.Encode()))
 	if err != nil {
-		d.Service.Config().Log.Printf("Failed to setup the POST request: %v", err)
-		return "", err
+		return "", fmt.Errorf("Failed to setup the POST request: %v", err)
 	}
+	req = req.WithContext(ctx)
 	// The CSRF token needs to be sent as a cookie
 	cookie := &http.Cookie{
 		Name:   "csrftoken",
@@ -102,17 +123,15 @@ func (d *DNSDumpster) postForm(token, domain string) (string, error) {
 	}
 	req.AddCookie(cookie)
 
-	req.Header.Set("User-Agent", utils.UserAgent)
-	req.Header.Set("Accept", utils.Accept)
-	req.Header.Set("Accept-Language", utils.AcceptLang)
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("Referer", "https://dnsdumpster.com")
-	req.Header.Set("X-CSRF-Token", token)
+	utils.SetHTTPRequestHeaders(req, map[string]string{
+		"Content-Type": "application/x-www-form-urlencoded",
+		"Referer":      "https://dnsdumpster.com",
+		"X-CSRF-Token": token,
+	})
 
 	resp, err := client.Do(req)
 	if err != nil {
-		d.Service.Config().Log.Printf("The POST request failed: %v", err)
-		return "", err
+		return "", fmt.Errorf("The POST request failed: %v", err)
 	}
 	// Now, grab the entire page
 	in, err := ioutil.ReadAll(resp.Body)
@@ -4,6 +4,8 @@
 package sources
 
 import (
+	"context"
+	"fmt"
 	"net/url"
 	"regexp"
 	"strings"
@@ -27,17 +29,21 @@ func NewEntrust(srv core.AmassService) DataSource {
 
 // Query returns the subdomain names discovered when querying this data source.
 func (e *Entrust) Query(domain, sub string) []string {
+	return e.namesFromResults(e.QueryContext(context.Background(), domain, sub))
+}
+
+// QueryContext returns the results discovered when querying this data source.
+func (e *Entrust) QueryContext(ctx context.Context, domain, sub string) []*Result {
 	var unique []string
 
 	if domain != sub {
-		return []string{}
+		return nil
 	}
 
 	u := e.getURL(domain)
-	page, err := utils.RequestWebPage(u, nil, nil, "", "")
+	page, err := utils.RequestWebPageContext(ctx, u, nil, nil, "", "")
 	if err != nil {
-		e.Service.Config().Log.Printf("%s: %v", u, err)
-		return unique
+		return []*Result{{Err: fmt.Errorf("%s: %v", u, err)}}
 	}
 	content := strings.Replace(page, "u003d", " ", -1)
 
@@ -57,7 +63,7 @@ func (e *Entrust) Query(domain, sub string) []string {
 			}
 		}
 	}
-	return unique
+	return NamesToResults(unique)
 }
 
 func (e *Entrust) getURL(domain string) string {
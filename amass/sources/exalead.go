@@ -4,6 +4,7 @@
 package sources
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/OWASP/Amass/amass/core"
@@ -25,17 +26,21 @@ func NewExalead(srv core.AmassService) DataSource {
 
 // Query returns the subdomain names discovered when querying this data source.
 func (e *Exalead) Query(domain, sub string) []string {
+	return e.namesFromResults(e.QueryContext(context.Background(), domain, sub))
+}
+
+// QueryContext returns the results discovered when querying this data source.
+func (e *Exalead) QueryContext(ctx context.Context, domain, sub string) []*Result {
 	var unique []string
 
 	if domain != sub {
-		return unique
+		return nil
 	}
 
 	url := e.getURL(domain)
-	page, err := utils.RequestWebPage(url, nil, nil, "", "")
+	page, err := utils.RequestWebPageContext(ctx, url, nil, nil, "", "")
 	if err != nil {
-		e.Service.Config().Log.Printf("%s: %v", url, err)
-		return unique
+		return []*Result{{Err: fmt.Errorf("%s: %v", url, err)}}
 	}
 	e.Service.SetActive()
 
@@ -45,7 +50,7 @@ func (e *Exalead) Query(domain, sub string) []string {
 			unique = append(unique, u...)
 		}
 	}
-	return unique
+	return NamesToResults(unique)
 }
 
 func (e *Exalead) getURL(domain string) string {
@@ -4,6 +4,7 @@
 package sources
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/OWASP/Amass/amass/core"
@@ -25,17 +26,21 @@ func NewFindSubdomains(srv core.AmassService) DataSource {
 
 // Query returns the subdomain names discovered when querying this data source.
 func (f *FindSubdomains) Query(domain, sub string) []string {
+	return f.namesFromResults(f.QueryContext(context.Background(), domain, sub))
+}
+
+// QueryContext returns the results discovered when querying this data source.
+func (f *FindSubdomains) QueryContext(ctx context.Context, domain, sub string) []*Result {
 	var unique []string
 
 	if domain != sub {
-		return unique
+		return nil
 	}
 
 	url := f.getURL(domain)
-	page, err := utils.RequestWebPage(url, nil, nil, "", "")
+	page, err := utils.RequestWebPageContext(ctx, url, nil, nil, "", "")
 	if err != nil {
-		f.Service.Config().Log.Printf("%s: %v", url, err)
-		return unique
+		return []*Result{{Err: fmt.Errorf("%s: %v", url, err)}}
 	}
 	f.Service.SetActive()
 
@@ -45,7 +50,7 @@ func (f *FindSubdomains) Query(domain, sub string) []string {
 			unique = append(unique, u...)
 		}
 	}
-	return unique
+	return NamesToResults(unique)
 }
 
 func (f *FindSubdomains) getURL(domain string) string {
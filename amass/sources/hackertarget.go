@@ -4,7 +4,11 @@
 package sources
 
 import (
+	"bufio"
+	"context"
 	"fmt"
+	"net"
+	"strings"
 
 	"github.com/OWASP/Amass/amass/core"
 	"github.com/OWASP/Amass/amass/utils"
@@ -25,27 +29,47 @@ func NewHackerTarget(srv core.AmassService) DataSource {
 
 // Query returns the subdomain names discovered when querying this data source.
 func (h *HackerTarget) Query(domain, sub string) []string {
-	var unique []string
+	return h.namesFromResults(h.QueryContext(context.Background(), domain, sub))
+}
+
+// QueryContext returns the results discovered when querying this data source.
+func (h *HackerTarget) QueryContext(ctx context.Context, domain, sub string) []*Result {
+	var results []*Result
 
 	if domain != sub {
-		return unique
+		return results
 	}
 
 	url := h.getURL(domain)
-	page, err := utils.RequestWebPage(url, nil, nil, "", "")
+	page, err := utils.RequestWebPageContext(ctx, url, nil, nil, "", "")
 	if err != nil {
-		h.Service.Config().Log.Printf("%s: %v", url, err)
-		return unique
+		return []*Result{{Err: fmt.Errorf("%s: %v", url, err)}}
 	}
 	h.Service.SetActive()
 
+	filter := make(map[string]*Result)
 	re := utils.SubdomainRegex(domain)
-	for _, sd := range re.FindAllString(page, -1) {
-		if u := utils.NewUniqueElements(unique, sd); len(u) > 0 {
-			unique = append(unique, u...)
+	// Each line of the response contains a name and address separated by a comma
+	scanner := bufio.NewScanner(strings.NewReader(page))
+	for scanner.Scan() {
+		parts := strings.Split(strings.TrimSpace(scanner.Text()), ",")
+
+		name := strings.ToLower(re.FindString(parts[0]))
+		if name == "" {
+			continue
+		}
+
+		r, found := filter[name]
+		if !found {
+			r = &Result{Name: name, Evidence: url}
+			filter[name] = r
+			results = append(results, r)
+		}
+		if len(parts) > 1 && net.ParseIP(parts[1]) != nil {
+			r.Addresses = utils.UniqueAppend(r.Addresses, parts[1])
 		}
 	}
-	return unique
+	return results
 }
 
 func (h *HackerTarget) getURL(domain string) string {
@@ -4,6 +4,7 @@
 package sources
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"time"
@@ -28,44 +29,45 @@ func NewIPv4Info(srv core.AmassService) DataSource {
 
 // Query returns the subdomain names discovered when querying this data source.
 func (i *IPv4Info) Query(domain, sub string) []string {
+	return i.namesFromResults(i.QueryContext(context.Background(), domain, sub))
+}
+
+// QueryContext returns the results discovered when querying this data source.
+func (i *IPv4Info) QueryContext(ctx context.Context, domain, sub string) []*Result {
 	var unique []string
 
 	if domain != sub {
-		return []string{}
+		return nil
 	}
 
 	url := i.getURL(domain)
-	page, err := utils.RequestWebPage(url, nil, nil, "", "")
+	page, err := utils.RequestWebPageContext(ctx, url, nil, nil, "", "")
 	if err != nil {
-		i.Service.Config().Log.Printf("%s: %v", url, err)
-		return unique
+		return []*Result{{Err: fmt.Errorf("%s: %v", url, err)}}
 	}
 	time.Sleep(time.Second)
 	i.Service.SetActive()
 
 	url = i.ipSubmatch(page, domain)
-	page, err = utils.RequestWebPage(url, nil, nil, "", "")
+	page, err = utils.RequestWebPageContext(ctx, url, nil, nil, "", "")
 	if err != nil {
-		i.Service.Config().Log.Printf("%s: %v", url, err)
-		return unique
+		return []*Result{{Err: fmt.Errorf("%s: %v", url, err)}}
 	}
 	time.Sleep(time.Second)
 	i.Service.SetActive()
 
 	url = i.domainSubmatch(page, domain)
-	page, err = utils.RequestWebPage(url, nil, nil, "", "")
+	page, err = utils.RequestWebPageContext(ctx, url, nil, nil, "", "")
 	if err != nil {
-		i.Service.Config().Log.Printf("%s: %v", url, err)
-		return unique
+		return []*Result{{Err: fmt.Errorf("%s: %v", url, err)}}
 	}
 	time.Sleep(time.Second)
 	i.Service.SetActive()
 
 	url = i.subdomainSubmatch(page, domain)
-	page, err = utils.RequestWebPage(url, nil, nil, "", "")
+	page, err = utils.RequestWebPageContext(ctx, url, nil, nil, "", "")
 	if err != nil {
-		i.Service.Config().Log.Printf("%s: %v", url, err)
-		return unique
+		return []*Result{{Err: fmt.Errorf("%s: %v", url, err)}}
 	}
 	i.Service.SetActive()
 
@@ -75,7 +77,7 @@ func (i *IPv4Info) Query(domain, sub string) []string {
 			unique = append(unique, u...)
 		}
 	}
-	return unique
+	return NamesToResults(unique)
 }
 
 func (i *IPv4Info) getURL(domain string) string {
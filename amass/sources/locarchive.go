@@ -3,7 +3,11 @@
 
 package sources
 
-import "github.com/OWASP/Amass/amass/core"
+import (
+	"context"
+
+	"github.com/OWASP/Amass/amass/core"
+)
 
 // LoCArchive is data source object type that implements the DataSource interface.
 type LoCArchive struct {
@@ -21,15 +25,21 @@ func NewLoCArchive(srv core.AmassService) DataSource {
 
 // Query returns the subdomain names discovered when querying this data source.
 func (la *LoCArchive) Query(domain, sub string) []string {
+	return la.namesFromResults(la.QueryContext(context.Background(), domain, sub))
+}
+
+// QueryContext returns the results discovered when querying this data source.
+func (la *LoCArchive) QueryContext(ctx context.Context, domain, sub string) []*Result {
 	if sub == "" {
-		return []string{}
+		return nil
 	}
 
-	names, err := la.crawl(la.baseURL, domain, sub)
+	names, err := la.crawl(ctx, la.baseURL, domain, sub)
+	results := NamesToResults(names)
 	if err != nil {
-		la.Service.Config().Log.Printf("%v", err)
+		results = append(results, &Result{Err: err})
 	}
-	return names
+	return results
 }
 
 // Subdomains returns true when the data source can query for subdomain names.
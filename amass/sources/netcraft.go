@@ -4,6 +4,7 @@
 package sources
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/OWASP/Amass/amass/core"
@@ -25,17 +26,21 @@ func NewNetcraft(srv core.AmassService) DataSource {
 
 // Query returns the subdomain names discovered when querying this data source.
 func (n *Netcraft) Query(domain, sub string) []string {
+	return n.namesFromResults(n.QueryContext(context.Background(), domain, sub))
+}
+
+// QueryContext returns the results discovered when querying this data source.
+func (n *Netcraft) QueryContext(ctx context.Context, domain, sub string) []*Result {
 	var unique []string
 
 	if domain != sub {
-		return unique
+		return nil
 	}
 
 	url := n.getURL(domain)
-	page, err := utils.RequestWebPage(url, nil, nil, "", "")
+	page, err := utils.RequestWebPageContext(ctx, url, nil, nil, "", "")
 	if err != nil {
-		n.Service.Config().Log.Printf("%s, %v", url, err)
-		return unique
+		return []*Result{{Err: fmt.Errorf("%s: %v", url, err)}}
 	}
 	n.Service.SetActive()
 
@@ -45,7 +50,7 @@ func (n *Netcraft) Query(domain, sub string) []string {
 			unique = append(unique, u...)
 		}
 	}
-	return unique
+	return NamesToResults(unique)
 }
 
 func (n *Netcraft) getURL(domain string) string {
@@ -3,7 +3,11 @@
 
 package sources
 
-import "github.com/OWASP/Amass/amass/core"
+import (
+	"context"
+
+	"github.com/OWASP/Amass/amass/core"
+)
 
 // OpenUKArchive is data source object type that implements the DataSource interface.
 type OpenUKArchive struct {
@@ -21,15 +25,21 @@ func NewOpenUKArchive(srv core.AmassService) DataSource {
 
 // Query returns the subdomain names discovered when querying this data source.
 func (o *OpenUKArchive) Query(domain, sub string) []string {
+	return o.namesFromResults(o.QueryContext(context.Background(), domain, sub))
+}
+
+// QueryContext returns the results discovered when querying this data source.
+func (o *OpenUKArchive) QueryContext(ctx context.Context, domain, sub string) []*Result {
 	if sub == "" {
-		return []string{}
+		return nil
 	}
 
-	names, err := o.crawl(o.baseURL, domain, sub)
+	names, err := o.crawl(ctx, o.baseURL, domain, sub)
+	results := NamesToResults(names)
 	if err != nil {
-		o.Service.Config().Log.Printf("%v", err)
+		results = append(results, &Result{Err: err})
 	}
-	return names
+	return results
 }
 
 // Subdomains returns true when the data source can query for subdomain names.
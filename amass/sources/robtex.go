@@ -5,7 +5,9 @@ package sources
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
+	"fmt"
 	"strings"
 	"time"
 
@@ -27,25 +29,31 @@ func NewRobtex(srv core.AmassService) DataSource {
 }
 
 type robtexJSON struct {
-	Name string `json:"rrname"`
-	Data string `json:"rrdata"`
-	Type string `json:"rrtype"`
+	Name      string `json:"rrname"`
+	Data      string `json:"rrdata"`
+	Type      string `json:"rrtype"`
+	FirstSeen int64  `json:"time_first"`
+	LastSeen  int64  `json:"time_last"`
 }
 
 // Query returns the subdomain names discovered when querying this data source.
 func (r *Robtex) Query(domain, sub string) []string {
+	return r.namesFromResults(r.QueryContext(context.Background(), domain, sub))
+}
+
+// QueryContext returns the results discovered when querying this data source.
+func (r *Robtex) QueryContext(ctx context.Context, domain, sub string) []*Result {
 	var ips []string
-	var unique []string
+	var results []*Result
 
 	if domain != sub {
-		return unique
+		return results
 	}
 
 	url := "https://freeapi.robtex.com/pdns/forward/" + domain
-	page, err := utils.RequestWebPage(url, nil, nil, "", "")
+	page, err := utils.RequestWebPageContext(ctx, url, nil, nil, "", "")
 	if err != nil {
-		r.Service.Config().Log.Printf("%s: %v", url, err)
-		return unique
+		return []*Result{{Err: fmt.Errorf("%s: %v", url, err)}}
 	}
 	r.Service.SetActive()
 
@@ -55,7 +63,8 @@ func (r *Robtex) Query(domain, sub string) []string {
 		}
 	}
 
-	var list string
+	filter := make(map[string]*Result)
+	re := utils.SubdomainRegex(domain)
 	t := time.NewTicker(500 * time.Millisecond)
 	defer t.Stop()
 loop:
@@ -63,29 +72,54 @@ loop:
 		r.Service.SetActive()
 
 		select {
+		case <-ctx.Done():
+			results = append(results, &Result{Err: ctx.Err()})
+			break loop
 		case <-r.Service.Quit():
 			break loop
 		case <-t.C:
 			url = "https://freeapi.robtex.com/pdns/reverse/" + ip
-			pdns, err := utils.RequestWebPage(url, nil, nil, "", "")
+			pdns, err := utils.RequestWebPageContext(ctx, url, nil, nil, "", "")
 			if err != nil {
-				r.Service.Config().Log.Printf("%s: %v", url, err)
+				results = append(results, &Result{Err: fmt.Errorf("%s: %v", url, err)})
 				continue
 			}
 
 			for _, line := range r.parseJSON(pdns) {
-				list += line.Name + " "
+				name := strings.ToLower(re.FindString(line.Name))
+				if name == "" || name != strings.ToLower(strings.TrimSuffix(line.Name, ".")) {
+					continue
+				}
+
+				res, found := filter[name]
+				if !found {
+					res = &Result{Name: name, Evidence: url}
+					filter[name] = res
+					results = append(results, res)
+				}
+				res.Addresses = utils.UniqueAppend(res.Addresses, ip)
+				r.updateTimes(res, line)
 			}
 		}
 	}
+	return results
+}
+
+func (r *Robtex) updateTimes(res *Result, line robtexJSON) {
+	if line.FirstSeen > 0 {
+		first := time.Unix(line.FirstSeen, 0)
+
+		if res.FirstSeen.IsZero() || first.Before(res.FirstSeen) {
+			res.FirstSeen = first
+		}
+	}
+	if line.LastSeen > 0 {
+		last := time.Unix(line.LastSeen, 0)
 
-	re := utils.SubdomainRegex(domain)
-	for _, sd := range re.FindAllString(list, -1) {
-		if u := utils.NewUniqueElements(unique, sd); len(u) > 0 {
-			unique = append(unique, u...)
+		if last.After(res.LastSeen) {
+			res.LastSeen = last
 		}
 	}
-	return unique
 }
 
 func (r *Robtex) parseJSON(page string) []robtexJSON {
@@ -4,10 +4,11 @@
 package sources
 
 import (
+	"context"
 	"fmt"
-	"net"
 	"net/http"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -24,6 +25,21 @@ const (
 	APIkeyOptional
 )
 
+var (
+	// SourceTypes contains the data source types that can be used to select groups of data sources.
+	SourceTypes = []string{
+		core.API,
+		core.ARCHIVE,
+		core.CERT,
+		core.SCRAPE,
+	}
+
+	// Data sources that are only used when explicitly included by name
+	disabledByDefault = []string{
+		"DNSDB",
+	}
+)
+
 // DataSource is the interface that all data sources types in Amass implement.
 type DataSource interface {
 	// Returns subdomain names from the data source
@@ -42,6 +58,100 @@ type DataSource interface {
 	APIKeyRequired() int
 }
 
+// Result contains a subdomain name discovered by a data source and the supporting
+// data the source provided along with it.
+type Result struct {
+	// The discovered subdomain name
+	Name string
+
+	// IP addresses the data source associated with the name
+	Addresses []string
+
+	// When the data source first and last observed the name
+	FirstSeen time.Time
+	LastSeen  time.Time
+
+	// The URL that served the data supporting the finding
+	Evidence string
+
+	// Reports a failure that occurred while querying the data source
+	Err error
+}
+
+// ContextDataSource is the interface implemented by data sources that can be cancelled
+// using a context and that return structured results instead of bare names.
+type ContextDataSource interface {
+	// Returns the results discovered for the subdomain name using the data source
+	QueryContext(ctx context.Context, domain, sub string) []*Result
+
+	// Returns the data source name that maps to an API key in the config
+	String() string
+
+	// Returns true if the data source supports subdomain name searches
+	Subdomains() bool
+
+	// Returns one of the data source types defined in the core package
+	Type() string
+
+	// Indicates if an API key is required by the data source
+	APIKeyRequired() int
+}
+
+// AsContextDataSource returns the provided DataSource as a ContextDataSource.
+// Data sources that do not implement the interface are wrapped by an adapter.
+func AsContextDataSource(source DataSource) ContextDataSource {
+	if cds, ok := source.(ContextDataSource); ok {
+		return cds
+	}
+	return &contextAdapter{DataSource: source}
+}
+
+type contextAdapter struct {
+	DataSource
+}
+
+// QueryContext executes the Query method of the adapted data source. The Query method
+// cannot be interrupted, so the context is only checked before the query is started.
+func (ca *contextAdapter) QueryContext(ctx context.Context, domain, sub string) []*Result {
+	if err := ctx.Err(); err != nil {
+		return []*Result{{Err: err}}
+	}
+	return NamesToResults(ca.Query(domain, sub))
+}
+
+// NamesToResults converts a slice of subdomain names into Result objects.
+func NamesToResults(names []string) []*Result {
+	var results []*Result
+
+	for _, name := range names {
+		results = append(results, &Result{Name: name})
+	}
+	return results
+}
+
+// ResultsToNames returns the unique subdomain names contained within the results.
+func ResultsToNames(results []*Result) []string {
+	var names []string
+
+	for _, r := range results {
+		if r.Err == nil && r.Name != "" {
+			names = utils.UniqueAppend(names, r.Name)
+		}
+	}
+	return names
+}
+
+// namesFromResults returns the unique subdomain names contained within the
+// results and logs the errors reported by the data source.
+func (bds *BaseDataSource) namesFromResults(results []*Result) []string {
+	for _, r := range results {
+		if r.Err != nil {
+			bds.Service.Config().Log.Printf("%s: %v", bds.Name, r.Err)
+		}
+	}
+	return ResultsToNames(results)
+}
+
 // BaseDataSource provides common functionalities and default behaviors to all
 // Amass data sources. Most of the base methods are not implemented by each data
 // source.
@@ -90,7 +200,7 @@ func (bds *BaseDataSource) String() string {
 // Web archive crawler implementation
 //-------------------------------------------------------------------------------------------------
 
-func (bds *BaseDataSource) crawl(base, domain, sub string) ([]string, error) {
+func (bds *BaseDataSource) crawl(ctx context.Context, base, domain, sub string) ([]string, error) {
 	var results []string
 	var filterMutex sync.Mutex
 	filter := make(map[string]struct{})
@@ -148,6 +258,11 @@ loop:
 			}()
 		case <-q.Done():
 			break loop
+		case <-ctx.Done():
+			go func() {
+				q.Cancel()
+			}()
+			return results, ctx.Err()
 		case <-bds.Service.Quit():
 			break loop
 		}
@@ -181,20 +296,21 @@ func (bds *BaseDataSource) linksAndNames(domain string, ctx *fetchbot.Context, r
 }
 
 func setFetcherConfig(f *fetchbot.Fetcher) {
-	d := net.Dialer{}
-	f.HttpClient = &http.Client{
-		Timeout: 10 * time.Second,
-		Transport: &http.Transport{
-			DialContext:           d.DialContext,
-			MaxIdleConns:          200,
-			IdleConnTimeout:       5 * time.Second,
-			TLSHandshakeTimeout:   5 * time.Second,
-			ExpectContinueTimeout: 5 * time.Second,
-		},
-	}
+	f.HttpClient = &fetcherClient{client: utils.NewHTTPClient(10 * time.Second)}
 	f.CrawlDelay = 1 * time.Second
 	f.DisablePoliteness = true
-	f.UserAgent = utils.UserAgent
+	f.UserAgent = utils.NextUserAgent()
+}
+
+// fetcherClient adds the configured header values to the requests of the crawler.
+type fetcherClient struct {
+	client *http.Client
+}
+
+// Do implements the fetchbot.Doer interface
+func (fc *fetcherClient) Do(req *http.Request) (*http.Response, error) {
+	utils.SetHTTPRequestHeaders(req, nil)
+	return fc.client.Do(req)
 }
 
 //-------------------------------------------------------------------------------------------------
@@ -212,7 +328,7 @@ func GetAllSources(srv core.AmassService) []DataSource {
 		NewCertSpotter(srv),
 		NewCommonCrawl(srv),
 		NewCrtsh(srv),
-		//NewDNSDB(srv),
+		NewDNSDB(srv),
 		NewDNSDumpster(srv),
 		NewDNSTable(srv),
 		NewDogpile(srv),
@@ -236,3 +352,72 @@ func GetAllSources(srv core.AmassService) []DataSource {
 		NewYahoo(srv),
 	}
 }
+
+// SelectSources returns the data sources from the provided slice that remain after
+// applying the include and exclude lists. Each entry in the lists can either be a
+// data source name or one of the data source types defined in the core package.
+// When the include list is empty, all data sources not disabled by default are selected.
+// Data sources disabled by default are only selected when included by name.
+func SelectSources(all []DataSource, include, exclude []string) []DataSource {
+	var selected []DataSource
+
+	for _, source := range all {
+		if len(include) > 0 {
+			if !sourceInList(source, include, !sourceDisabled(source)) {
+				continue
+			}
+		} else if sourceDisabled(source) {
+			continue
+		}
+
+		if sourceInList(source, exclude, true) {
+			continue
+		}
+		selected = append(selected, source)
+	}
+	return selected
+}
+
+// ValidSourceName returns true if the provided string identifies a data source or a data source type.
+func ValidSourceName(all []DataSource, name string) bool {
+	for _, t := range SourceTypes {
+		if strings.EqualFold(name, t) {
+			return true
+		}
+	}
+
+	for _, source := range all {
+		if strings.EqualFold(name, source.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+// DisabledByDefault returns true if the data source is only used when explicitly included by name.
+func DisabledByDefault(source DataSource) bool {
+	return sourceDisabled(source)
+}
+
+func sourceDisabled(source DataSource) bool {
+	for _, name := range disabledByDefault {
+		if strings.EqualFold(name, source.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+func sourceInList(source DataSource, list []string, matchType bool) bool {
+	for _, entry := range list {
+		entry = strings.TrimSpace(entry)
+
+		if strings.EqualFold(entry, source.String()) {
+			return true
+		}
+		if matchType && strings.EqualFold(entry, source.Type()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,183 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package sources
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/amass/core"
+	"github.com/OWASP/Amass/amass/utils"
+	"github.com/PuerkitoBio/fetchbot"
+)
+
+type testSource struct {
+	BaseDataSource
+	queries int
+}
+
+func (ts *testSource) Query(domain, sub string) []string {
+	ts.queries++
+	return []string{"www." + domain}
+}
+
+func newTestService(buf *bytes.Buffer) core.AmassService {
+	config := &core.AmassConfig{Log: log.New(buf, "", 0)}
+
+	return core.NewBaseAmassService("Test Service", config, nil)
+}
+
+func TestContextAdapter(t *testing.T) {
+	source := &testSource{}
+	cds := AsContextDataSource(source)
+
+	results := cds.QueryContext(context.Background(), "example.com", "example.com")
+	if names := ResultsToNames(results); len(names) != 1 || names[0] != "www.example.com" {
+		t.Errorf("The adapter returned the names %v", names)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results = cds.QueryContext(ctx, "example.com", "example.com")
+	if len(results) != 1 || results[0].Err != context.Canceled {
+		t.Errorf("The adapter did not report the cancelled context: %v", results)
+	}
+	if source.queries != 1 {
+		t.Errorf("The adapted data source was queried %d times instead of once", source.queries)
+	}
+}
+
+func TestQueryContextCancel(t *testing.T) {
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Hold the request until the client gives up on it
+		<-r.Context().Done()
+		close(done)
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	source := NewIPv4Info(newTestService(&buf)).(*IPv4Info)
+	source.baseURL = ts.URL
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	results := source.QueryContext(ctx, "example.com", "example.com")
+	if len(results) != 1 || results[0].Err == nil {
+		t.Fatalf("QueryContext did not report the cancelled request: %v", results)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Error("The web request continued after the context was cancelled")
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	source := NewIPv4Info(newTestService(&buf)).(*IPv4Info)
+	source.baseURL = ts.URL
+
+	results := source.QueryContext(context.Background(), "example.com", "example.com")
+	if len(results) != 1 || results[0].Err == nil {
+		t.Fatalf("QueryContext did not return the failed request: %v", results)
+	}
+	if !strings.Contains(results[0].Err.Error(), "503") {
+		t.Errorf("QueryContext returned the error %v", results[0].Err)
+	}
+
+	if names := source.Query("example.com", "example.com"); len(names) != 0 {
+		t.Errorf("Query returned the names %v", names)
+	}
+	if !strings.Contains(buf.String(), "IPv4info: "+ts.URL) {
+		t.Errorf("Query did not log the failed request: %q", buf.String())
+	}
+}
+
+func TestSelectSources(t *testing.T) {
+	all := []DataSource{
+		&testSource{BaseDataSource: BaseDataSource{SourceType: core.API, Name: "Alpha"}},
+		&testSource{BaseDataSource: BaseDataSource{SourceType: core.CERT, Name: "Bravo"}},
+		&testSource{BaseDataSource: BaseDataSource{SourceType: core.SCRAPE, Name: "Charlie"}},
+		// Disabled by default
+		&testSource{BaseDataSource: BaseDataSource{SourceType: core.API, Name: "DNSDB"}},
+	}
+
+	tests := []struct {
+		name     string
+		include  []string
+		exclude  []string
+		expected string
+	}{
+		{"default", nil, nil, "Alpha Bravo Charlie"},
+		{"exclude name", nil, []string{"bravo"}, "Alpha Charlie"},
+		{"exclude type", nil, []string{"api"}, "Bravo Charlie"},
+		{"include names", []string{"Alpha", " charlie "}, nil, "Alpha Charlie"},
+		{"include type", []string{core.API}, nil, "Alpha"},
+		{"include disabled", []string{"dnsdb"}, nil, "DNSDB"},
+		{"include type and disabled", []string{core.API, "DNSDB"}, nil, "Alpha DNSDB"},
+		{"include and exclude", []string{core.API, core.CERT}, []string{"Alpha"}, "Bravo"},
+		{"exclude disabled", []string{"DNSDB"}, []string{"DNSDB"}, ""},
+		{"include unknown", []string{"Unknown"}, nil, ""},
+		{"exclude unknown", nil, []string{"Unknown"}, "Alpha Bravo Charlie"},
+	}
+
+	for _, test := range tests {
+		var names []string
+		for _, source := range SelectSources(all, test.include, test.exclude) {
+			names = append(names, source.String())
+		}
+		if got := strings.Join(names, " "); got != test.expected {
+			t.Errorf("%s: SelectSources returned %q instead of %q", test.name, got, test.expected)
+		}
+	}
+
+	for name, valid := range map[string]bool{"alpha": true, "cert": true, "Unknown": false} {
+		if ValidSourceName(all, name) != valid {
+			t.Errorf("ValidSourceName(%s) did not return %t", name, valid)
+		}
+	}
+	if !DisabledByDefault(all[3]) || DisabledByDefault(all[0]) {
+		t.Errorf("DisabledByDefault did not identify the DNSDB data source")
+	}
+}
+
+func TestFetcherHeaders(t *testing.T) {
+	received := make(chan string, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Get("X-Amass-Test")
+	}))
+	defer ts.Close()
+
+	utils.SetHTTPHeaders(map[string]string{"X-Amass-Test": "crawled"})
+	defer utils.SetHTTPHeaders(nil)
+
+	f := fetchbot.New(fetchbot.HandlerFunc(func(ctx *fetchbot.Context, res *http.Response, err error) {}))
+	setFetcherConfig(f)
+	q := f.Start()
+	q.SendStringGet(ts.URL)
+	q.Close()
+
+	select {
+	case got := <-received:
+		if got != "crawled" {
+			t.Errorf("The crawler request had the header value %q", got)
+		}
+	default:
+		t.Errorf("The crawler did not send the request")
+	}
+}
@@ -4,6 +4,7 @@
 package sources
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/OWASP/Amass/amass/core"
@@ -25,18 +26,22 @@ func NewThreatCrowd(srv core.AmassService) DataSource {
 
 // Query returns the subdomain names discovered when querying this data source.
 func (t *ThreatCrowd) Query(domain, sub string) []string {
+	return t.namesFromResults(t.QueryContext(context.Background(), domain, sub))
+}
+
+// QueryContext returns the results discovered when querying this data source.
+func (t *ThreatCrowd) QueryContext(ctx context.Context, domain, sub string) []*Result {
 	var unique []string
 
 	if domain != sub {
-		return unique
+		return nil
 	}
 
 	re := utils.SubdomainRegex(domain)
 	url := t.getURL(domain)
-	page, err := utils.RequestWebPage(url, nil, nil, "", "")
+	page, err := utils.RequestWebPageContext(ctx, url, nil, nil, "", "")
 	if err != nil {
-		t.Service.Config().Log.Printf("%s: %v", url, err)
-		return unique
+		return []*Result{{Err: fmt.Errorf("%s: %v", url, err)}}
 	}
 	t.Service.SetActive()
 
@@ -45,7 +50,7 @@ func (t *ThreatCrowd) Query(domain, sub string) []string {
 			unique = append(unique, u...)
 		}
 	}
-	return unique
+	return NamesToResults(unique)
 }
 
 func (t *ThreatCrowd) getURL(domain string) string {
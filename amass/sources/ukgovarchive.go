@@ -3,7 +3,11 @@
 
 package sources
 
-import "github.com/OWASP/Amass/amass/core"
+import (
+	"context"
+
+	"github.com/OWASP/Amass/amass/core"
+)
 
 // UKGovArchive is data source object type that implements the DataSource interface.
 type UKGovArchive struct {
@@ -21,15 +25,21 @@ func NewUKGovArchive(srv core.AmassService) DataSource {
 
 // Query returns the subdomain names discovered when querying this data source.
 func (u *UKGovArchive) Query(domain, sub string) []string {
+	return u.namesFromResults(u.QueryContext(context.Background(), domain, sub))
+}
+
+// QueryContext returns the results discovered when querying this data source.
+func (u *UKGovArchive) QueryContext(ctx context.Context, domain, sub string) []*Result {
 	if sub == "" {
-		return []string{}
+		return nil
 	}
 
-	names, err := u.crawl(u.baseURL, domain, sub)
+	names, err := u.crawl(ctx, u.baseURL, domain, sub)
+	results := NamesToResults(names)
 	if err != nil {
-		u.Service.Config().Log.Printf("%v", err)
+		results = append(results, &Result{Err: err})
 	}
-	return names
+	return results
 }
 
 // Subdomains returns true when the data source can query for subdomain names.
@@ -4,6 +4,7 @@
 package sources
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/OWASP/Amass/amass/core"
@@ -25,18 +26,22 @@ func NewVirusTotal(srv core.AmassService) DataSource {
 
 // Query returns the subdomain names discovered when querying this data source.
 func (v *VirusTotal) Query(domain, sub string) []string {
+	return v.namesFromResults(v.QueryContext(context.Background(), domain, sub))
+}
+
+// QueryContext returns the results discovered when querying this data source.
+func (v *VirusTotal) QueryContext(ctx context.Context, domain, sub string) []*Result {
 	var unique []string
 
 	if domain != sub {
-		return unique
+		return nil
 	}
 
 	re := utils.SubdomainRegex(domain)
 	url := v.getURL(domain)
-	page, err := utils.RequestWebPage(url, nil, nil, "", "")
+	page, err := utils.RequestWebPageContext(ctx, url, nil, nil, "", "")
 	if err != nil {
-		v.Service.Config().Log.Printf("%s: %v", url, err)
-		return unique
+		return []*Result{{Err: fmt.Errorf("%s: %v", url, err)}}
 	}
 	v.Service.SetActive()
 
@@ -45,7 +50,7 @@ func (v *VirusTotal) Query(domain, sub string) []string {
 			unique = append(unique, u...)
 		}
 	}
-	return unique
+	return NamesToResults(unique)
 }
 
 func (v *VirusTotal) getURL(domain string) string {
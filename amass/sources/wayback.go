@@ -3,7 +3,11 @@
 
 package sources
 
-import "github.com/OWASP/Amass/amass/core"
+import (
+	"context"
+
+	"github.com/OWASP/Amass/amass/core"
+)
 
 // WaybackMachine is data source object type that implements the DataSource interface.
 type WaybackMachine struct {
@@ -21,15 +25,21 @@ func NewWaybackMachine(srv core.AmassService) DataSource {
 
 // Query returns the subdomain names discovered when querying this data source.
 func (w *WaybackMachine) Query(domain, sub string) []string {
+	return w.namesFromResults(w.QueryContext(context.Background(), domain, sub))
+}
+
+// QueryContext returns the results discovered when querying this data source.
+func (w *WaybackMachine) QueryContext(ctx context.Context, domain, sub string) []*Result {
 	if sub == "" {
-		return []string{}
+		return nil
 	}
 
-	names, err := w.crawl(w.baseURL, domain, sub)
+	names, err := w.crawl(ctx, w.baseURL, domain, sub)
+	results := NamesToResults(names)
 	if err != nil {
-		w.Service.Config().Log.Printf("%v", err)
+		results = append(results, &Result{Err: err})
 	}
-	return names
+	return results
 }
 
 // Subdomains returns true when the data source can query for subdomain names.
@@ -4,6 +4,7 @@
 package amass
 
 import (
+	"context"
 	"regexp"
 	"strings"
 	"time"
@@ -19,7 +20,7 @@ var (
 )
 
 type entry struct {
-	Source sources.DataSource
+	Source sources.ContextDataSource
 	Domain string
 	Sub    string
 }
@@ -30,9 +31,11 @@ type SourcesService struct {
 	core.BaseAmassService
 
 	bus           evbus.Bus
+	ctx           context.Context
+	cancel        context.CancelFunc
 	responses     chan *core.AmassRequest
-	directs       []sources.DataSource
-	throttles     []sources.DataSource
+	directs       []sources.ContextDataSource
+	throttles     []sources.ContextDataSource
 	throttleQueue []*entry
 	filter        *utils.StringFilter
 	outfilter     *utils.StringFilter
@@ -48,8 +51,12 @@ func NewSourcesService(config *core.AmassConfig, bus evbus.Bus) *SourcesService
 		outfilter: utils.NewStringFilter(),
 	}
 	ss.BaseAmassService = *core.NewBaseAmassService("Sources Service", config, ss)
+	ss.ctx, ss.cancel = context.WithCancel(context.Background())
+
+	all := sources.GetAllSources(ss)
+	for _, s := range sources.SelectSources(all, config.IncludeSources, config.ExcludeSources) {
+		source := sources.AsContextDataSource(s)
 
-	for _, source := range sources.GetAllSources(ss) {
 		if source.Type() == core.ARCHIVE {
 			ss.throttles = append(ss.throttles, source)
 		} else {
@@ -76,6 +83,8 @@ func (ss *SourcesService) OnStop() error {
 	ss.BaseAmassService.OnStop()
 
 	ss.bus.Unsubscribe(core.CHECKED, ss.SendRequest)
+	// Cancel the data source queries still in progress
+	ss.cancel()
 	return nil
 }
 
@@ -96,6 +105,10 @@ func (ss *SourcesService) handleRequest(req *core.AmassRequest) {
 	if ss.filter.Duplicate(req.Name) || !ss.Config().IsDomainInScope(req.Name) {
 		return
 	}
+	if blacklisted, reason := ss.Config().BlacklistReason(req.Name); blacklisted {
+		ss.Config().Log.Print(reason)
+		return
+	}
 
 	var subsrch bool
 	if req.Name != req.Domain {
@@ -147,6 +160,10 @@ func (ss *SourcesService) handleOutput(req *core.AmassRequest) {
 	if ss.outfilter.Duplicate(req.Name + req.Source) {
 		return
 	}
+	if blacklisted, reason := ss.Config().BlacklistReason(req.Name); blacklisted {
+		ss.Config().Log.Printf("%s: %s", req.Source, reason)
+		return
+	}
 	ss.Config().MaxFlow.Acquire(1)
 	ss.bus.Publish(core.NEWNAME, req)
 	ss.SendRequest(req)
@@ -163,18 +180,43 @@ func (ss *SourcesService) queryAllSources() {
 	}
 }
 
-func (ss *SourcesService) queryOneSource(source sources.DataSource, domain, sub string) {
-	for _, name := range source.Query(domain, sub) {
-		ss.responses <- &core.AmassRequest{
-			Name:   name,
+func (ss *SourcesService) queryOneSource(source sources.ContextDataSource, domain, sub string) {
+	for _, r := range source.QueryContext(ss.ctx, domain, sub) {
+		if r.Err != nil {
+			// Errors caused by the service stopping are not reported
+			if ss.ctx.Err() == nil {
+				ss.Config().Log.Printf("%s: %v", source.String(), r.Err)
+			}
+			continue
+		}
+		if r.Name == "" {
+			continue
+		}
+
+		req := &core.AmassRequest{
+			Name:   r.Name,
 			Domain: domain,
 			Tag:    source.Type(),
 			Source: source.String(),
 		}
+		if len(r.Addresses) > 0 || !r.FirstSeen.IsZero() || !r.LastSeen.IsZero() || r.Evidence != "" {
+			req.Evidence = &core.SourceEvidence{
+				Addresses: r.Addresses,
+				FirstSeen: r.FirstSeen,
+				LastSeen:  r.LastSeen,
+				URL:       r.Evidence,
+			}
+		}
+
+		select {
+		case ss.responses <- req:
+		case <-ss.ctx.Done():
+			return
+		}
 	}
 }
 
-func (ss *SourcesService) throttleAdd(source sources.DataSource, domain, sub string) {
+func (ss *SourcesService) throttleAdd(source sources.ContextDataSource, domain, sub string) {
 	ss.Lock()
 	defer ss.Unlock()
 
@@ -0,0 +1,285 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+)
+
+// starttlsFunc upgrades a plaintext connection so the TLS handshake can be performed.
+type starttlsFunc func(conn net.Conn, serverName string) error
+
+// The protocols that require STARTTLS negotiation on their well-known ports
+var starttlsPorts = map[int]starttlsFunc{
+	21:   starttlsFTP,
+	25:   starttlsSMTP,
+	110:  starttlsPOP3,
+	143:  starttlsIMAP,
+	389:  starttlsLDAP,
+	587:  starttlsSMTP,
+	2525: starttlsSMTP,
+	5222: starttlsXMPPClient,
+	5269: starttlsXMPPServer,
+	5432: starttlsPostgreSQL,
+}
+
+// The encoded LDAP extended request for StartTLS (OID 1.3.6.1.4.1.1466.20037) using message ID 1
+var ldapStartTLSRequest = []byte{
+	0x30, 0x1d, 0x02, 0x01, 0x01, 0x77, 0x18, 0x80, 0x16,
+	'1', '.', '3', '.', '6', '.', '1', '.', '4', '.', '1', '.',
+	'1', '4', '6', '6', '.', '2', '0', '0', '3', '7',
+}
+
+// The PostgreSQL SSLRequest message
+var postgresSSLRequest = []byte{0x00, 0x00, 0x00, 0x08, 0x04, 0xd2, 0x16, 0x2f}
+
+func starttlsSMTP(conn net.Conn, serverName string) error {
+	r := bufio.NewReader(conn)
+
+	if err := expectReply(r, "220"); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(conn, "EHLO localhost\r\n"); err != nil {
+		return err
+	}
+	if err := expectReply(r, "250"); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(conn, "STARTTLS\r\n"); err != nil {
+		return err
+	}
+	return expectReply(r, "220")
+}
+
+func starttlsFTP(conn net.Conn, serverName string) error {
+	r := bufio.NewReader(conn)
+
+	if err := expectReply(r, "220"); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(conn, "AUTH TLS\r\n"); err != nil {
+		return err
+	}
+	return expectReply(r, "234")
+}
+
+// expectReply reads a possibly multi-line SMTP or FTP reply and checks the reply code.
+func expectReply(r *bufio.Reader, code string) error {
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		if len(line) < 4 || !strings.HasPrefix(line, code) {
+			return fmt.Errorf("Unexpected reply: %s", strings.TrimSpace(line))
+		}
+		// The last line of the reply has a space after the code
+		if line[3] == ' ' || line[3] == '\r' || line[3] == '\n' {
+			return nil
+		}
+	}
+}
+
+func starttlsPOP3(conn net.Conn, serverName string) error {
+	r := bufio.NewReader(conn)
+
+	if err := expectLinePrefix(r, "+OK"); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(conn, "STLS\r\n"); err != nil {
+		return err
+	}
+	return expectLinePrefix(r, "+OK")
+}
+
+func starttlsIMAP(conn net.Conn, serverName string) error {
+	r := bufio.NewReader(conn)
+
+	if err := expectLinePrefix(r, "* OK"); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(conn, "a001 STARTTLS\r\n"); err != nil {
+		return err
+	}
+	// Skip untagged responses until the tagged response arrives
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(line, "*") {
+			continue
+		}
+		if !strings.HasPrefix(line, "a001 OK") {
+			return fmt.Errorf("Unexpected reply: %s", strings.TrimSpace(line))
+		}
+		return nil
+	}
+}
+
+func expectLinePrefix(r *bufio.Reader, prefix string) error {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, prefix) {
+		return fmt.Errorf("Unexpected reply: %s", strings.TrimSpace(line))
+	}
+	return nil
+}
+
+func starttlsXMPPClient(conn net.Conn, serverName string) error {
+	return starttlsXMPP(conn, serverName, "jabber:client")
+}
+
+func starttlsXMPPServer(conn net.Conn, serverName string) error {
+	return starttlsXMPP(conn, serverName, "jabber:server")
+}
+
+func starttlsXMPP(conn net.Conn, serverName, namespace string) error {
+	to := serverName
+	if to == "" {
+		to = hostFromConn(conn)
+	}
+
+	_, err := fmt.Fprintf(conn, "<?xml version='1.0'?><stream:stream to='%s' xmlns='%s' "+
+		"xmlns:stream='http://etherx.jabber.org/streams' version='1.0'>", to, namespace)
+	if err != nil {
+		return err
+	}
+	if err := readUntil(conn, "</stream:features>"); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(conn, "<starttls xmlns='urn:ietf:params:xml:ns:xmpp-tls'/>"); err != nil {
+		return err
+	}
+	if err := readUntil(conn, "<proceed"); err != nil {
+		return err
+	}
+	// Consume the remainder of the proceed element before the handshake begins
+	tag, err := readUntilBytes(conn, ">")
+	if err == nil && !bytes.HasSuffix(tag, []byte("/>")) {
+		err = readUntil(conn, "</proceed>")
+	}
+	return err
+}
+
+// readUntil reads from the connection one byte at a time, so no bytes of the TLS handshake are consumed.
+func readUntil(conn net.Conn, marker string) error {
+	_, err := readUntilBytes(conn, marker)
+	return err
+}
+
+func readUntilBytes(conn net.Conn, marker string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	b := make([]byte, 1)
+	for buf.Len() < 65536 {
+		if _, err := conn.Read(b); err != nil {
+			return nil, err
+		}
+		buf.WriteByte(b[0])
+
+		if bytes.HasSuffix(buf.Bytes(), []byte(marker)) {
+			return buf.Bytes(), nil
+		}
+		if bytes.HasSuffix(buf.Bytes(), []byte("<failure")) {
+			return nil, errors.New("The XMPP server refused STARTTLS")
+		}
+	}
+	return nil, errors.New("The XMPP server did not offer STARTTLS")
+}
+
+func starttlsLDAP(conn net.Conn, serverName string) error {
+	if _, err := conn.Write(ldapStartTLSRequest); err != nil {
+		return err
+	}
+
+	msg, err := readBERMessage(conn)
+	if err != nil {
+		return err
+	}
+	// Skip the message ID to reach the extended response
+	if len(msg) < 2 || msg[0] != 0x02 {
+		return errors.New("The LDAP response does not have a message ID")
+	}
+	pos := 2 + int(msg[1])
+	if pos >= len(msg) || msg[pos] != 0x78 {
+		return errors.New("The LDAP response is not an extended response")
+	}
+	// Skip the length of the extended response
+	if pos++; pos < len(msg) && msg[pos]&0x80 != 0 {
+		pos += int(msg[pos] & 0x7f)
+	}
+	pos++
+	// The result code must indicate success
+	if pos+3 > len(msg) || !bytes.Equal(msg[pos:pos+3], []byte{0x0a, 0x01, 0x00}) {
+		return errors.New("The LDAP server refused StartTLS")
+	}
+	return nil
+}
+
+// readBERMessage reads a single BER encoded sequence from the connection.
+func readBERMessage(conn net.Conn) ([]byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return nil, err
+	}
+	if header[0] != 0x30 {
+		return nil, errors.New("The LDAP response is not a BER sequence")
+	}
+
+	length := int(header[1])
+	if length&0x80 != 0 {
+		num := length & 0x7f
+		if num == 0 || num > 4 {
+			return nil, errors.New("The LDAP response has an invalid length")
+		}
+
+		lenBytes := make([]byte, num)
+		if _, err := io.ReadFull(conn, lenBytes); err != nil {
+			return nil, err
+		}
+		length = 0
+		for _, b := range lenBytes {
+			length = length<<8 | int(b)
+		}
+	}
+	if length > 65536 {
+		return nil, errors.New("The LDAP response is too large")
+	}
+
+	msg := make([]byte, length)
+	_, err := io.ReadFull(conn, msg)
+	return msg, err
+}
+
+func starttlsPostgreSQL(conn net.Conn, serverName string) error {
+	if _, err := conn.Write(postgresSSLRequest); err != nil {
+		return err
+	}
+
+	reply := make([]byte, 1)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return err
+	}
+	if reply[0] != 'S' {
+		return errors.New("The PostgreSQL server does not support SSL")
+	}
+	return nil
+}
+
+func hostFromConn(conn net.Conn) string {
+	host, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		return ""
+	}
+	return host
+}
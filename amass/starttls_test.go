@@ -0,0 +1,194 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/amass/utils"
+)
+
+func testCertificate(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "mail.example.com"},
+		DNSNames:     []string{"mail.example.com", "imap.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// startTLSServer accepts a single connection, performs the server side of the
+// plaintext negotiation and then completes the TLS handshake.
+func startTLSServer(t *testing.T, negotiate func(conn net.Conn, r *bufio.Reader) bool) (string, int, chan string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sni := make(chan string, 1)
+	cert := testCertificate(t)
+	go func() {
+		defer ln.Close()
+
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+		if negotiate != nil && !negotiate(conn, bufio.NewReader(conn)) {
+			return
+		}
+
+		c := tls.Server(conn, &tls.Config{
+			GetCertificate: func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+				sni <- hello.ServerName
+				return &cert, nil
+			},
+		})
+		c.Handshake()
+	}()
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	p, _ := strconv.Atoi(port)
+	return host, p, sni
+}
+
+func readLine(r *bufio.Reader, prefix string) bool {
+	line, err := r.ReadString('\n')
+	return err == nil && strings.HasPrefix(line, prefix)
+}
+
+func TestPullCertificateSTARTTLS(t *testing.T) {
+	tests := []struct {
+		name      string
+		starttls  starttlsFunc
+		negotiate func(conn net.Conn, r *bufio.Reader) bool
+	}{
+		{"TLS", nil, nil},
+		{"SMTP", starttlsSMTP, func(conn net.Conn, r *bufio.Reader) bool {
+			io.WriteString(conn, "220 mail.example.com ESMTP\r\n")
+			if !readLine(r, "EHLO") {
+				return false
+			}
+			io.WriteString(conn, "250-mail.example.com\r\n250-PIPELINING\r\n250 STARTTLS\r\n")
+			if !readLine(r, "STARTTLS") {
+				return false
+			}
+			io.WriteString(conn, "220 Ready to start TLS\r\n")
+			return true
+		}},
+		{"FTP", starttlsFTP, func(conn net.Conn, r *bufio.Reader) bool {
+			io.WriteString(conn, "220-Welcome\r\n220 FTP server ready\r\n")
+			if !readLine(r, "AUTH TLS") {
+				return false
+			}
+			io.WriteString(conn, "234 AUTH TLS successful\r\n")
+			return true
+		}},
+		{"POP3", starttlsPOP3, func(conn net.Conn, r *bufio.Reader) bool {
+			io.WriteString(conn, "+OK POP3 ready\r\n")
+			if !readLine(r, "STLS") {
+				return false
+			}
+			io.WriteString(conn, "+OK Begin TLS negotiation\r\n")
+			return true
+		}},
+		{"IMAP", starttlsIMAP, func(conn net.Conn, r *bufio.Reader) bool {
+			io.WriteString(conn, "* OK IMAP4rev1 ready\r\n")
+			if !readLine(r, "a001 STARTTLS") {
+				return false
+			}
+			io.WriteString(conn, "* CAPABILITY IMAP4rev1\r\na001 OK Begin TLS negotiation now\r\n")
+			return true
+		}},
+		{"XMPP", starttlsXMPPClient, func(conn net.Conn, r *bufio.Reader) bool {
+			// Read the XML declaration and the stream header
+			for i := 0; i < 2; i++ {
+				if _, err := r.ReadString('>'); err != nil {
+					return false
+				}
+			}
+			io.WriteString(conn, "<stream:stream><stream:features><starttls xmlns='urn:ietf:params:xml:ns:xmpp-tls'>"+
+				"<required/></starttls></stream:features>")
+			if line, err := r.ReadString('>'); err != nil || !strings.Contains(line, "<starttls") {
+				return false
+			}
+			io.WriteString(conn, "<proceed xmlns='urn:ietf:params:xml:ns:xmpp-tls'/>")
+			return true
+		}},
+		{"LDAP", starttlsLDAP, func(conn net.Conn, r *bufio.Reader) bool {
+			req := make([]byte, len(ldapStartTLSRequest))
+			if _, err := io.ReadFull(r, req); err != nil {
+				return false
+			}
+			// Extended response with message ID 1 and the success result code
+			conn.Write([]byte{0x30, 0x0c, 0x02, 0x01, 0x01, 0x78, 0x07,
+				0x0a, 0x01, 0x00, 0x04, 0x00, 0x04, 0x00})
+			return true
+		}},
+		{"PostgreSQL", starttlsPostgreSQL, func(conn net.Conn, r *bufio.Reader) bool {
+			req := make([]byte, len(postgresSSLRequest))
+			if _, err := io.ReadFull(r, req); err != nil {
+				return false
+			}
+			conn.Write([]byte{'S'})
+			return true
+		}},
+	}
+
+	for _, test := range tests {
+		host, port, sni := startTLSServer(t, test.negotiate)
+
+		cert, err := pullCertificate(host, "mail.example.com", port, test.starttls)
+		if err != nil {
+			t.Errorf("%s: failed to pull the certificate: %v", test.name, err)
+			continue
+		}
+		if names := utils.CertificateNames(cert); len(names) != 2 || names[1] != "imap.example.com" {
+			t.Errorf("%s: returned the unexpected names %v", test.name, names)
+		}
+		if name := <-sni; name != "mail.example.com" {
+			t.Errorf("%s: the server name %s was sent instead of mail.example.com", test.name, name)
+		}
+	}
+}
+
+func TestPullCertificateSTARTTLSRefused(t *testing.T) {
+	host, port, _ := startTLSServer(t, func(conn net.Conn, r *bufio.Reader) bool {
+		io.WriteString(conn, "+OK POP3 ready\r\n")
+		readLine(r, "STLS")
+		io.WriteString(conn, "-ERR Command not permitted\r\n")
+		return false
+	})
+
+	if _, err := pullCertificate(host, "", port, starttlsPOP3); err == nil {
+		t.Errorf("pullCertificate did not return an error when STARTTLS was refused")
+	}
+}
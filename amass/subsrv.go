@@ -28,6 +28,8 @@ type SubdomainService struct {
 	releases chan struct{}
 
 	completions chan time.Time
+
+	resolved *progressCounter
 }
 
 // NewSubdomainService requires the enumeration configuration and event bus as parameters.
@@ -40,6 +42,7 @@ func NewSubdomainService(config *core.AmassConfig, bus evbus.Bus) *SubdomainServ
 		subdomains:  make(map[string]int),
 		releases:    make(chan struct{}, max),
 		completions: make(chan time.Time, max),
+		resolved:    newProgressCounter(),
 	}
 
 	ss.BaseAmassService = *core.NewBaseAmassService("Subdomain Service", config, ss)
@@ -111,6 +114,11 @@ func (ss *SubdomainService) processRequests() {
 	}
 }
 
+// Resolved returns the number of names resolved and the recent number resolved each second.
+func (ss *SubdomainService) Resolved() (uint64, float64) {
+	return ss.resolved.Done(), ss.resolved.Rate()
+}
+
 func (ss *SubdomainService) sendCompletionTime(t time.Time) {
 	ss.completions <- t
 }
@@ -142,6 +150,7 @@ func (ss *SubdomainService) performRequest(req *core.AmassRequest) {
 
 func (ss *SubdomainService) performCheck(req *core.AmassRequest) {
 	ss.SetActive()
+	ss.resolved.Inc()
 
 	if ss.Config().IsDomainInScope(req.Name) {
 		ss.checkSubdomain(req)
@@ -0,0 +1,74 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// CertificateFingerprint returns the hex encoded SHA-256 fingerprint of the certificate.
+func CertificateFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// CertificateKeyType returns a description of the public key algorithm and size.
+func CertificateKeyType(cert *x509.Certificate) string {
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return "RSA " + strconv.Itoa(key.N.BitLen())
+	case *ecdsa.PublicKey:
+		return "ECDSA " + key.Curve.Params().Name
+	}
+	return cert.PublicKeyAlgorithm.String()
+}
+
+// CertificateSelfSigned returns true when the certificate was signed by its own key.
+func CertificateSelfSigned(cert *x509.Certificate) bool {
+	if !bytes.Equal(cert.RawIssuer, cert.RawSubject) {
+		return false
+	}
+	// CheckSignatureFrom is not used, since leaf certificates are not permitted to sign
+	return cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil
+}
+
+// CertificateProperties returns the metadata of the certificate as properties
+// that can be stored with the certificate node.
+func CertificateProperties(cert *x509.Certificate) map[string]string {
+	return map[string]string{
+		"fingerprint": CertificateFingerprint(cert),
+		"subject":     cert.Subject.String(),
+		"issuer":      cert.Issuer.String(),
+		"serial":      fmt.Sprintf("%x", cert.SerialNumber),
+		"not_before":  cert.NotBefore.UTC().Format(time.RFC3339),
+		"not_after":   cert.NotAfter.UTC().Format(time.RFC3339),
+		"key_type":    CertificateKeyType(cert),
+		"self_signed": strconv.FormatBool(CertificateSelfSigned(cert)),
+	}
+}
+
+// CertificateNames returns the subject common name and the DNS names within the certificate.
+func CertificateNames(cert *x509.Certificate) []string {
+	var subdomains []string
+
+	// Add the subject common name to the list of subdomain names
+	if commonName := RemoveAsteriskLabel(cert.Subject.CommonName); commonName != "" {
+		subdomains = append(subdomains, commonName)
+	}
+	// Add the cert DNS names to the list of subdomain names
+	for _, name := range cert.DNSNames {
+		if n := RemoveAsteriskLabel(name); n != "" {
+			subdomains = UniqueAppend(subdomains, n)
+		}
+	}
+	return subdomains
+}
@@ -0,0 +1,196 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	httpLock sync.Mutex
+	// The proxy used for all outbound web requests
+	httpProxy *url.URL
+	// The root certificate authorities trusted during web requests
+	httpRootCAs *x509.CertPool
+	// Headers added to every outbound web request
+	httpHeaders map[string]string
+	// User agent strings that are rotated through during web requests
+	userAgents []string
+	// The transport shared by all clients created by NewHTTPClient
+	httpTransport *http.Transport
+)
+
+// SetHTTPProxy configures the proxy used by all outbound web requests. The URL scheme
+// can be http, https or socks5, and credentials can be provided in the user info section.
+// Providing an empty string removes the proxy.
+func SetHTTPProxy(proxy string) error {
+	var u *url.URL
+
+	if proxy != "" {
+		var err error
+
+		u, err = url.Parse(proxy)
+		if err != nil {
+			return fmt.Errorf("Failed to parse the proxy URL %s: %v", proxy, err)
+		}
+
+		switch strings.ToLower(u.Scheme) {
+		case "http", "https", "socks5":
+		default:
+			return fmt.Errorf("The proxy URL scheme %s is not supported", u.Scheme)
+		}
+	}
+
+	httpLock.Lock()
+	defer httpLock.Unlock()
+
+	httpProxy = u
+	httpTransport = nil
+	return nil
+}
+
+// SetHTTPCABundle adds the PEM encoded certificates in the provided file to the
+// certificate authorities trusted by all outbound web requests.
+func SetHTTPCABundle(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("Failed to read the CA bundle %s: %v", path, err)
+	}
+
+	httpLock.Lock()
+	defer httpLock.Unlock()
+
+	pool := httpRootCAs
+	if pool == nil {
+		pool, err = x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return fmt.Errorf("No certificates were found in the CA bundle %s", path)
+	}
+
+	httpRootCAs = pool
+	httpTransport = nil
+	return nil
+}
+
+// SetHTTPHeaders provides header values that are added to every outbound web request.
+func SetHTTPHeaders(hvals map[string]string) {
+	httpLock.Lock()
+	defer httpLock.Unlock()
+
+	httpHeaders = make(map[string]string)
+	for k, v := range hvals {
+		httpHeaders[k] = v
+	}
+}
+
+// SetUserAgents provides the user agent strings that will be randomly rotated through
+// during outbound web requests. Providing an empty slice restores the default user agent.
+func SetUserAgents(agents []string) {
+	httpLock.Lock()
+	defer httpLock.Unlock()
+
+	userAgents = []string{}
+	for _, agent := range agents {
+		if a := strings.TrimSpace(agent); a != "" {
+			userAgents = append(userAgents, a)
+		}
+	}
+}
+
+// NextUserAgent returns the user agent string that should be used by the next web request.
+func NextUserAgent() string {
+	httpLock.Lock()
+	defer httpLock.Unlock()
+
+	if len(userAgents) == 0 {
+		return UserAgent
+	}
+	return userAgents[rand.Intn(len(userAgents))]
+}
+
+// NewHTTPClient returns a client that honors the proxy, certificate authorities
+// and other settings configured for all outbound web requests.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: sharedTransport(),
+	}
+}
+
+// SetHTTPRequestHeaders adds the default, configured and provided header values to the request.
+func SetHTTPRequestHeaders(req *http.Request, hvals map[string]string) {
+	req.Header.Set("User-Agent", NextUserAgent())
+	req.Header.Set("Accept", Accept)
+	req.Header.Set("Accept-Language", AcceptLang)
+
+	httpLock.Lock()
+	for k, v := range httpHeaders {
+		req.Header.Set(k, v)
+	}
+	httpLock.Unlock()
+
+	for k, v := range hvals {
+		req.Header.Set(k, v)
+	}
+}
+
+// NewInsecureHTTPClient returns a client that honors the proxy and other settings configured
+// for all outbound web requests, but does not verify the certificates presented by servers.
+func NewInsecureHTTPClient(timeout time.Duration) *http.Client {
+	httpLock.Lock()
+	defer httpLock.Unlock()
+
+	tr := newTransport()
+	tr.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: tr,
+	}
+}
+
+func sharedTransport() *http.Transport {
+	httpLock.Lock()
+	defer httpLock.Unlock()
+
+	if httpTransport == nil {
+		httpTransport = newTransport()
+	}
+	return httpTransport
+}
+
+// newTransport builds a transport from the current settings. The caller must hold httpLock.
+func newTransport() *http.Transport {
+	d := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	tr := &http.Transport{
+		DialContext:           d.DialContext,
+		MaxIdleConns:          200,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 5 * time.Second,
+	}
+	if httpProxy != nil {
+		tr.Proxy = http.ProxyURL(httpProxy)
+	}
+	if httpRootCAs != nil {
+		tr.TLSClientConfig = &tls.Config{RootCAs: httpRootCAs}
+	}
+	return tr
+}
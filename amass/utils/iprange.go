@@ -0,0 +1,291 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	bigOne    = big.NewInt(1)
+	ipv6Space = new(big.Int).Lsh(bigOne, 128)
+)
+
+// IPRange is the inclusive range of IP addresses between First and Last.
+type IPRange struct {
+	First net.IP
+	Last  net.IP
+}
+
+// NewIPRange returns the range between the two addresses, which can be provided in any order.
+func NewIPRange(a, b net.IP) (IPRange, error) {
+	first, last := a.To16(), b.To16()
+	if first == nil || last == nil {
+		return IPRange{}, errors.New("The IP range requires two valid addresses")
+	}
+	if (a.To4() == nil) != (b.To4() == nil) {
+		return IPRange{}, fmt.Errorf("The range %s-%s mixes IPv4 and IPv6 addresses", a, b)
+	}
+
+	if new(big.Int).SetBytes(first).Cmp(new(big.Int).SetBytes(last)) > 0 {
+		first, last = last, first
+	}
+	return IPRange{First: first, Last: last}, nil
+}
+
+// ParseIPRange parses the first-last notation, where the last address can be abbreviated
+// to the final IPv4 octet in decimal (192.0.2.1-50) or the final IPv6 group in hex (2001:db8::1-ff).
+func ParseIPRange(s string) (IPRange, error) {
+	parts := strings.SplitN(strings.TrimSpace(s), "-", 2)
+	if len(parts) != 2 {
+		return IPRange{}, fmt.Errorf("%s is not a valid IP range", s)
+	}
+
+	first := net.ParseIP(strings.TrimSpace(parts[0]))
+	if first == nil {
+		return IPRange{}, fmt.Errorf("%s is not a valid IP range", s)
+	}
+
+	suffix := strings.TrimSpace(parts[1])
+	last := net.ParseIP(suffix)
+	if last == nil {
+		var err error
+		if last, err = replaceLastGroup(first, suffix); err != nil {
+			return IPRange{}, fmt.Errorf("%s is not a valid IP range: %v", s, err)
+		}
+	}
+	return NewIPRange(first, last)
+}
+
+// replaceLastGroup returns the address with the final IPv4 octet or IPv6 group replaced.
+func replaceLastGroup(ip net.IP, group string) (net.IP, error) {
+	if ip4 := ip.To4(); ip4 != nil {
+		n, err := strconv.ParseUint(group, 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a valid IPv4 octet", group)
+		}
+
+		last := append(net.IP{}, ip4...)
+		last[3] = byte(n)
+		return last, nil
+	}
+
+	n, err := strconv.ParseUint(group, 16, 16)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a valid IPv6 group", group)
+	}
+
+	last := append(net.IP{}, ip.To16()...)
+	last[14], last[15] = byte(n>>8), byte(n)
+	return last, nil
+}
+
+// CIDRHosts returns the range of host addresses within the CIDR. As with NetHosts,
+// the network and broadcast addresses are not included when the netblock has them.
+func CIDRHosts(cidr *net.IPNet) IPRange {
+	first, last := NetFirstLast(cidr)
+	first, last = first.To16(), last.To16()
+
+	if ones, bits := cidr.Mask.Size(); bits-ones >= 2 {
+		first = ipAdd(first, bigOne)
+		last = ipAdd(last, big.NewInt(-1))
+	}
+	return IPRange{First: first, Last: last}
+}
+
+// Size returns the number of addresses within the range.
+func (r IPRange) Size() *big.Int {
+	size := new(big.Int).Sub(new(big.Int).SetBytes(r.Last.To16()), new(big.Int).SetBytes(r.First.To16()))
+	return size.Add(size, bigOne)
+}
+
+// Contains returns true when the address is within the range.
+func (r IPRange) Contains(ip net.IP) bool {
+	n := new(big.Int).SetBytes(ip.To16())
+
+	return new(big.Int).SetBytes(r.First.To16()).Cmp(n) <= 0 &&
+		new(big.Int).SetBytes(r.Last.To16()).Cmp(n) >= 0
+}
+
+// String returns the range in the first-last notation.
+func (r IPRange) String() string {
+	return r.First.String() + "-" + r.Last.String()
+}
+
+// MergeIPRanges returns the ranges sorted, with the overlapping and adjacent ranges combined,
+// so that each address is only provided once.
+func MergeIPRanges(ranges []IPRange) []IPRange {
+	sorted := append([]IPRange{}, ranges...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].First.To16(), sorted[j].First.To16()) < 0
+	})
+
+	var merged []IPRange
+	for _, r := range sorted {
+		r = IPRange{First: r.First.To16(), Last: r.Last.To16()}
+
+		if n := len(merged); n > 0 {
+			prev := &merged[n-1]
+			// IPv4 and IPv6 ranges are never combined
+			if (prev.Last.To4() == nil) == (r.First.To4() == nil) &&
+				bytes.Compare(ipAdd(prev.Last, bigOne), r.First) >= 0 {
+				if bytes.Compare(r.Last, prev.Last) > 0 {
+					prev.Last = r.Last
+				}
+				continue
+			}
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// ipAdd returns the address n addresses after ip, wrapping around the IPv6 address space.
+func ipAdd(ip net.IP, n *big.Int) net.IP {
+	sum := new(big.Int).Add(new(big.Int).SetBytes(ip.To16()), n)
+	return intToIP(sum.Mod(sum, ipv6Space), 128)
+}
+
+// IPIterator produces the addresses within a set of ranges one at a time, so that large
+// scopes can be processed without storing every address. The iterator can be limited to
+// one shard of the addresses, and can produce the addresses in a pseudorandom order.
+type IPIterator struct {
+	ranges []IPRange
+	// The index of the first address of each range
+	offsets []*big.Int
+	total   *big.Int
+
+	shard  *big.Int
+	shards *big.Int
+
+	// The index of the next address in sequential order
+	next *big.Int
+
+	// The linear congruential generator that permutes the indices in random order
+	random bool
+	mod    *big.Int
+	mult   *big.Int
+	incr   *big.Int
+	state  *big.Int
+	steps  *big.Int
+}
+
+// NewIPIterator returns an iterator over the addresses of the ranges in sequential order.
+func NewIPIterator(ranges ...IPRange) *IPIterator {
+	it := &IPIterator{
+		ranges: ranges,
+		total:  big.NewInt(0),
+		shard:  big.NewInt(0),
+		shards: big.NewInt(1),
+		next:   big.NewInt(0),
+	}
+
+	for _, r := range ranges {
+		it.offsets = append(it.offsets, new(big.Int).Set(it.total))
+		it.total.Add(it.total, r.Size())
+	}
+	return it
+}
+
+// Total returns the number of addresses the iterator produces, which is the number
+// of addresses within all the ranges that belong to the shard.
+func (it *IPIterator) Total() *big.Int {
+	// The indices of the shard are index, index+count, index+2*count, ...
+	if it.total.Cmp(it.shard) <= 0 {
+		return big.NewInt(0)
+	}
+
+	n := new(big.Int).Sub(it.total, it.shard)
+	n.Add(n, it.shards).Sub(n, bigOne)
+	return n.Div(n, it.shards)
+}
+
+// Shard limits the iterator to the addresses of shard index (starting at zero) out of count
+// shards. Iterators configured with each index produce all the addresses exactly once.
+func (it *IPIterator) Shard(index, count int) error {
+	if count < 1 || index < 0 || index >= count {
+		return fmt.Errorf("%d is not a valid shard of %d", index, count)
+	}
+
+	it.shard = big.NewInt(int64(index))
+	it.shards = big.NewInt(int64(count))
+	it.next = new(big.Int).Set(it.shard)
+	return nil
+}
+
+// Randomize causes the addresses to be produced in a pseudorandom order determined by the seed.
+func (it *IPIterator) Randomize(seed int64) {
+	r := rand.New(rand.NewSource(seed))
+
+	// The generator walks a full cycle of the smallest power of two holding all the indices
+	it.mod = new(big.Int).Lsh(bigOne, uint(it.total.BitLen()))
+	// The multiplier must be one more than a multiple of four, and the increment must be odd
+	it.mult = new(big.Int).Rand(r, it.mod)
+	it.mult.Lsh(it.mult, 2).Add(it.mult, bigOne).Mod(it.mult, it.mod)
+	it.incr = new(big.Int).Rand(r, it.mod)
+	it.incr.Lsh(it.incr, 1).Add(it.incr, bigOne).Mod(it.incr, it.mod)
+	it.state = new(big.Int).Rand(r, it.mod)
+	it.steps = big.NewInt(0)
+	it.random = true
+}
+
+// Next returns the next address, or nil when all the addresses have been produced.
+func (it *IPIterator) Next() net.IP {
+	for {
+		idx := it.nextIndex()
+		if idx == nil {
+			return nil
+		}
+
+		// Only the indices belonging to the shard are used
+		if it.random && new(big.Int).Mod(idx, it.shards).Cmp(it.shard) != 0 {
+			continue
+		}
+		return it.address(idx)
+	}
+}
+
+func (it *IPIterator) nextIndex() *big.Int {
+	if !it.random {
+		if it.next.Cmp(it.total) >= 0 {
+			return nil
+		}
+
+		idx := new(big.Int).Set(it.next)
+		it.next.Add(it.next, it.shards)
+		return idx
+	}
+
+	// Indices outside of the ranges are skipped until the cycle is complete
+	for it.steps.Cmp(it.mod) < 0 {
+		it.state.Mul(it.state, it.mult).Add(it.state, it.incr).Mod(it.state, it.mod)
+		it.steps.Add(it.steps, bigOne)
+
+		if it.state.Cmp(it.total) < 0 {
+			return new(big.Int).Set(it.state)
+		}
+	}
+	return nil
+}
+
+// address returns the address at the index within the concatenated ranges.
+func (it *IPIterator) address(idx *big.Int) net.IP {
+	i := sort.Search(len(it.offsets), func(i int) bool {
+		return it.offsets[i].Cmp(idx) > 0
+	}) - 1
+
+	ip := ipAdd(it.ranges[i].First, new(big.Int).Sub(idx, it.offsets[i]))
+	if it.ranges[i].First.To4() != nil {
+		return ip.To4()
+	}
+	return ip
+}
@@ -0,0 +1,167 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func testIPRanges(t *testing.T) []IPRange {
+	_, ipnet, _ := net.ParseCIDR("192.0.2.0/28")
+	r, err := NewIPRange(net.ParseIP("2001:db8::20"), net.ParseIP("2001:db8::10"))
+	if err != nil {
+		t.Fatalf("NewIPRange failed: %v", err)
+	}
+	return []IPRange{CIDRHosts(ipnet), r}
+}
+
+func TestIPIteratorSequential(t *testing.T) {
+	ranges := testIPRanges(t)
+	it := NewIPIterator(ranges...)
+
+	// The /28 provides 14 hosts and the IPv6 range 17 addresses
+	if total := it.Total().Int64(); total != 31 {
+		t.Fatalf("Total returned %d instead of 31", total)
+	}
+
+	var ips []string
+	for ip := it.Next(); ip != nil; ip = it.Next() {
+		ips = append(ips, ip.String())
+	}
+	if len(ips) != 31 || ips[0] != "192.0.2.1" || ips[13] != "192.0.2.14" ||
+		ips[14] != "2001:db8::10" || ips[30] != "2001:db8::20" {
+		t.Errorf("The iterator produced the unexpected addresses %v", ips)
+	}
+}
+
+func TestIPIteratorShardsAndRandomOrder(t *testing.T) {
+	ranges := testIPRanges(t)
+
+	for _, random := range []bool{false, true} {
+		seen := make(map[string]int)
+		var order []string
+
+		for shard := 0; shard < 3; shard++ {
+			it := NewIPIterator(ranges...)
+			if err := it.Shard(shard, 3); err != nil {
+				t.Fatalf("Shard failed: %v", err)
+			}
+			// The 31 addresses are divided into shards of 11, 10 and 10 addresses
+			expected := int64(10)
+			if shard == 0 {
+				expected = 11
+			}
+			if total := it.Total().Int64(); total != expected {
+				t.Errorf("Total returned %d instead of %d for shard %d", total, expected, shard)
+			}
+			if random {
+				it.Randomize(42)
+			}
+
+			for ip := it.Next(); ip != nil; ip = it.Next() {
+				seen[ip.String()]++
+				order = append(order, ip.String())
+			}
+		}
+
+		if len(seen) != 31 {
+			t.Errorf("The shards produced %d unique addresses instead of 31", len(seen))
+		}
+		for addr, count := range seen {
+			if count != 1 {
+				t.Errorf("%s was produced %d times", addr, count)
+			}
+			var found bool
+			for _, r := range ranges {
+				if r.Contains(net.ParseIP(addr)) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("%s is outside of the ranges", addr)
+			}
+		}
+		if random && order[0] == "192.0.2.1" && order[1] == "192.0.2.4" {
+			t.Errorf("The random order matched the sequential order")
+		}
+	}
+
+	if err := NewIPIterator(ranges...).Shard(3, 3); err == nil {
+		t.Errorf("Shard did not return an error for an invalid shard index")
+	}
+}
+
+func TestIPIteratorLargeRange(t *testing.T) {
+	_, ipnet, _ := net.ParseCIDR("2001:db8::/32")
+	it := NewIPIterator(CIDRHosts(ipnet))
+	it.Randomize(1)
+
+	// The addresses are produced without storing the range
+	for i := 0; i < 1000; i++ {
+		if ip := it.Next(); ip == nil || !ipnet.Contains(ip) {
+			t.Fatalf("The iterator produced %v for the /32", ip)
+		}
+	}
+	if it.Total().BitLen() != 96 {
+		t.Errorf("Total returned %s for the /32", it.Total())
+	}
+}
+
+func TestNewIPRange(t *testing.T) {
+	if _, err := NewIPRange(net.ParseIP("192.0.2.1"), net.ParseIP("2001:db8::1")); err == nil {
+		t.Errorf("NewIPRange did not return an error for mixed address families")
+	}
+
+	r, err := NewIPRange(net.ParseIP("192.0.2.50"), net.ParseIP("192.0.2.1"))
+	if err != nil || r.String() != "192.0.2.1-192.0.2.50" || r.Size().Int64() != 50 {
+		t.Errorf("NewIPRange returned %v and %v", r, err)
+	}
+}
+
+func TestParseIPRange(t *testing.T) {
+	tests := map[string]string{
+		"192.0.2.1-192.0.2.50":      "192.0.2.1-192.0.2.50",
+		"192.0.2.1-50":              "192.0.2.1-192.0.2.50",
+		"2001:db8::1-2001:db8::1:0": "2001:db8::1-2001:db8::1:0",
+		"2001:db8::1-ff":            "2001:db8::1-2001:db8::ff",
+		"2001:db8::a:1-FFFF":        "2001:db8::a:1-2001:db8::a:ffff",
+	}
+
+	for s, expected := range tests {
+		if r, err := ParseIPRange(s); err != nil || r.String() != expected {
+			t.Errorf("ParseIPRange(%s) returned %v and %v instead of %s", s, r, err, expected)
+		}
+	}
+
+	for _, s := range []string{"192.0.2.1", "192.0.2.1-256", "2001:db8::1-10000", "192.0.2.1-2001:db8::1", "x-y"} {
+		if _, err := ParseIPRange(s); err == nil {
+			t.Errorf("ParseIPRange did not return an error for %s", s)
+		}
+	}
+}
+
+func TestMergeIPRanges(t *testing.T) {
+	var ranges []IPRange
+	for _, r := range [][2]string{
+		{"192.0.2.100", "192.0.2.200"},
+		{"192.0.2.1", "192.0.2.50"},
+		{"192.0.2.51", "192.0.2.60"},
+		{"192.0.2.150", "192.0.2.250"},
+		{"2001:db8::1", "2001:db8::5"},
+	} {
+		ipr, _ := NewIPRange(net.ParseIP(r[0]), net.ParseIP(r[1]))
+		ranges = append(ranges, ipr)
+	}
+
+	var got []string
+	for _, r := range MergeIPRanges(ranges) {
+		got = append(got, r.String())
+	}
+	expected := "192.0.2.1-192.0.2.60 192.0.2.100-192.0.2.250 2001:db8::1-2001:db8::5"
+	if strings.Join(got, " ") != expected {
+		t.Errorf("MergeIPRanges returned %v instead of %s", got, expected)
+	}
+}
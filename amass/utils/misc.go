@@ -6,6 +6,8 @@ package utils
 import (
 	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/irfansharif/cfilter"
 )
@@ -70,6 +72,56 @@ func (s *Semaphore) Release(num int) {
 	}
 }
 
+// RateLimiter implements an object that paces operations to a maximum number per second.
+type RateLimiter struct {
+	ticker *time.Ticker
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewRateLimiter returns a RateLimiter that allows perSec operations each second.
+func NewRateLimiter(perSec int) *RateLimiter {
+	if perSec <= 0 {
+		perSec = 1
+	}
+
+	// Rates above one per nanosecond are limited to that rate, since tickers require a positive interval
+	interval := time.Second / time.Duration(perSec)
+	if interval <= 0 {
+		interval = time.Nanosecond
+	}
+
+	return &RateLimiter{
+		ticker: time.NewTicker(interval),
+		done:   make(chan struct{}),
+	}
+}
+
+// Take blocks until the next operation is allowed to proceed.
+// The method returns false when the RateLimiter has been stopped.
+func (rl *RateLimiter) Take() bool {
+	select {
+	case <-rl.done:
+		return false
+	default:
+	}
+
+	select {
+	case <-rl.ticker.C:
+		return true
+	case <-rl.done:
+		return false
+	}
+}
+
+// Stop releases the resources used by the RateLimiter and unblocks all callers of Take.
+func (rl *RateLimiter) Stop() {
+	rl.once.Do(func() {
+		rl.ticker.Stop()
+		close(rl.done)
+	})
+}
+
 type filterRequest struct {
 	String string
 	Result chan bool
@@ -136,28 +188,16 @@ func AnySubdomainRegex() *regexp.Regexp {
 func NewUniqueElements(orig []string, add ...string) []string {
 	var n []string
 
+	seen := make(map[string]struct{}, len(orig)+len(add))
+	for _, ov := range orig {
+		seen[strings.ToLower(ov)] = struct{}{}
+	}
+
 	for _, av := range add {
-		found := false
 		s := strings.ToLower(av)
-
-		// Check the original slice for duplicates
-		for _, ov := range orig {
-			if s == strings.ToLower(ov) {
-				found = true
-				break
-			}
-		}
-		// Check that we didn't already add it in
-		if !found {
-			for _, nv := range n {
-				if s == nv {
-					found = true
-					break
-				}
-			}
-		}
 		// If no duplicates were found, add the entry in
-		if !found {
+		if _, found := seen[s]; !found {
+			seen[s] = struct{}{}
 			n = append(n, s)
 		}
 	}
@@ -0,0 +1,50 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	rl := NewRateLimiter(50)
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if !rl.Take() {
+			t.Fatalf("Take returned false before the RateLimiter was stopped")
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("Five operations at 50 per second took %s", elapsed)
+	}
+
+	// Stop releases the callers waiting in Take
+	done := make(chan bool)
+	go func() { done <- rl.Take() }()
+	rl.Stop()
+	select {
+	case ok := <-done:
+		if ok {
+			t.Errorf("Take returned true after the RateLimiter was stopped")
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Take did not return after the RateLimiter was stopped")
+	}
+	rl.Stop()
+	if rl.Take() {
+		t.Errorf("Take returned true after the RateLimiter was stopped")
+	}
+}
+
+func TestRateLimiterLargeRate(t *testing.T) {
+	// The interval of rates above one per nanosecond must not be zero
+	rl := NewRateLimiter(2000000000)
+	defer rl.Stop()
+
+	if !rl.Take() {
+		t.Errorf("Take returned false for the RateLimiter with a large rate")
+	}
+}
@@ -0,0 +1,460 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"net"
+)
+
+// The marker that precedes the metadata section of a MaxMind DB file
+var mmdbMetadataStart = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// The number of bytes between the search tree and the data section
+const mmdbDataSeparator = 16
+
+// The deepest nesting of pointers, maps and arrays that will be decoded
+const mmdbMaxDepth = 512
+
+// MMDBReader performs lookups within a MaxMind DB file, such as the GeoLite2 ASN and City databases.
+// Decoded values are strings, float32, float64, []byte, uint64, int32, *big.Int, bool,
+// []interface{} and map[string]interface{}.
+type MMDBReader struct {
+	// The type of data within the database, such as GeoLite2-ASN
+	DatabaseType string
+
+	// The IP version of the search tree (4 or 6)
+	IPVersion int
+
+	// All the values within the metadata section
+	Metadata map[string]interface{}
+
+	buf        []byte
+	data       []byte
+	nodeCount  int
+	recordSize int
+	ipv4Start  int
+}
+
+// IsMMDB returns true when the buffer contains the MaxMind DB metadata marker.
+func IsMMDB(buf []byte) bool {
+	return bytes.LastIndex(buf, mmdbMetadataStart) != -1
+}
+
+// OpenMMDB reads the MaxMind DB file into memory and returns a reader for the database.
+func OpenMMDB(path string) (*MMDBReader, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read the MaxMind DB file %s: %v", path, err)
+	}
+	return NewMMDBReader(buf)
+}
+
+// NewMMDBReader returns a reader for the MaxMind DB contained in the buffer.
+func NewMMDBReader(buf []byte) (*MMDBReader, error) {
+	idx := bytes.LastIndex(buf, mmdbMetadataStart)
+	if idx == -1 {
+		return nil, errors.New("The MaxMind DB metadata section was not found")
+	}
+
+	meta := &mmdbDecoder{buf: buf[idx+len(mmdbMetadataStart):]}
+	value, _, err := meta.decode(0)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode the MaxMind DB metadata: %v", err)
+	}
+	metadata, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("The MaxMind DB metadata is not a map")
+	}
+
+	r := &MMDBReader{
+		Metadata:   metadata,
+		buf:        buf,
+		nodeCount:  int(mmdbUint(metadata["node_count"])),
+		recordSize: int(mmdbUint(metadata["record_size"])),
+		IPVersion:  int(mmdbUint(metadata["ip_version"])),
+	}
+	r.DatabaseType, _ = metadata["database_type"].(string)
+
+	switch r.recordSize {
+	case 24, 28, 32:
+	default:
+		return nil, fmt.Errorf("The MaxMind DB record size %d is not supported", r.recordSize)
+	}
+	if r.IPVersion != 4 && r.IPVersion != 6 {
+		return nil, fmt.Errorf("The MaxMind DB IP version %d is not supported", r.IPVersion)
+	}
+
+	treeSize := r.nodeCount * r.recordSize / 4
+	if treeSize+mmdbDataSeparator > idx {
+		return nil, errors.New("The MaxMind DB search tree is larger than the file")
+	}
+	r.data = buf[treeSize+mmdbDataSeparator : idx]
+
+	// IPv4 addresses are found beneath 96 zero bits within IPv6 trees
+	if r.IPVersion == 6 {
+		node := 0
+		for i := 0; i < 96 && node < r.nodeCount; i++ {
+			node = r.record(node, 0)
+		}
+		r.ipv4Start = node
+	}
+	return r, nil
+}
+
+func mmdbUint(v interface{}) uint64 {
+	n, _ := v.(uint64)
+	return n
+}
+
+// record returns the left (bit 0) or right (bit 1) record of the node.
+func (r *MMDBReader) record(node, bit int) int {
+	size := r.recordSize / 4
+	n := r.buf[node*size : node*size+size]
+
+	switch r.recordSize {
+	case 24:
+		n = n[bit*3:]
+		return int(n[0])<<16 | int(n[1])<<8 | int(n[2])
+	case 28:
+		if bit == 0 {
+			return int(n[3]&0xf0)<<20 | int(n[0])<<16 | int(n[1])<<8 | int(n[2])
+		}
+		return int(n[3]&0x0f)<<24 | int(n[4])<<16 | int(n[5])<<8 | int(n[6])
+	}
+	return int(binary.BigEndian.Uint32(n[bit*4:]))
+}
+
+// resolve decodes the data referenced by a record pointing beyond the search tree.
+func (r *MMDBReader) resolve(record int) (interface{}, error) {
+	offset := record - r.nodeCount - mmdbDataSeparator
+	if offset < 0 || offset >= len(r.data) {
+		return nil, errors.New("The MaxMind DB record points outside of the data section")
+	}
+
+	d := &mmdbDecoder{buf: r.data}
+	value, _, err := d.decode(offset)
+	return value, err
+}
+
+// Lookup returns the data and the network associated with the IP address.
+// A nil value is returned when the database has no data for the address.
+func (r *MMDBReader) Lookup(ip net.IP) (interface{}, *net.IPNet, error) {
+	bits := 128
+	node := 0
+	addr := ip.To16()
+	if ip4 := ip.To4(); ip4 != nil {
+		bits = 32
+		addr = ip4
+		node = r.ipv4Start
+	} else if r.IPVersion == 4 || addr == nil {
+		return nil, nil, fmt.Errorf("The address %s cannot be found within the MaxMind DB", ip)
+	}
+
+	var depth int
+	for depth = 0; depth < bits && node < r.nodeCount; depth++ {
+		bit := int(addr[depth/8]>>uint(7-depth%8)) & 1
+		node = r.record(node, bit)
+	}
+
+	ipnet := &net.IPNet{
+		IP:   addr.Mask(net.CIDRMask(depth, bits)),
+		Mask: net.CIDRMask(depth, bits),
+	}
+	if node == r.nodeCount {
+		return nil, ipnet, nil
+	} else if node < r.nodeCount {
+		return nil, nil, errors.New("The MaxMind DB search tree is invalid")
+	}
+
+	value, err := r.resolve(node)
+	return value, ipnet, err
+}
+
+// Networks calls the function for each network within the database that has data.
+// Networks within the IPv4 space of IPv6 databases are provided as IPv4 networks.
+func (r *MMDBReader) Networks(fn func(*net.IPNet, interface{}) error) error {
+	w := &mmdbWalk{
+		reader: r,
+		fn:     fn,
+		cache:  make(map[int]interface{}),
+	}
+
+	bits := 128
+	if r.IPVersion == 4 {
+		bits = 32
+	}
+	return w.walk(0, make([]byte, bits/8), 0)
+}
+
+type mmdbWalk struct {
+	reader *MMDBReader
+	fn     func(*net.IPNet, interface{}) error
+	cache  map[int]interface{}
+}
+
+func (w *mmdbWalk) walk(node int, addr []byte, depth int) error {
+	r := w.reader
+	bits := len(addr) * 8
+
+	if node == r.nodeCount {
+		return nil
+	} else if node > r.nodeCount {
+		value, found := w.cache[node]
+		if !found {
+			var err error
+
+			value, err = r.resolve(node)
+			if err != nil {
+				return err
+			}
+			w.cache[node] = value
+		}
+		return w.fn(mmdbNetwork(addr, depth), value)
+	}
+	if depth >= bits {
+		return errors.New("The MaxMind DB search tree is deeper than the address size")
+	}
+	// Skip the subtrees that alias the IPv4 space within IPv6 databases
+	if bits == 128 && node == r.ipv4Start && depth > 0 && !isIPv4Prefix(addr, depth) {
+		return nil
+	}
+
+	for bit := 0; bit < 2; bit++ {
+		next := make([]byte, len(addr))
+		copy(next, addr)
+		if bit == 1 {
+			next[depth/8] |= 1 << uint(7-depth%8)
+		}
+
+		if err := w.walk(r.record(node, bit), next, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isIPv4Prefix returns true when the IPv6 prefix is within the ::/96 IPv4 space.
+func isIPv4Prefix(addr []byte, depth int) bool {
+	return depth == 96 && bytes.Equal(addr[:12], make([]byte, 12))
+}
+
+func mmdbNetwork(addr []byte, depth int) *net.IPNet {
+	bits := len(addr) * 8
+	ip := net.IP(addr)
+
+	if bits == 128 && depth >= 96 && bytes.Equal(addr[:12], make([]byte, 12)) {
+		ip = net.IPv4(addr[12], addr[13], addr[14], addr[15]).To4()
+		bits = 32
+		depth -= 96
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(depth, bits)}
+}
+
+// mmdbDecoder decodes the values within the data and metadata sections.
+type mmdbDecoder struct {
+	buf []byte
+	// The nesting of the value currently being decoded
+	depth int
+}
+
+const (
+	mmdbPointer   = 1
+	mmdbString    = 2
+	mmdbDouble    = 3
+	mmdbBytes     = 4
+	mmdbUint16    = 5
+	mmdbUint32    = 6
+	mmdbMap       = 7
+	mmdbInt32     = 8
+	mmdbUint64    = 9
+	mmdbUint128   = 10
+	mmdbArray     = 11
+	mmdbContainer = 12
+	mmdbEndMarker = 13
+	mmdbBoolean   = 14
+	mmdbFloat     = 15
+)
+
+// decode returns the value at the offset and the offset following the value.
+func (d *mmdbDecoder) decode(offset int) (interface{}, int, error) {
+	// Pointers can still form a cycle through maps and arrays
+	d.depth++
+	defer func() { d.depth-- }()
+	if d.depth > mmdbMaxDepth {
+		return nil, 0, errors.New("The MaxMind DB data is nested too deeply")
+	}
+
+	typ, size, offset, err := d.control(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if typ == mmdbPointer {
+		// Pointers to pointers are not permitted, which also prevents a pointer from referring to itself
+		if ptyp, _, _, err := d.control(size); err != nil {
+			return nil, 0, err
+		} else if ptyp == mmdbPointer {
+			return nil, 0, errors.New("The MaxMind DB pointer refers to another pointer")
+		}
+		// The value is found at the pointer, while decoding continues after the pointer
+		value, _, err := d.decode(size)
+		return value, offset, err
+	}
+	if offset+d.payloadSize(typ, size) > len(d.buf) {
+		return nil, 0, errors.New("The MaxMind DB value extends beyond the data")
+	}
+
+	switch typ {
+	case mmdbString:
+		return string(d.buf[offset : offset+size]), offset + size, nil
+	case mmdbBytes:
+		b := make([]byte, size)
+		copy(b, d.buf[offset:offset+size])
+		return b, offset + size, nil
+	case mmdbDouble:
+		if size != 8 {
+			return nil, 0, errors.New("The MaxMind DB double has an invalid size")
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(d.buf[offset:])), offset + 8, nil
+	case mmdbFloat:
+		if size != 4 {
+			return nil, 0, errors.New("The MaxMind DB float has an invalid size")
+		}
+		return math.Float32frombits(binary.BigEndian.Uint32(d.buf[offset:])), offset + 4, nil
+	case mmdbUint16, mmdbUint32, mmdbUint64:
+		var n uint64
+		for _, b := range d.buf[offset : offset+size] {
+			n = n<<8 | uint64(b)
+		}
+		return n, offset + size, nil
+	case mmdbInt32:
+		var n uint32
+		for _, b := range d.buf[offset : offset+size] {
+			n = n<<8 | uint32(b)
+		}
+		return int32(n), offset + size, nil
+	case mmdbUint128:
+		return new(big.Int).SetBytes(d.buf[offset : offset+size]), offset + size, nil
+	case mmdbBoolean:
+		return size != 0, offset, nil
+	case mmdbMap:
+		m := make(map[string]interface{}, size)
+		for i := 0; i < size; i++ {
+			var key, value interface{}
+
+			key, offset, err = d.decode(offset)
+			if err != nil {
+				return nil, 0, err
+			}
+			k, ok := key.(string)
+			if !ok {
+				return nil, 0, errors.New("The MaxMind DB map key is not a string")
+			}
+			value, offset, err = d.decode(offset)
+			if err != nil {
+				return nil, 0, err
+			}
+			m[k] = value
+		}
+		return m, offset, nil
+	case mmdbArray:
+		a := make([]interface{}, 0, size)
+		for i := 0; i < size; i++ {
+			var value interface{}
+
+			value, offset, err = d.decode(offset)
+			if err != nil {
+				return nil, 0, err
+			}
+			a = append(a, value)
+		}
+		return a, offset, nil
+	}
+	return nil, 0, fmt.Errorf("The MaxMind DB data type %d is not supported", typ)
+}
+
+// payloadSize returns the smallest number of bytes that can follow the control bytes.
+// Each map entry and array element requires at least one byte for each value, which
+// keeps sizes taken from the file from allocating more than the data can contain.
+func (d *mmdbDecoder) payloadSize(typ, size int) int {
+	switch typ {
+	case mmdbMap:
+		return 2 * size
+	case mmdbArray:
+		return size
+	case mmdbBoolean:
+		return 0
+	}
+	return size
+}
+
+// control returns the type and size of the value at the offset, and the offset of the payload.
+// The size is the location of the referenced value for pointers.
+func (d *mmdbDecoder) control(offset int) (int, int, int, error) {
+	if offset >= len(d.buf) {
+		return 0, 0, 0, errors.New("The MaxMind DB offset is beyond the data")
+	}
+
+	ctrl := d.buf[offset]
+	offset++
+	typ := int(ctrl >> 5)
+	if typ == 0 {
+		if offset >= len(d.buf) {
+			return 0, 0, 0, errors.New("The MaxMind DB extended type is beyond the data")
+		}
+		typ = 7 + int(d.buf[offset])
+		offset++
+	}
+
+	if typ == mmdbPointer {
+		n := int(ctrl>>3) & 0x3
+		if offset+n+1 > len(d.buf) {
+			return 0, 0, 0, errors.New("The MaxMind DB pointer is beyond the data")
+		}
+
+		b := d.buf[offset : offset+n+1]
+		var ptr int
+		switch n {
+		case 0:
+			ptr = int(ctrl&0x7)<<8 | int(b[0])
+		case 1:
+			ptr = (int(ctrl&0x7)<<16 | int(b[0])<<8 | int(b[1])) + 2048
+		case 2:
+			ptr = (int(ctrl&0x7)<<24 | int(b[0])<<16 | int(b[1])<<8 | int(b[2])) + 526336
+		case 3:
+			ptr = int(binary.BigEndian.Uint32(b))
+		}
+		return typ, ptr, offset + n + 1, nil
+	}
+
+	size := int(ctrl & 0x1f)
+	if size >= 29 {
+		n := size - 28
+		if offset+n > len(d.buf) {
+			return 0, 0, 0, errors.New("The MaxMind DB size is beyond the data")
+		}
+
+		b := d.buf[offset : offset+n]
+		switch n {
+		case 1:
+			size = 29 + int(b[0])
+		case 2:
+			size = 285 + (int(b[0])<<8 | int(b[1]))
+		case 3:
+			size = 65821 + (int(b[0])<<16 | int(b[1])<<8 | int(b[2]))
+		}
+		offset += n
+	}
+	if typ == mmdbContainer || typ == mmdbEndMarker {
+		return 0, 0, 0, fmt.Errorf("The MaxMind DB data type %d is not supported", typ)
+	}
+	return typ, size, offset, nil
+}
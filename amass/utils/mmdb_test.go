@@ -0,0 +1,287 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"bytes"
+	"net"
+	"testing"
+)
+
+// mmdbTestNode is a node of the search tree built by the test database writer.
+type mmdbTestNode struct {
+	child [2]*mmdbTestNode
+	// The data offset plus one for records that point to data
+	data [2]int
+}
+
+func (n *mmdbTestNode) insert(ip net.IP, prefix, offset int) {
+	node := n
+	for depth := 0; depth < prefix-1; depth++ {
+		bit := int(ip[depth/8]>>uint(7-depth%8)) & 1
+		if node.child[bit] == nil {
+			node.child[bit] = &mmdbTestNode{}
+		}
+		node = node.child[bit]
+	}
+	depth := prefix - 1
+	node.data[int(ip[depth/8]>>uint(7-depth%8))&1] = offset + 1
+}
+
+func (n *mmdbTestNode) find(ip net.IP, depth int) *mmdbTestNode {
+	node := n
+	for i := 0; i < depth; i++ {
+		bit := int(ip[i/8]>>uint(7-i%8)) & 1
+		if node.child[bit] == nil {
+			node.child[bit] = &mmdbTestNode{}
+		}
+		node = node.child[bit]
+	}
+	return node
+}
+
+func mmdbTestControl(typ, size int) []byte {
+	var extra []byte
+	// Sizes of 29 or more are stored in the following byte
+	if size >= 29 {
+		extra = []byte{byte(size - 29)}
+		size = 29
+	}
+
+	ctrl := []byte{byte(typ<<5 | size)}
+	if typ > 7 {
+		ctrl = []byte{byte(size), byte(typ - 7)}
+	}
+	return append(ctrl, extra...)
+}
+
+func mmdbTestString(s string) []byte {
+	return append(mmdbTestControl(mmdbString, len(s)), s...)
+}
+
+func mmdbTestUint(typ int, n uint32) []byte {
+	var b []byte
+	for ; n > 0; n >>= 8 {
+		b = append([]byte{byte(n)}, b...)
+	}
+	return append(mmdbTestControl(typ, len(b)), b...)
+}
+
+// buildTestMMDB creates an IPv6 database using 24 bit records that contains two IPv4
+// networks, one IPv6 network and an alias of the IPv4 space at ::ffff:0:0/96.
+func buildTestMMDB() []byte {
+	var data []byte
+
+	// The first record stores the organization string directly
+	first := len(data)
+	data = append(data, mmdbTestControl(mmdbMap, 2)...)
+	data = append(data, mmdbTestString("autonomous_system_number")...)
+	data = append(data, mmdbTestUint(mmdbUint32, 64500)...)
+	data = append(data, mmdbTestString("autonomous_system_organization")...)
+	org := len(data)
+	data = append(data, mmdbTestString("Example Net")...)
+
+	// The second record uses a pointer to the organization string
+	second := len(data)
+	data = append(data, mmdbTestControl(mmdbMap, 2)...)
+	data = append(data, mmdbTestString("autonomous_system_number")...)
+	data = append(data, mmdbTestUint(mmdbUint32, 64501)...)
+	data = append(data, mmdbTestString("autonomous_system_organization")...)
+	data = append(data, byte(mmdbPointer<<5|org>>8), byte(org))
+
+	third := len(data)
+	data = append(data, mmdbTestControl(mmdbMap, 2)...)
+	data = append(data, mmdbTestString("autonomous_system_number")...)
+	data = append(data, mmdbTestUint(mmdbUint32, 64502)...)
+	data = append(data, mmdbTestString("autonomous_system_organization")...)
+	data = append(data, mmdbTestString("Documentation")...)
+
+	root := &mmdbTestNode{}
+	root.insert(net.ParseIP("::192.0.2.0"), 96+24, first)
+	root.insert(net.ParseIP("::198.51.100.0"), 96+25, second)
+	root.insert(net.ParseIP("2001:db8::"), 32, third)
+	// Alias the IPv4-mapped addresses to the IPv4 subtree
+	ipv4 := root.find(net.ParseIP("::"), 96)
+	root.find(net.ParseIP("::ffff:0:0"), 95).child[1] = ipv4
+
+	// Number the nodes in breadth first order
+	var nodes []*mmdbTestNode
+	numbers := make(map[*mmdbTestNode]int)
+	for queue := []*mmdbTestNode{root}; len(queue) > 0; queue = queue[1:] {
+		n := queue[0]
+		if _, found := numbers[n]; found {
+			continue
+		}
+		numbers[n] = len(nodes)
+		nodes = append(nodes, n)
+		for _, c := range n.child {
+			if c != nil {
+				queue = append(queue, c)
+			}
+		}
+	}
+
+	var tree []byte
+	count := len(nodes)
+	for _, n := range nodes {
+		for bit := 0; bit < 2; bit++ {
+			value := count
+			if c := n.child[bit]; c != nil {
+				value = numbers[c]
+			} else if n.data[bit] > 0 {
+				value = count + mmdbDataSeparator + n.data[bit] - 1
+			}
+			tree = append(tree, byte(value>>16), byte(value>>8), byte(value))
+		}
+	}
+
+	var meta []byte
+	meta = append(meta, mmdbTestControl(mmdbMap, 4)...)
+	meta = append(meta, mmdbTestString("node_count")...)
+	meta = append(meta, mmdbTestUint(mmdbUint32, uint32(count))...)
+	meta = append(meta, mmdbTestString("record_size")...)
+	meta = append(meta, mmdbTestUint(mmdbUint16, 24)...)
+	meta = append(meta, mmdbTestString("ip_version")...)
+	meta = append(meta, mmdbTestUint(mmdbUint16, 6)...)
+	meta = append(meta, mmdbTestString("database_type")...)
+	meta = append(meta, mmdbTestString("Test-ASN")...)
+
+	var buf bytes.Buffer
+	buf.Write(tree)
+	buf.Write(make([]byte, mmdbDataSeparator))
+	buf.Write(data)
+	buf.Write(mmdbMetadataStart)
+	buf.Write(meta)
+	return buf.Bytes()
+}
+
+func TestMMDBLookup(t *testing.T) {
+	r, err := NewMMDBReader(buildTestMMDB())
+	if err != nil {
+		t.Fatalf("NewMMDBReader failed: %v", err)
+	}
+	if r.DatabaseType != "Test-ASN" || r.IPVersion != 6 {
+		t.Errorf("The metadata was not decoded properly: %v", r.Metadata)
+	}
+
+	tests := []struct {
+		addr string
+		asn  uint64
+		org  string
+		cidr string
+	}{
+		{"192.0.2.10", 64500, "Example Net", "192.0.2.0/24"},
+		{"198.51.100.100", 64501, "Example Net", "198.51.100.0/25"},
+		{"2001:db8::1", 64502, "Documentation", "2001:db8::/32"},
+		{"198.51.100.200", 0, "", "198.51.100.128/25"},
+	}
+
+	for _, test := range tests {
+		value, ipnet, err := r.Lookup(net.ParseIP(test.addr))
+		if err != nil {
+			t.Errorf("%s: Lookup failed: %v", test.addr, err)
+			continue
+		}
+		if ipnet == nil || ipnet.String() != test.cidr {
+			t.Errorf("%s: Lookup returned the network %v instead of %s", test.addr, ipnet, test.cidr)
+		}
+		if test.asn == 0 {
+			if value != nil {
+				t.Errorf("%s: Lookup returned data for an address without data: %v", test.addr, value)
+			}
+			continue
+		}
+
+		m, _ := value.(map[string]interface{})
+		if m["autonomous_system_number"] != test.asn || m["autonomous_system_organization"] != test.org {
+			t.Errorf("%s: Lookup returned the unexpected data %v", test.addr, value)
+		}
+	}
+}
+
+func TestMMDBNetworks(t *testing.T) {
+	r, err := NewMMDBReader(buildTestMMDB())
+	if err != nil {
+		t.Fatalf("NewMMDBReader failed: %v", err)
+	}
+
+	var networks []string
+	err = r.Networks(func(ipnet *net.IPNet, value interface{}) error {
+		networks = append(networks, ipnet.String())
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Networks failed: %v", err)
+	}
+
+	// The IPv4-mapped alias must not provide the IPv4 networks a second time
+	expected := "192.0.2.0/24 198.51.100.0/25 2001:db8::/32"
+	var got string
+	for i, n := range networks {
+		if i > 0 {
+			got += " "
+		}
+		got += n
+	}
+	if got != expected {
+		t.Errorf("Networks returned %s instead of %s", got, expected)
+	}
+}
+
+func TestIsMMDB(t *testing.T) {
+	if !IsMMDB(buildTestMMDB()) {
+		t.Errorf("IsMMDB did not detect the MaxMind DB")
+	}
+	if IsMMDB([]byte("1.0.0.0\t1.0.0.255\t13335\tUS\tCLOUDFLARENET\n")) {
+		t.Errorf("IsMMDB detected a TSV file as a MaxMind DB")
+	}
+}
+
+func TestMMDBPointerLoop(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		// A pointer to its own offset
+		{"self", []byte{mmdbPointer << 5, 0}},
+		// Two pointers that refer to each other
+		{"pair", []byte{mmdbPointer << 5, 2, mmdbPointer << 5, 0}},
+	}
+
+	for _, test := range tests {
+		d := &mmdbDecoder{buf: test.data}
+		if _, _, err := d.decode(0); err == nil {
+			t.Errorf("%s: decode did not return an error for the pointer loop", test.name)
+		}
+	}
+
+	// A pointer to another value is still followed
+	d := &mmdbDecoder{buf: append([]byte{mmdbPointer << 5, 2}, mmdbTestString("Example")...)}
+	if value, offset, err := d.decode(0); err != nil || value != "Example" || offset != 2 {
+		t.Errorf("decode returned %v, %d and %v for the pointer", value, offset, err)
+	}
+}
+
+func TestMMDBInvalidData(t *testing.T) {
+	// A map with a value that points back to the map
+	cycle := append([]byte{mmdbMap<<5 | 1}, mmdbTestString("a")...)
+	cycle = append(cycle, mmdbPointer<<5, 0)
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"cycle", cycle},
+		{"map size", append(mmdbTestControl(mmdbMap, 100), mmdbTestString("a")...)},
+		{"large map size", []byte{mmdbMap<<5 | 31, 0xff, 0xff, 0xff}},
+		{"array size", append(mmdbTestControl(mmdbArray, 40), 0, 0, 0)},
+	}
+
+	for _, test := range tests {
+		d := &mmdbDecoder{buf: test.data}
+		if _, _, err := d.decode(0); err == nil {
+			t.Errorf("%s: decode did not return an error for the invalid data", test.name)
+		}
+	}
+}
@@ -4,6 +4,7 @@
 package utils
 
 import (
+	"context"
 	"errors"
 	"io"
 	"io/ioutil"
@@ -28,6 +29,12 @@ const (
 // RequestWebPage returns a string containing the entire response for
 // the url parameter when successful.
 func RequestWebPage(url string, body io.Reader, hvals map[string]string, uid, secret string) (string, error) {
+	return RequestWebPageContext(context.Background(), url, body, hvals, uid, secret)
+}
+
+// RequestWebPageContext behaves like RequestWebPage, but the request
+// is cancelled when the provided context is done.
+func RequestWebPageContext(ctx context.Context, url string, body io.Reader, hvals map[string]string, uid, secret string) (string, error) {
 	method := "GET"
 	if body != nil {
 		method = "POST"
@@ -36,29 +43,13 @@ func RequestWebPage(url string, body io.Reader, hvals map[string]string, uid, se
 	if err != nil {
 		return "", err
 	}
+	req = req.WithContext(ctx)
 	if uid != "" && secret != "" {
 		req.SetBasicAuth(uid, secret)
 	}
-	req.Header.Set("User-Agent", UserAgent)
-	req.Header.Set("Accept", Accept)
-	req.Header.Set("Accept-Language", AcceptLang)
-	if hvals != nil {
-		for k, v := range hvals {
-			req.Header.Set(k, v)
-		}
-	}
+	SetHTTPRequestHeaders(req, hvals)
 
-	d := net.Dialer{}
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-		Transport: &http.Transport{
-			DialContext:           d.DialContext,
-			MaxIdleConns:          200,
-			IdleConnTimeout:       90 * time.Second,
-			TLSHandshakeTimeout:   10 * time.Second,
-			ExpectContinueTimeout: 5 * time.Second,
-		},
-	}
+	client := NewHTTPClient(30 * time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
 		return "", err
@@ -162,14 +153,38 @@ func addrDec(ip net.IP) {
 	}
 }
 
+// The prefix lengths of the subnets typically assigned to individual networks
+const (
+	IPv4SubnetPrefix = 24
+	IPv6SubnetPrefix = 64
+)
+
+// AddressSubnet returns the /24 or /64 subnet containing the IPv4 or IPv6 address.
+func AddressSubnet(ip net.IP) *net.IPNet {
+	if ip4 := ip.To4(); ip4 != nil {
+		mask := net.CIDRMask(IPv4SubnetPrefix, 8*net.IPv4len)
+		return &net.IPNet{IP: ip4.Mask(mask), Mask: mask}
+	} else if ip.To16() == nil {
+		return nil
+	}
+
+	mask := net.CIDRMask(IPv6SubnetPrefix, 8*net.IPv6len)
+	return &net.IPNet{IP: ip.Mask(mask), Mask: mask}
+}
+
 // CIDRSubset returns a subset of the IP addresses contained within
 // the cidr parameter with num elements around the addr element.
+// IPv6 subsets do not extend beyond the /64 subnet containing the address.
 func CIDRSubset(cidr *net.IPNet, addr string, num int) []net.IP {
 	first := net.ParseIP(addr)
+	if first == nil {
+		return nil
+	}
 
 	if !cidr.Contains(first) {
 		return []net.IP{first}
 	}
+	cidr = subsetPrefix(cidr, first)
 
 	offset := num / 2
 	// Get the first address
@@ -199,6 +214,14 @@ func CIDRSubset(cidr *net.IPNet, addr string, num int) []net.IP {
 	return RangeHosts(first, last)
 }
 
+// subsetPrefix returns the /64 subnet containing the IPv6 address when the netblock is larger.
+func subsetPrefix(cidr *net.IPNet, ip net.IP) *net.IPNet {
+	if ones, bits := cidr.Mask.Size(); bits != 8*net.IPv6len || ones >= IPv6SubnetPrefix {
+		return cidr
+	}
+	return AddressSubnet(ip)
+}
+
 // ReverseIP returns an IP address that is the ip parameter with the numbers reversed.
 func ReverseIP(ip string) string {
 	var reversed []string
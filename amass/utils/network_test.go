@@ -62,3 +62,40 @@ func TestAmassCIDRSubset(t *testing.T) {
 		t.Error("CIDRSubset returned an incorrect number of elements")
 	}
 }
+
+func TestAmassCIDRSubsetIPv6(t *testing.T) {
+	_, ipnet, _ := net.ParseCIDR("2001:db8::/32")
+
+	// The subset is centered on the address
+	subset := CIDRSubset(ipnet, "2001:db8:0:5::80", 16)
+	if len(subset) != 17 || subset[0].String() != "2001:db8:0:5::78" || subset[16].String() != "2001:db8:0:5::88" {
+		t.Errorf("CIDRSubset returned the unexpected addresses %v", subset)
+	}
+
+	// The subset does not cross into the neighboring /64 subnets
+	subset = CIDRSubset(ipnet, "2001:db8:0:5::2", 16)
+	if len(subset) != 11 || subset[0].String() != "2001:db8:0:5::" {
+		t.Errorf("CIDRSubset crossed the /64 boundary: %v", subset)
+	}
+
+	if subset := CIDRSubset(ipnet, "not-an-address", 16); subset != nil {
+		t.Errorf("CIDRSubset returned %v for an invalid address", subset)
+	}
+}
+
+func TestAddressSubnet(t *testing.T) {
+	tests := map[string]string{
+		"192.0.2.77":           "192.0.2.0/24",
+		"::ffff:192.0.2.77":    "192.0.2.0/24",
+		"2001:db8:0:5:1:2:3:4": "2001:db8:0:5::/64",
+	}
+
+	for addr, expected := range tests {
+		if subnet := AddressSubnet(net.ParseIP(addr)); subnet == nil || subnet.String() != expected {
+			t.Errorf("AddressSubnet(%s) returned %v instead of %s", addr, subnet, expected)
+		}
+	}
+	if subnet := AddressSubnet(nil); subnet != nil {
+		t.Errorf("AddressSubnet returned %v for an invalid address", subnet)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// StatusInterval is the time between updates of a progress status line.
+const StatusInterval = 2 * time.Second
+
+// StatusLine is a line of progress information that is kept at the bottom of a terminal.
+// The line is removed while other output is printed, so the two are not mixed together.
+type StatusLine struct {
+	sync.Mutex
+	out   io.Writer
+	shown bool
+}
+
+// NewStatusLine returns a StatusLine that is written to out, such as standard error.
+func NewStatusLine(out io.Writer) *StatusLine {
+	return &StatusLine{out: out}
+}
+
+// Show replaces the current status line with the line provided.
+func (sl *StatusLine) Show(line string) {
+	sl.Lock()
+	defer sl.Unlock()
+
+	fmt.Fprintf(sl.out, "\r\033[K%s", line)
+	sl.shown = true
+}
+
+// Clear removes the status line when it is shown.
+func (sl *StatusLine) Clear() {
+	sl.Lock()
+	defer sl.Unlock()
+
+	sl.remove()
+}
+
+// Print removes the status line while the function prints output.
+func (sl *StatusLine) Print(print func()) {
+	sl.Lock()
+	defer sl.Unlock()
+
+	sl.remove()
+	print()
+}
+
+func (sl *StatusLine) remove() {
+	if sl.shown {
+		fmt.Fprint(sl.out, "\r\033[K")
+		sl.shown = false
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestStatusLine(t *testing.T) {
+	var buf bytes.Buffer
+	sl := NewStatusLine(&buf)
+
+	// Nothing is removed before a status line has been shown
+	sl.Print(func() { buf.WriteString("first\n") })
+	sl.Show("50%")
+	sl.Print(func() { buf.WriteString("second\n") })
+	sl.Clear()
+
+	if got, want := buf.String(), "first\n\r\033[K50%\r\033[Ksecond\n"; got != want {
+		t.Errorf("The status line wrote %q instead of %q", got, want)
+	}
+}
@@ -15,6 +15,7 @@ const d3Template = `
     <meta charset="utf-8">
     <title>Amass Network Mapping</title>
     <script src="https://d3js.org/d3.v4.min.js"></script>
+    {{ if .GeoLayout }}<script src="https://unpkg.com/topojson-client@3"></script>{{ end }}
     <style>
         div#tooltip {
             position: absolute;        
@@ -41,7 +42,7 @@ const d3Template = `
 var graph = {
     nodes: [
     {{ range .Nodes }}
-        {id: {{.ID }}, num: {{ .Num }}, label: "{{ .Label }}", color: "{{ .Color }}" },
+        {id: {{.ID }}, num: {{ .Num }}, label: "{{ .Label }}", color: "{{ .Color }}"{{ if .Located }}, lat: {{ .Latitude }}, lon: {{ .Longitude }}{{ end }} },
     {{ end }}
     ],
     edges: [
@@ -139,6 +140,9 @@ function update() {
     ctx.translate(transform.x, transform.y);
     ctx.scale(transform.k, transform.k);
 
+    if (geoLayout) {
+        drawMap();
+    }
     graph.edges.forEach(drawEdge);
     graph.nodes.forEach(drawNode);
 
@@ -245,8 +249,56 @@ function dragged() {
 
 function dragended() {
     if (!d3.event.active) simulation.alphaTarget(0);
-    d3.event.subject.fx = null;
-    d3.event.subject.fy = null;
+    // Located addresses return to their position on the map
+    d3.event.subject.fx = geoLayout ? d3.event.subject.gx : null;
+    d3.event.subject.fy = geoLayout ? d3.event.subject.gy : null;
+}
+
+// The map layout fixes the located addresses at their position on the world map
+var geoLayout = {{ .GeoLayout }},
+    land,
+    projection,
+    geoPath;
+
+if (geoLayout) {
+    projection = d3.geoEquirectangular()
+        .fitSize([graphWidth, graphHeight], {type: "Sphere"});
+    geoPath = d3.geoPath(projection, ctx);
+
+    graph.nodes.forEach(function(n) {
+        if (n.lat !== undefined) {
+            var p = projection([n.lon, n.lat]);
+
+            n.gx = n.fx = p[0];
+            n.gy = n.fy = p[1];
+        }
+    });
+
+    simulation.force("center", null);
+    simulation.alpha(1).restart();
+
+    d3.json("https://unpkg.com/world-atlas@1/world/110m.json", function(error, world) {
+        if (!error) {
+            land = topojson.feature(world, world.objects.land);
+            update();
+        }
+    });
+}
+
+function drawMap() {
+    ctx.beginPath();
+    geoPath(d3.geoGraticule10());
+    ctx.strokeStyle = "#eee";
+    ctx.stroke();
+
+    if (land) {
+        ctx.beginPath();
+        geoPath(land);
+        ctx.fillStyle = "#f4f4f4";
+        ctx.fill();
+        ctx.strokeStyle = "#ccc";
+        ctx.stroke();
+    }
 }
 
 update();
@@ -263,33 +315,51 @@ type d3Edge struct {
 }
 
 type d3Node struct {
-	ID    int
-	Num   int
-	Label string
-	Color string
+	ID        int
+	Num       int
+	Label     string
+	Color     string
+	Located   bool
+	Latitude  float64
+	Longitude float64
 }
 
 type d3Graph struct {
-	Name   string
-	MaxNum int
-	Nodes  []d3Node
-	Edges  []d3Edge
+	Name      string
+	MaxNum    int
+	GeoLayout bool
+	Nodes     []d3Node
+	Edges     []d3Edge
 }
 
 // WriteD3Data generates a HTML file that displays the Amass graph using D3.
 func WriteD3Data(output io.Writer, nodes []Node, edges []Edge) {
+	writeD3Data(output, nodes, edges, false)
+}
+
+// WriteD3MapData generates a HTML file that displays the Amass graph using D3,
+// with the addresses that have a geolocation placed on a world map.
+func WriteD3MapData(output io.Writer, nodes []Node, edges []Edge) {
+	writeD3Data(output, nodes, edges, true)
+}
+
+func writeD3Data(output io.Writer, nodes []Node, edges []Edge, geo bool) {
 	colors := map[string]string{
-		"Subdomain": "green",
-		"Domain":    "red",
-		"IPAddress": "orange",
-		"PTR":       "yellow",
-		"NS":        "cyan",
-		"MX":        "purple",
-		"Netblock":  "pink",
-		"AS":        "blue",
+		"Subdomain":   "green",
+		"Domain":      "red",
+		"IPAddress":   "orange",
+		"PTR":         "yellow",
+		"NS":          "cyan",
+		"MX":          "purple",
+		"Netblock":    "pink",
+		"AS":          "blue",
+		"Certificate": "brown",
 	}
 
-	graph := &d3Graph{Name: "Amass Network Mapping"}
+	graph := &d3Graph{
+		Name:      "Amass Network Mapping",
+		GeoLayout: geo,
+	}
 
 	for idx, node := range nodes {
 		label := node.Title
@@ -297,11 +367,17 @@ func WriteD3Data(output io.Writer, nodes []Node, edges []Edge) {
 			label += ", Source: " + node.Source
 		}
 
-		graph.Nodes = append(graph.Nodes, d3Node{
+		n := d3Node{
 			ID:    idx,
 			Label: label,
 			Color: colors[node.Type],
-		})
+		}
+		if l := node.Location; l != nil && l.Located {
+			n.Located = true
+			n.Latitude = l.Latitude
+			n.Longitude = l.Longitude
+		}
+		graph.Nodes = append(graph.Nodes, n)
 	}
 
 	for _, edge := range edges {
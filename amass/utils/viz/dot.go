@@ -12,7 +12,7 @@ import (
 const dotTemplate = `
 digraph {{ .Name }} {
 {{ range .Nodes }}
-        node [label="{{ .Label }}",color="{{ .Color }}",type="{{ .Type }}",source="{{ .Source }}"]; n{{ .ID }};
+        node [label="{{ .Label }}",color="{{ .Color }}",type="{{ .Type }}",source="{{ .Source }}"{{ if .Location }},location="{{ .Location }}"{{ end }}{{ if .Located }},latitude="{{ .Latitude }}",longitude="{{ .Longitude }}"{{ end }}]; n{{ .ID }};
 {{ end }}
 
 {{ range .Edges }}
@@ -28,11 +28,15 @@ type dotEdge struct {
 }
 
 type dotNode struct {
-	ID     string
-	Label  string
-	Color  string
-	Type   string
-	Source string
+	ID        string
+	Label     string
+	Color     string
+	Type      string
+	Source    string
+	Location  string
+	Located   bool
+	Latitude  float64
+	Longitude float64
 }
 
 type dotGraph struct {
@@ -44,26 +48,34 @@ type dotGraph struct {
 // WriteDOTData generates a DOT file to display the Amass graph.
 func WriteDOTData(output io.Writer, nodes []Node, edges []Edge) {
 	colors := map[string]string{
-		"Subdomain": "green",
-		"Domain":    "red",
-		"IPAddress": "orange",
-		"PTR":       "yellow",
-		"NS":        "cyan",
-		"MX":        "purple",
-		"Netblock":  "pink",
-		"AS":        "blue",
+		"Subdomain":   "green",
+		"Domain":      "red",
+		"IPAddress":   "orange",
+		"PTR":         "yellow",
+		"NS":          "cyan",
+		"MX":          "purple",
+		"Netblock":    "pink",
+		"AS":          "blue",
+		"Certificate": "brown",
 	}
 
 	graph := &dotGraph{Name: "Amass Network Mapping"}
 
 	for idx, node := range nodes {
-		graph.Nodes = append(graph.Nodes, dotNode{
+		n := dotNode{
 			ID:     strconv.Itoa(idx + 1),
 			Label:  node.Label,
 			Color:  colors[node.Type],
 			Type:   node.Type,
 			Source: node.Source,
-		})
+		}
+		if l := node.Location; l != nil {
+			n.Location = l.String()
+			n.Located = l.Located
+			n.Latitude = l.Latitude
+			n.Longitude = l.Longitude
+		}
+		graph.Nodes = append(graph.Nodes, n)
 	}
 
 	for _, edge := range edges {
@@ -103,6 +103,7 @@ var (
 	gexfPurple = &gexfColor{R: 142, G: 68, B: 173}
 	gexfPink   = &gexfColor{R: 243, G: 26, B: 188}
 	gexfBlue   = &gexfColor{R: 26, G: 69, B: 243}
+	gexfBrown  = &gexfColor{R: 160, G: 82, B: 45}
 )
 
 // WriteGEXFData generates a GEXF file to display the Amass graph using Gephi.
@@ -133,6 +134,10 @@ func WriteGEXFData(output io.Writer, nodes []Node, edges []Edge) {
 					{ID: "0", Title: "Title", Type: "string"},
 					{ID: "1", Title: "Source", Type: "string"},
 					{ID: "2", Title: "Type", Type: "string"},
+					{ID: "3", Title: "Country", Type: "string"},
+					{ID: "4", Title: "City", Type: "string"},
+					{ID: "5", Title: "Latitude", Type: "double"},
+					{ID: "6", Title: "Longitude", Type: "double"},
 				},
 			},
 		},
@@ -158,16 +163,32 @@ func WriteGEXFData(output io.Writer, nodes []Node, edges []Edge) {
 			color = gexfPink
 		case "AS":
 			color = gexfBlue
+		case "Certificate":
+			color = gexfBrown
+		}
+
+		attrs := []gexfAttrValue{
+			{For: "0", Value: n.Title},
+			{For: "1", Value: n.Source},
+			{For: "2", Value: n.Type},
+		}
+		if l := n.Location; l != nil {
+			attrs = append(attrs,
+				gexfAttrValue{For: "3", Value: l.Country},
+				gexfAttrValue{For: "4", Value: l.City},
+			)
+			if l.Located {
+				attrs = append(attrs,
+					gexfAttrValue{For: "5", Value: strconv.FormatFloat(l.Latitude, 'f', -1, 64)},
+					gexfAttrValue{For: "6", Value: strconv.FormatFloat(l.Longitude, 'f', -1, 64)},
+				)
+			}
 		}
 
 		doc.Graph.Nodes = append(doc.Graph.Nodes, gexfNode{
 			ID:    strconv.Itoa(idx),
 			Label: n.Label,
-			Attrs: []gexfAttrValue{
-				{For: "0", Value: n.Title},
-				{For: "1", Value: n.Source},
-				{For: "2", Value: n.Type},
-			},
+			Attrs: attrs,
 			Color: color,
 		})
 	}
@@ -23,12 +23,16 @@ type graphistryEdges struct {
 }
 
 type graphistryNodes struct {
-	NodeID string `json:"node"`
-	Label  string `json:"pointLabel"`
-	Title  string `json:"pointTitle"`
-	Color  int    `json:"pointColor"`
-	Type   string `json:"type"`
-	Source string `json:"source"`
+	NodeID    string  `json:"node"`
+	Label     string  `json:"pointLabel"`
+	Title     string  `json:"pointTitle"`
+	Color     int     `json:"pointColor"`
+	Type      string  `json:"type"`
+	Source    string  `json:"source"`
+	Country   string  `json:"country,omitempty"`
+	City      string  `json:"city,omitempty"`
+	Latitude  float64 `json:"latitude,omitempty"`
+	Longitude float64 `json:"longitude,omitempty"`
 }
 
 type graphistryREST struct {
@@ -42,14 +46,15 @@ type graphistryREST struct {
 // WriteGraphistryData generates a JSON file to display the Amass graph using Graphistry.
 func WriteGraphistryData(output io.Writer, nodes []Node, edges []Edge) {
 	colors := map[string]int{
-		"Subdomain": 3,
-		"Domain":    5,
-		"IPAddress": 7,
-		"PTR":       10,
-		"NS":        0,
-		"MX":        9,
-		"Netblock":  4,
-		"AS":        1,
+		"Subdomain":   3,
+		"Domain":      5,
+		"IPAddress":   7,
+		"PTR":         10,
+		"NS":          0,
+		"MX":          9,
+		"Netblock":    4,
+		"AS":          1,
+		"Certificate": 2,
 	}
 	name := "Amass_" + time.Now().Format("Jan_2_2006_15_04_05")
 	restJSON := &graphistryREST{
@@ -63,14 +68,23 @@ func WriteGraphistryData(output io.Writer, nodes []Node, edges []Edge) {
 	}
 
 	for idx, node := range nodes {
-		restJSON.Nodes = append(restJSON.Nodes, graphistryNodes{
+		n := graphistryNodes{
 			NodeID: strconv.Itoa(idx),
 			Label:  node.Label,
 			Title:  node.Title,
 			Color:  colors[node.Type],
 			Type:   node.Type,
 			Source: node.Source,
-		})
+		}
+		if l := node.Location; l != nil {
+			n.Country = l.Country
+			n.City = l.City
+			if l.Located {
+				n.Latitude = l.Latitude
+				n.Longitude = l.Longitude
+			}
+		}
+		restJSON.Nodes = append(restJSON.Nodes, n)
 	}
 
 	for _, edge := range edges {
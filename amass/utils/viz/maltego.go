@@ -26,6 +26,7 @@ func WriteMaltegoData(output io.Writer, nodes []Node, edges []Edge) {
 		"maltego.AS",
 		"maltego.Company",
 		"maltego.DNSName",
+		"maltego.Location",
 	}
 	// Print the column types in the first row
 	fmt.Fprintln(output, strings.Join(types, ","))
@@ -69,12 +70,14 @@ func typeToIndex(t string) int {
 		idx = 6
 	case "Company":
 		idx = 7
+	case "Location":
+		idx = 9
 	}
 	return idx
 }
 
 func writeMaltegoTableLine(out io.Writer, data1, type1, data2, type2 string) {
-	row := []string{"", "", "", "", "", "", "", "", ""}
+	row := []string{"", "", "", "", "", "", "", "", "", ""}
 
 	idx1 := typeToIndex(type1)
 	row[idx1] = data1
@@ -108,6 +111,10 @@ func traverseTree(out io.Writer, id int, nodes []Node, edges []Edge, filter map[
 		company := strings.Replace(strings.TrimSpace(parts[2]), ",", "", -1)
 		writeMaltegoTableLine(out, d1, t1, company, "Company")
 	}
+	// Print the line containing the location of the address
+	if l := nodes[id].Location; t1 == "IPAddress" && l != nil {
+		writeMaltegoTableLine(out, d1, t1, strings.Replace(l.String(), ",", "", -1), "Location")
+	}
 
 	for _, edge := range edges {
 		subFrom := from
@@ -121,6 +128,10 @@ func traverseTree(out io.Writer, id int, nodes []Node, edges []Edge, filter map[
 		}
 		d2 := nodes[n].Label
 		t2 := nodes[n].Type
+		// Certificates do not have a column within the table
+		if t2 == "Certificate" {
+			continue
+		}
 		// Need to properly handle CNAME records
 		if strings.Contains(edge.Title, "CNAME") {
 			if subFrom {
@@ -85,10 +85,33 @@ const htmlStart string = `<!doctype html>
   };
 `
 
-const htmlEnd string = `
+const htmlNetwork string = `
     var data = {nodes: nodes, edges: edges};
 
     network = new vis.Network(container, data, options);
+`
+
+// The lines of latitude and longitude drawn behind the graph when using the map layout
+const htmlMap string = `
+    network.on("beforeDrawing", function(ctx) {
+      ctx.strokeStyle = 'rgb(229,232,232)';
+      ctx.lineWidth = 1;
+      for (var lon = -180; lon <= 180; lon += 30) {
+        ctx.beginPath();
+        ctx.moveTo(lon * scale, -90 * scale);
+        ctx.lineTo(lon * scale, 90 * scale);
+        ctx.stroke();
+      }
+      for (var lat = -90; lat <= 90; lat += 30) {
+        ctx.beginPath();
+        ctx.moveTo(-180 * scale, lat * scale);
+        ctx.lineTo(180 * scale, lat * scale);
+        ctx.stroke();
+      }
+    });
+`
+
+const htmlEnd string = `
   }
 
   redrawAll()
@@ -99,8 +122,21 @@ const htmlEnd string = `
 </html>
 `
 
+// The number of canvas pixels for each degree of latitude and longitude in the map layout
+const visjsMapScale = 10
+
 // WriteVisjsData generates a HTML file that displays the Amass graph using Visjs.
 func WriteVisjsData(output io.Writer, nodes []Node, edges []Edge) {
+	writeVisjsData(output, nodes, edges, false)
+}
+
+// WriteVisjsMapData generates a HTML file that displays the Amass graph using Visjs,
+// with the addresses that have a geolocation fixed at their position on a world map.
+func WriteVisjsMapData(output io.Writer, nodes []Node, edges []Edge) {
+	writeVisjsData(output, nodes, edges, true)
+}
+
+func writeVisjsData(output io.Writer, nodes []Node, edges []Edge, geo bool) {
 	bufwr := bufio.NewWriter(output)
 
 	bufwr.WriteString(htmlStart)
@@ -119,7 +155,7 @@ func WriteVisjsData(output io.Writer, nodes []Node, edges []Edge) {
 				", Source: " + node.Source + "', color: {background: 'red'}},\n"
 		case "IPAddress":
 			nStr += "{id: " + idxStr + ", title: '" + node.Title +
-				"', color: {background: 'orange'}},\n"
+				"', color: {background: 'orange'}" + visjsMapPosition(node, geo) + "},\n"
 		case "PTR":
 			nStr += "{id: " + idxStr + ", title: '" + node.Title +
 				"', color: {background: 'yellow'}},\n"
@@ -135,6 +171,9 @@ func WriteVisjsData(output io.Writer, nodes []Node, edges []Edge) {
 		case "AS":
 			nStr += "{id: " + idxStr + ", title: '" + node.Title +
 				"', color: {background: 'blue'}},\n"
+		case "Certificate":
+			nStr += "{id: " + idxStr + ", title: '" + node.Title +
+				", Source: " + node.Source + "', color: {background: 'brown'}},\n"
 		}
 
 	}
@@ -152,6 +191,23 @@ func WriteVisjsData(output io.Writer, nodes []Node, edges []Edge) {
 	bufwr.WriteString(eStr)
 	bufwr.Flush()
 
+	bufwr.WriteString(htmlNetwork)
+	if geo {
+		bufwr.WriteString("    var scale = " + strconv.Itoa(visjsMapScale) + ";\n")
+		bufwr.WriteString(htmlMap)
+	}
 	bufwr.WriteString(htmlEnd)
 	bufwr.Flush()
 }
+
+// visjsMapPosition returns the properties that fix a located node on the map.
+func visjsMapPosition(node Node, geo bool) string {
+	if !geo || node.Location == nil || !node.Location.Located {
+		return ""
+	}
+
+	x := strconv.FormatFloat(node.Location.Longitude*visjsMapScale, 'f', 2, 64)
+	// The canvas y axis increases towards the south
+	y := strconv.FormatFloat(-node.Location.Latitude*visjsMapScale, 'f', 2, 64)
+	return ", x: " + x + ", y: " + y + ", fixed: true"
+}
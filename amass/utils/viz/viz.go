@@ -17,4 +17,26 @@ type Node struct {
 	Label  string
 	Title  string
 	Source string
+	// The geolocation of IPAddress nodes, or nil when unknown
+	Location *Location
+}
+
+// Location is the geolocation of an Amass graph node.
+type Location struct {
+	Country string
+	City    string
+	// Located is false when the coordinates are not known
+	Located   bool
+	Latitude  float64
+	Longitude float64
+}
+
+// String returns the city and country of the location.
+func (l *Location) String() string {
+	if l.City == "" {
+		return l.Country
+	} else if l.Country == "" {
+		return l.City
+	}
+	return l.City + ", " + l.Country
 }
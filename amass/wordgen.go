@@ -0,0 +1,466 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/OWASP/Amass/amass/core"
+)
+
+// The built-in character sets available within masks
+var maskCharsets = map[rune]string{
+	'l': "abcdefghijklmnopqrstuvwxyz",
+	'd': "0123456789",
+	'h': "0123456789abcdef",
+	'a': "abcdefghijklmnopqrstuvwxyz0123456789",
+	's': "-",
+}
+
+// WordGenerator is the interface for the objects that produce labels during brute forcing.
+type WordGenerator interface {
+	fmt.Stringer
+
+	// Returns the number of words that will be produced by the generator
+	Count() uint64
+
+	// Calls the function with each word until all have been produced or false is returned
+	Each(fn func(word string) bool)
+}
+
+// WordlistGenerator produces the words within a wordlist.
+type WordlistGenerator struct {
+	Words []string
+}
+
+// NewWordlistGenerator returns a WordlistGenerator for the provided words.
+func NewWordlistGenerator(words []string) *WordlistGenerator {
+	return &WordlistGenerator{Words: words}
+}
+
+// String implements the WordGenerator interface.
+func (wg *WordlistGenerator) String() string {
+	return "Wordlist"
+}
+
+// Count implements the WordGenerator interface.
+func (wg *WordlistGenerator) Count() uint64 {
+	return uint64(len(wg.Words))
+}
+
+// Each implements the WordGenerator interface.
+func (wg *WordlistGenerator) Each(fn func(word string) bool) {
+	for _, word := range wg.Words {
+		if !fn(word) {
+			return
+		}
+	}
+}
+
+// FileWordGenerator streams the words within one or more wordlist files, which can be
+// gzip compressed. Empty lines and lines starting with # are ignored, and duplicate
+// words across all the files are only produced once.
+type FileWordGenerator struct {
+	sync.Mutex
+	Paths []string
+	once  sync.Once
+	count uint64
+	err   error
+}
+
+// NewFileWordGenerator returns a FileWordGenerator for the wordlist files
+// after checking that each of the files can be read.
+func NewFileWordGenerator(paths []string) (*FileWordGenerator, error) {
+	for _, path := range paths {
+		f, err := openWordlist(path)
+		if err != nil {
+			return nil, err
+		}
+		f.Close()
+	}
+	return &FileWordGenerator{Paths: paths}, nil
+}
+
+// String implements the WordGenerator interface.
+func (fg *FileWordGenerator) String() string {
+	if err := fg.Err(); err != nil {
+		return "Wordlist files (" + err.Error() + ")"
+	}
+	return "Wordlist files"
+}
+
+// Err returns the first error that occurred while reading the wordlist files.
+func (fg *FileWordGenerator) Err() error {
+	fg.Lock()
+	defer fg.Unlock()
+
+	return fg.err
+}
+
+func (fg *FileWordGenerator) setErr(err error) {
+	fg.Lock()
+	defer fg.Unlock()
+
+	if fg.err == nil {
+		fg.err = err
+	}
+}
+
+// Count implements the WordGenerator interface. The files are only read once to count the words.
+func (fg *FileWordGenerator) Count() uint64 {
+	fg.once.Do(func() {
+		fg.Each(func(word string) bool {
+			fg.count++
+			return true
+		})
+	})
+	return fg.count
+}
+
+// Each implements the WordGenerator interface.
+func (fg *FileWordGenerator) Each(fn func(word string) bool) {
+	// Only the hashes of the words are kept in memory to detect duplicates
+	seen := make(map[uint64]struct{})
+
+	for _, path := range fg.Paths {
+		more, err := eachWordInFile(path, seen, fn)
+		if err != nil {
+			fg.setErr(err)
+		}
+		if !more {
+			return
+		}
+	}
+}
+
+// eachWordInFile returns false when fn stopped the words from being produced.
+func eachWordInFile(path string, seen map[uint64]struct{}, fn func(word string) bool) (bool, error) {
+	f, err := openWordlist(path)
+	if err != nil {
+		return true, err
+	}
+	defer f.Close()
+
+	h := fnv.New64a()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		word := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+
+		h.Reset()
+		h.Write([]byte(word))
+		sum := h.Sum64()
+		if _, found := seen[sum]; found {
+			continue
+		}
+		seen[sum] = struct{}{}
+
+		if !fn(word) {
+			return false, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return true, fmt.Errorf("Failed to read the wordlist file %s: %v", path, err)
+	}
+	return true, nil
+}
+
+type wordlistFile struct {
+	io.Reader
+	file *os.File
+	gz   *gzip.Reader
+}
+
+func (wf *wordlistFile) Close() error {
+	if wf.gz != nil {
+		wf.gz.Close()
+	}
+	return wf.file.Close()
+}
+
+// openWordlist returns a reader for the wordlist file that decompresses gzip files.
+func openWordlist(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open the wordlist file %s: %v", path, err)
+	}
+
+	br := bufio.NewReader(f)
+	wf := &wordlistFile{Reader: br, file: f}
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		wf.gz, err = gzip.NewReader(br)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("Failed to decompress the wordlist file %s: %v", path, err)
+		}
+		wf.Reader = wf.gz
+	}
+	return wf, nil
+}
+
+// MaskGenerator produces all the words described by a hashcat style mask.
+// The mask can reference the built-in character sets ?l (lowercase letters),
+// ?d (digits), ?h (lowercase hex), ?a (letters and digits) and ?s (hyphen),
+// the custom character sets ?1 through ?4, and ?? for a literal question mark.
+type MaskGenerator struct {
+	Mask     string
+	charsets []string
+}
+
+// NewMaskGenerator returns a MaskGenerator for the mask using the custom character sets provided.
+// Custom character sets can reference the built-in character sets.
+func NewMaskGenerator(mask string, custom []string) (*MaskGenerator, error) {
+	if len(custom) > 4 {
+		return nil, fmt.Errorf("Only four custom character sets can be defined")
+	}
+
+	var customSets []string
+	for _, cs := range custom {
+		set, err := expandCharset(cs)
+		if err != nil {
+			return nil, err
+		}
+		customSets = append(customSets, set)
+	}
+
+	mg := &MaskGenerator{Mask: mask}
+	runes := []rune(strings.ToLower(mask))
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '?' {
+			mg.charsets = append(mg.charsets, string(runes[i]))
+			continue
+		}
+		if i+1 >= len(runes) {
+			return nil, fmt.Errorf("The mask %s ends with an incomplete placeholder", mask)
+		}
+
+		i++
+		c := runes[i]
+		switch {
+		case c == '?':
+			mg.charsets = append(mg.charsets, "?")
+		case c >= '1' && c <= '4':
+			idx := int(c - '1')
+			if idx >= len(customSets) {
+				return nil, fmt.Errorf("The mask %s references the undefined character set ?%c", mask, c)
+			}
+			mg.charsets = append(mg.charsets, customSets[idx])
+		default:
+			set, found := maskCharsets[c]
+			if !found {
+				return nil, fmt.Errorf("The mask %s references the unknown character set ?%c", mask, c)
+			}
+			mg.charsets = append(mg.charsets, set)
+		}
+	}
+
+	if len(mg.charsets) == 0 || len(mg.charsets) > 63 {
+		return nil, fmt.Errorf("The mask %s does not describe a valid DNS label length", mask)
+	}
+	return mg, nil
+}
+
+func expandCharset(cs string) (string, error) {
+	var set []rune
+
+	runes := []rune(strings.ToLower(cs))
+	for i := 0; i < len(runes); i++ {
+		add := string(runes[i])
+
+		if runes[i] == '?' && i+1 < len(runes) {
+			i++
+			if runes[i] == '?' {
+				add = "?"
+			} else if s, found := maskCharsets[runes[i]]; found {
+				add = s
+			} else {
+				return "", fmt.Errorf("The character set %s references the unknown character set ?%c", cs, runes[i])
+			}
+		}
+
+		for _, c := range add {
+			if !strings.ContainsRune(string(set), c) {
+				set = append(set, c)
+			}
+		}
+	}
+
+	if len(set) == 0 {
+		return "", fmt.Errorf("The character set %s is empty", cs)
+	}
+	return string(set), nil
+}
+
+// String implements the WordGenerator interface.
+func (mg *MaskGenerator) String() string {
+	return "Mask " + mg.Mask
+}
+
+// Count implements the WordGenerator interface.
+func (mg *MaskGenerator) Count() uint64 {
+	count := uint64(1)
+
+	for _, set := range mg.charsets {
+		size := uint64(len([]rune(set)))
+		// Saturate instead of overflowing
+		if count > math.MaxUint64/size {
+			return math.MaxUint64
+		}
+		count *= size
+	}
+	return count
+}
+
+// Each implements the WordGenerator interface.
+func (mg *MaskGenerator) Each(fn func(word string) bool) {
+	sets := make([][]rune, len(mg.charsets))
+	for i, set := range mg.charsets {
+		sets[i] = []rune(set)
+	}
+
+	idx := make([]int, len(sets))
+	word := make([]rune, len(sets))
+	for {
+		for i, set := range sets {
+			word[i] = set[idx[i]]
+		}
+		if !fn(string(word)) {
+			return
+		}
+		// Increment the indices starting from the last position
+		pos := len(idx) - 1
+		for ; pos >= 0; pos-- {
+			idx[pos]++
+			if idx[pos] < len(sets[pos]) {
+				break
+			}
+			idx[pos] = 0
+		}
+		if pos < 0 {
+			return
+		}
+	}
+}
+
+// CombinatorGenerator produces the words created by joining each word
+// produced by the left generator with each word in the right list.
+type CombinatorGenerator struct {
+	Left      WordGenerator
+	Right     []string
+	Separator string
+}
+
+// NewCombinatorGenerator returns a CombinatorGenerator that produces words such as word1-word2.
+func NewCombinatorGenerator(left WordGenerator, right []string) *CombinatorGenerator {
+	return &CombinatorGenerator{
+		Left:      left,
+		Right:     right,
+		Separator: "-",
+	}
+}
+
+// String implements the WordGenerator interface.
+func (cg *CombinatorGenerator) String() string {
+	return "Combinator"
+}
+
+// Count implements the WordGenerator interface.
+func (cg *CombinatorGenerator) Count() uint64 {
+	left, right := cg.Left.Count(), uint64(len(cg.Right))
+	// Saturate instead of overflowing
+	if right > 0 && left > math.MaxUint64/right {
+		return math.MaxUint64
+	}
+	return left * right
+}
+
+// Each implements the WordGenerator interface.
+func (cg *CombinatorGenerator) Each(fn func(word string) bool) {
+	cg.Left.Each(func(l string) bool {
+		for _, r := range cg.Right {
+			if !fn(l + cg.Separator + r) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// wordCount returns the number of words produced by all the generators, using the counts provided.
+func wordCount(gens []WordGenerator, counts map[WordGenerator]uint64) uint64 {
+	var total uint64
+
+	for _, gen := range gens {
+		c := counts[gen]
+		// Saturate instead of overflowing
+		if total > math.MaxUint64-c {
+			return math.MaxUint64
+		}
+		total += c
+	}
+	return total
+}
+
+// BruteForceGenerators returns the WordGenerators described by the enumeration configuration.
+// The default wordlist is used when no words were provided, unless only masks were requested.
+// Combinations are created using the Wordlist words, or the wordlist files when no words are set.
+func BruteForceGenerators(config *core.AmassConfig) ([]WordGenerator, error) {
+	var gens []WordGenerator
+	var words WordGenerator
+
+	if len(config.Wordlist) > 0 {
+		words = NewWordlistGenerator(config.Wordlist)
+		gens = append(gens, words)
+	}
+	if len(config.WordlistFiles) > 0 {
+		fg, err := NewFileWordGenerator(config.WordlistFiles)
+		if err != nil {
+			return nil, err
+		}
+		gens = append(gens, fg)
+		if words == nil {
+			words = fg
+		}
+	}
+	if words == nil && (len(config.BruteMasks) == 0 || len(config.CombinatorWordlist) > 0) {
+		words = NewWordlistGenerator(defaultWordlist)
+		gens = append(gens, words)
+	}
+
+	for _, mask := range config.BruteMasks {
+		mg, err := NewMaskGenerator(mask, config.MaskCharsets)
+		if err != nil {
+			return nil, err
+		}
+		gens = append(gens, mg)
+	}
+	if words != nil && len(config.CombinatorWordlist) > 0 {
+		gens = append(gens, NewCombinatorGenerator(words, config.CombinatorWordlist))
+	}
+	return gens, nil
+}
+
+// RecursiveGenerators returns a WordGenerator for each level of recursive brute forcing.
+func RecursiveGenerators(config *core.AmassConfig) ([]WordGenerator, error) {
+	var levels []WordGenerator
+
+	for _, path := range config.RecursiveWordlistFiles {
+		fg, err := NewFileWordGenerator([]string{path})
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels, fg)
+	}
+	return levels, nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package amass
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMaskGenerator(t *testing.T) {
+	mg, err := NewMaskGenerator("web?d?1", []string{"ab"})
+	if err != nil {
+		t.Fatalf("NewMaskGenerator failed: %v", err)
+	}
+	if c := mg.Count(); c != 20 {
+		t.Errorf("Count returned %d instead of 20", c)
+	}
+
+	var words []string
+	mg.Each(func(word string) bool {
+		words = append(words, word)
+		return true
+	})
+	if uint64(len(words)) != mg.Count() {
+		t.Errorf("Each produced %d words instead of %d", len(words), mg.Count())
+	}
+	if words[0] != "web0a" || words[len(words)-1] != "web9b" {
+		t.Errorf("Each produced the unexpected words %s and %s", words[0], words[len(words)-1])
+	}
+
+	for _, mask := range []string{"web?", "?x", "?1"} {
+		if _, err := NewMaskGenerator(mask, nil); err == nil {
+			t.Errorf("NewMaskGenerator did not return an error for %s", mask)
+		}
+	}
+}
+
+func TestCombinatorGenerator(t *testing.T) {
+	cg := NewCombinatorGenerator(NewWordlistGenerator([]string{"api", "web"}), []string{"dev", "prod", "qa"})
+	if c := cg.Count(); c != 6 {
+		t.Errorf("Count returned %d instead of 6", c)
+	}
+
+	var words []string
+	cg.Each(func(word string) bool {
+		words = append(words, word)
+		return len(words) < 2
+	})
+	if len(words) != 2 || words[1] != "api-prod" {
+		t.Errorf("Each did not stop when requested or produced unexpected words: %v", words)
+	}
+}
+
+func TestFileWordGenerator(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wordlists")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	plain := filepath.Join(dir, "plain.txt")
+	if err := ioutil.WriteFile(plain, []byte("# comment\nwww\n\nmail\nWWW\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The compressed file is detected without relying on the extension
+	compressed := filepath.Join(dir, "compressed.lst")
+	f, err := os.Create(compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	gz.Write([]byte("mail\nvpn\n"))
+	gz.Close()
+	f.Close()
+
+	fg, err := NewFileWordGenerator([]string{plain, compressed})
+	if err != nil {
+		t.Fatalf("NewFileWordGenerator failed: %v", err)
+	}
+
+	var words []string
+	fg.Each(func(word string) bool {
+		words = append(words, word)
+		return true
+	})
+	if got := strings.Join(words, ","); got != "www,mail,vpn" {
+		t.Errorf("Each produced %s instead of www,mail,vpn", got)
+	}
+	if c := fg.Count(); c != 3 {
+		t.Errorf("Count returned %d instead of 3", c)
+	}
+
+	if _, err := NewFileWordGenerator([]string{filepath.Join(dir, "missing.txt")}); err == nil {
+		t.Errorf("NewFileWordGenerator did not return an error for a missing file")
+	}
+	if err := fg.Err(); err != nil {
+		t.Errorf("Err returned %v after the files were read", err)
+	}
+
+	// Lines longer than the scanner buffer cause the file to fail while being read
+	long := filepath.Join(dir, "long.txt")
+	if err := ioutil.WriteFile(long, []byte("api\n"+strings.Repeat("a", 70000)+"\ndev\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fg, err = NewFileWordGenerator([]string{long, plain})
+	if err != nil {
+		t.Fatalf("NewFileWordGenerator failed: %v", err)
+	}
+	if c := fg.Count(); c != 3 {
+		t.Errorf("Count returned %d instead of the 3 words read before the failure and from the other file", c)
+	}
+	if fg.Err() == nil || !strings.Contains(fg.String(), long) {
+		t.Errorf("The generator did not report the failure: %s", fg)
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/OWASP/Amass/amass"
+	"github.com/OWASP/Amass/amass/core"
+	"github.com/OWASP/Amass/amass/dnssrv"
+	"github.com/OWASP/Amass/amass/utils"
+)
+
+// The status line that displays the progress on standard error
+var status = utils.NewStatusLine(os.Stderr)
+
+// printLine prints the line on standard output, after removing the status line when shown.
+func printLine(line string) {
+	status.Print(func() {
+		fmt.Println(line)
+	})
+}
+
+// hostRecord is the information discovered on an address in scope.
+type hostRecord struct {
+	IP          string   `json:"ip"`
+	PTR         string   `json:"ptr,omitempty"`
+	CertNames   []string `json:"cert_names,omitempty"`
+	Domains     []string `json:"domains"`
+	ASN         int      `json:"asn,omitempty"`
+	CIDR        string   `json:"cidr,omitempty"`
+	Description string   `json:"desc,omitempty"`
+}
+
+// hostScanner investigates the addresses using a fixed number of workers,
+// and paces the addresses to the rate allowed by the timing template.
+type hostScanner struct {
+	it      *utils.IPIterator
+	ports   []int
+	workers int
+	limiter *utils.RateLimiter
+
+	total     *big.Int
+	started   time.Time
+	processed uint64
+	records   uint64
+
+	// The lookups performed on each address, which are replaced during testing
+	reverse  func(addr string) (string, string, error)
+	certs    func(addr string, ports []int) []*core.AmassRequest
+	netblock func(addr string) (int, *net.IPNet, string, error)
+}
+
+func newHostScanner(it *utils.IPIterator, timing core.EnumerationTiming, rate int, ports []int) *hostScanner {
+	if rate <= 0 {
+		rate = core.TimingToSweepsPerSecond(timing)
+	}
+
+	return &hostScanner{
+		it:      it,
+		ports:   ports,
+		workers: core.TimingToMaxFlow(timing),
+		limiter: utils.NewRateLimiter(rate),
+		total:   it.Total(),
+		started: time.Now(),
+
+		reverse:  dnssrv.Reverse,
+		certs:    amass.PullCertificateNames,
+		netblock: amass.IPRequest,
+	}
+}
+
+// run sends a record for each address where names were discovered, and closes
+// the channel once all the addresses produced by the iterator have been investigated.
+func (hs *hostScanner) run(out chan<- *hostRecord) {
+	it := hs.it
+
+	var wg sync.WaitGroup
+	addrs := make(chan net.IP, hs.workers)
+	for i := 0; i < hs.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for ip := range addrs {
+				if r := hs.investigate(ip); r != nil {
+					atomic.AddUint64(&hs.records, 1)
+					out <- r
+				}
+				atomic.AddUint64(&hs.processed, 1)
+			}
+		}()
+	}
+
+	for ip := it.Next(); ip != nil && hs.limiter.Take(); ip = it.Next() {
+		addrs <- ip
+	}
+	close(addrs)
+	wg.Wait()
+	hs.limiter.Stop()
+	close(out)
+}
+
+// investigate obtains the PTR name and certificate names for the address.
+func (hs *hostScanner) investigate(ip net.IP) *hostRecord {
+	addr := ip.String()
+	record := &hostRecord{IP: addr}
+	filter := make(map[string]struct{})
+
+	addDomain := func(d string) {
+		if d = strings.TrimSpace(d); d == "" {
+			return
+		}
+		if _, found := filter[d]; !found {
+			filter[d] = struct{}{}
+			record.Domains = append(record.Domains, d)
+		}
+	}
+
+	core.MaxConnections.Acquire(1)
+	if _, answer, err := hs.reverse(addr); err == nil {
+		record.PTR = answer
+		addDomain(amass.SubdomainToDomain(answer))
+	}
+	core.MaxConnections.Release(1)
+
+	core.MaxConnections.Acquire(1)
+	for _, r := range hs.certs(addr, hs.ports) {
+		record.CertNames = utils.UniqueAppend(record.CertNames, r.Name)
+		addDomain(r.Domain)
+	}
+	core.MaxConnections.Release(1)
+
+	if record.PTR == "" && len(record.CertNames) == 0 {
+		return nil
+	}
+
+	if asn, cidr, desc, err := hs.netblock(addr); err == nil {
+		record.ASN = asn
+		record.CIDR = cidr.String()
+		record.Description = desc
+	}
+	return record
+}
+
+// printProgress renders the progress of the scanner on standard error until done is closed.
+func (hs *hostScanner) printProgress(done chan struct{}) {
+	t := time.NewTicker(utils.StatusInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-done:
+			status.Clear()
+			return
+		case <-t.C:
+			status.Show(hs.progressLine())
+		}
+	}
+}
+
+func (hs *hostScanner) progressLine() string {
+	processed := atomic.LoadUint64(&hs.processed)
+	elapsed := time.Since(hs.started)
+
+	var rate float64
+	if secs := elapsed.Seconds(); secs > 0 {
+		rate = float64(processed) / secs
+	}
+
+	total, eta := "?", "?"
+	var pct float64
+	if hs.total.IsUint64() {
+		t := hs.total.Uint64()
+
+		total = fmt.Sprintf("%d", t)
+		if t > 0 {
+			pct = float64(processed) / float64(t) * 100
+		}
+		if rate > 0 && t >= processed {
+			eta = (time.Duration(float64(t-processed)/rate) * time.Second).String()
+		}
+	}
+	return fmt.Sprintf("Hosts: %d/%s (%.1f%%) %.0f/s ETA %s | Records: %d",
+		processed, total, pct, rate, eta, atomic.LoadUint64(&hs.records))
+}
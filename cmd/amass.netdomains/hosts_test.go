@@ -0,0 +1,77 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/OWASP/Amass/amass/core"
+	"github.com/OWASP/Amass/amass/utils"
+)
+
+func TestHostScanner(t *testing.T) {
+	r, _ := utils.ParseIPRange("192.0.2.1-10")
+	_, cidr, _ := net.ParseCIDR("192.0.2.0/24")
+
+	hs := newHostScanner(utils.NewIPIterator(r), core.Aggressive, 20, []int{443})
+	hs.reverse = func(addr string) (string, string, error) {
+		switch addr {
+		case "192.0.2.1":
+			return utils.ReverseIP(addr) + ".in-addr.arpa", "www.example.com", nil
+		case "192.0.2.2":
+			return utils.ReverseIP(addr) + ".in-addr.arpa", "mail.example.org", nil
+		}
+		return "", "", errors.New("no PTR record")
+	}
+	hs.certs = func(addr string, ports []int) []*core.AmassRequest {
+		if addr != "192.0.2.2" && addr != "192.0.2.3" {
+			return nil
+		}
+		return []*core.AmassRequest{
+			{Name: "api.example.net", Domain: "example.net"},
+			{Name: "mail.example.org", Domain: "example.org"},
+		}
+	}
+	hs.netblock = func(addr string) (int, *net.IPNet, string, error) {
+		return 64500, cidr, "EXAMPLE-AS", nil
+	}
+
+	start := time.Now()
+	records := make(chan *hostRecord, 10)
+	go hs.run(records)
+
+	got := make(map[string]*hostRecord)
+	for record := range records {
+		got[record.IP] = record
+	}
+	// The addresses are paced to the rate provided
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Errorf("The ten addresses were investigated in %s at 20 per second", elapsed)
+	}
+	if hs.processed != 10 || hs.records != 3 || len(got) != 3 {
+		t.Fatalf("The scanner processed %d addresses and sent %d records: %v", hs.processed, hs.records, got)
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(got["192.0.2.2"]); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"ip":"192.0.2.2","ptr":"mail.example.org","cert_names":["api.example.net","mail.example.org"],` +
+		`"domains":["example.org","example.net"],"asn":64500,"cidr":"192.0.2.0/24","desc":"EXAMPLE-AS"}` + "\n"
+	if buf.String() != want {
+		t.Errorf("The JSON record was %s", buf.String())
+	}
+
+	if rec := got["192.0.2.1"]; rec == nil || rec.PTR != "www.example.com" || len(rec.CertNames) != 0 {
+		t.Errorf("The record for the PTR name was %+v", rec)
+	}
+	if rec := got["192.0.2.3"]; rec == nil || rec.PTR != "" || len(rec.Domains) != 2 {
+		t.Errorf("The record for the certificate names was %+v", rec)
+	}
+}
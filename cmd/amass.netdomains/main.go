@@ -4,8 +4,10 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"math/rand"
 	"net"
 	"os"
@@ -15,48 +17,119 @@ import (
 
 	"github.com/OWASP/Amass/amass"
 	"github.com/OWASP/Amass/amass/core"
-	"github.com/OWASP/Amass/amass/dnssrv"
 	"github.com/OWASP/Amass/amass/utils"
 )
 
 var (
-	started   = make(chan struct{}, 50)
-	done      = make(chan struct{}, 50)
-	results   = make(chan string, 100)
-	whoisChan = make(chan string, 100)
+	whoisChan = make(chan []string, 100)
+	rdapChan  = make(chan string, 100)
 )
 
 func main() {
-	var whois bool
-	var org string
+	var whois, rdap, intel, sequential, progress bool
+	var org, outpath, shard, jsonpath string
+	var minconf, timing, rate int
 	var addrs parseIPs
 	var cidrs parseCIDRs
 	var asns, ports parseInts
+	var asndbs parseStrings
+	headers := make(parseHeaders)
 
 	help := flag.Bool("h", false, "Show the program usage message")
 	flag.StringVar(&org, "org", "", "Search string provided against AS description information")
-	flag.Var(&addrs, "addr", "IPs and ranges (192.168.1.1-254) separated by commas")
+	flag.Var(&addrs, "addr", "IPs and ranges (192.168.1.1-254, 2001:db8::1-ff) separated by commas")
 	flag.Var(&cidrs, "cidr", "CIDRs separated by commas (can be used multiple times)")
 	flag.Var(&asns, "asn", "ASNs separated by commas (can be used multiple times)")
 	flag.BoolVar(&whois, "whois", false, "All discovered domains are run through reverse whois")
+	flag.BoolVar(&intel, "intel", false, "Discover the root domains of the org, ASNs and netblocks with confidence scores")
+	flag.StringVar(&outpath, "o", "", "Path to the file that receives the intel root domains, for use with amass -df")
+	flag.IntVar(&minconf, "min-conf", 0, "Minimum confidence (0-100) of the intel root domains written to the file")
+	flag.BoolVar(&rdap, "rdap", false, "Print the RDAP registrant organization and abuse contacts of ASNs and domains")
+	flag.StringVar(&shard, "shard", "", "Process only shard N of M shards of the addresses (e.g. 1/4)")
+	flag.BoolVar(&sequential, "seq", false, "Process the addresses in sequential order instead of a random order")
+	flag.IntVar(&timing, "T", int(core.Normal), "Timing templates 0 (slowest) through 5 (fastest)")
+	flag.IntVar(&rate, "rate", 0, "Maximum addresses investigated per second (overrides the timing template)")
+	flag.BoolVar(&progress, "progress", false, "Show a live status line with the progress through the addresses")
+	flag.StringVar(&jsonpath, "json", "", "Path to the JSON output file of the records discovered on each address")
 	flag.Var(&ports, "p", "Ports separated by commas (default: 443)")
+	flag.Var(&asndbs, "asn-db", "Local IP-to-ASN database files used before online sources (can be used multiple times)")
+	netcache := flag.String("netcache", "", "File that keeps the ASN and netblock data between runs, such as "+amass.DefaultInfraCachePath())
+	proxy := flag.String("proxy", "", "URL of the HTTP(S) or SOCKS5 proxy used for web requests")
+	capath := flag.String("ca", "", "Path to a PEM file providing additional trusted certificate authorities")
+	agentspath := flag.String("uaf", "", "Path to a file providing user agents rotated through during web requests")
+	flag.Var(headers, "H", "HTTP header 'Name: value' added to web requests (can be used multiple times)")
 	flag.Parse()
 
 	if *help {
-		fmt.Printf("Usage: %s [--addr IP] [--cidr CIDR] [--asn number] [-p number]\n", path.Base(os.Args[0]))
+		fmt.Printf("Usage: %s [--addr IP] [--cidr CIDR] [--asn number] [--org string] [-intel] [-p number]\n", path.Base(os.Args[0]))
 		flag.PrintDefaults()
 		return
 	}
 	if len(ports) == 0 {
 		ports = []int{443}
 	}
+	if err := setupHTTP(*proxy, *capath, *agentspath, headers); err != nil {
+		fmt.Println(err)
+		return
+	}
+	if timing < int(core.Paranoid) || timing > int(core.Insane) {
+		fmt.Println("The timing template must be between 0 and 5")
+		return
+	}
+	if rate < 0 {
+		fmt.Println("The rate cannot be negative")
+		return
+	}
+	shardIdx, shards, err := parseShard(shard)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, file := range asndbs {
+		if err := amass.LoadASNDatabase(file); err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+	if *netcache != "" {
+		if err := amass.OpenInfrastructureCache(*netcache, 0); err != nil {
+			fmt.Println(err)
+			return
+		}
+		defer func() {
+			if err := amass.SaveInfrastructureCache(); err != nil {
+				fmt.Println(err)
+			}
+		}()
+	}
 
 	rand.Seed(time.Now().UTC().UnixNano())
+	if intel {
+		ic := amass.NewIntelCollection()
+		ic.Org = org
+		ic.ASNs = asns
+		ic.CIDRs = cidrs
+		ic.Ranges = addrs
+		ic.Ports = ports
+		ic.ReverseWhois = whois
+		ic.Shard = shardIdx
+		ic.Shards = shards
+		ic.Sequential = sequential
+
+		if err := performIntel(ic, outpath, minconf); err != nil {
+			fmt.Println(err)
+		}
+		return
+	}
 	if org != "" {
 		records, err := amass.LookupASNsByName(org)
 		if err == nil {
 			for _, a := range records {
-				fmt.Printf("%d, %s, %s, %s\n", a.ASN, a.CC, a.Registry, a.Description)
+				fmt.Printf("%d, %s, %s, %s", a.ASN, a.CC, a.Registry, a.Description)
+				if rdap {
+					fmt.Print(registrationDetails(amass.RDAPASNRequest(a.ASN)))
+				}
+				fmt.Println()
 			}
 		} else {
 			fmt.Printf("%v\n", err)
@@ -64,103 +137,180 @@ func main() {
 		return
 	}
 
-	ips := allIPsInScope(addrs, cidrs, asns)
-	if len(ips) == 0 {
+	if rdap {
+		for _, asn := range asns {
+			fmt.Printf("AS%d%s\n", asn, registrationDetails(amass.RDAPASNRequest(asn)))
+		}
+	}
+
+	it := utils.NewIPIterator(allRangesInScope(addrs, cidrs, asns)...)
+	if it.Total().Sign() == 0 {
 		fmt.Println("The parameters identified no hosts")
 		return
 	}
+	if shards > 0 {
+		it.Shard(shardIdx, shards)
+	}
+	if !sequential {
+		it.Randomize(time.Now().UnixNano())
+	}
+
+	var enc *json.Encoder
+	if jsonpath != "" {
+		f, err := os.Create(jsonpath)
+		if err != nil {
+			fmt.Printf("Failed to open the JSON output file: %v\n", err)
+			return
+		}
+		defer f.Close()
+		enc = json.NewEncoder(f)
+	}
+
 	// Begin discovering all the domain names
-	go performAllReverseDNS(ips)
-	go pullAllCertificates(ips, ports)
+	hs := newHostScanner(it, core.EnumerationTiming(timing), rate, ports)
+	records := make(chan *hostRecord, 100)
+	go hs.run(records)
+	if progress {
+		progressDone := make(chan struct{})
+		go hs.printProgress(progressDone)
+		defer close(progressDone)
+	}
 	// Print all the unique domain names
-	var count int
+	var pending int
 	filter := utils.NewStringFilter()
-loop:
-	for {
+	for records != nil || pending > 0 {
 		select {
-		case <-started:
-			count++
-		case <-done:
-			count--
-			if count == 0 {
-				break loop
+		case record, ok := <-records:
+			if !ok {
+				records = nil
+				continue
 			}
-		case d := <-results:
-			if !filter.Duplicate(d) {
-				if whois {
-					go getWhoisDomains(d)
+			if enc != nil {
+				enc.Encode(record)
+			}
+			for _, d := range record.Domains {
+				if !filter.Duplicate(d) {
+					if whois {
+						pending++
+						go getWhoisDomains(d)
+					}
+					pending += printDomain(d, rdap)
 				}
-				fmt.Println(d)
 			}
-		case domain := <-whoisChan:
-			if !filter.Duplicate(domain) {
-				fmt.Println(domain)
+		case domains := <-whoisChan:
+			pending--
+			for _, d := range domains {
+				if !filter.Duplicate(d) {
+					pending += printDomain(d, rdap)
+				}
 			}
+		case line := <-rdapChan:
+			printLine(line)
+			pending--
 		}
 	}
 }
 
-func getWhoisDomains(d string) {
-	domains, err := amass.ReverseWhois(d)
-	if err != nil {
-		return
+// performIntel prints the root domains discovered by the intelligence collection, and writes
+// the domains meeting the minimum confidence to the file at outpath.
+// setupHTTP applies the settings shared by all outbound web requests, such as the RDAP lookups.
+func setupHTTP(proxy, capath, agentspath string, headers map[string]string) error {
+	if err := utils.SetHTTPProxy(proxy); err != nil {
+		return err
+	}
+	if capath != "" {
+		if err := utils.SetHTTPCABundle(capath); err != nil {
+			return err
+		}
 	}
-	for _, domain := range domains {
-		if name := strings.TrimSpace(domain); name != "" {
-			results <- name
+	if agentspath != "" {
+		data, err := ioutil.ReadFile(agentspath)
+		if err != nil {
+			return fmt.Errorf("Failed to read the user agents file %s: %v", agentspath, err)
 		}
+		utils.SetUserAgents(strings.Split(string(data), "\n"))
 	}
+	utils.SetHTTPHeaders(headers)
+	return nil
 }
 
-func performAllReverseDNS(ips []net.IP) {
-	for _, ip := range ips {
-		core.MaxConnections.Acquire(1)
-		started <- struct{}{}
+func performIntel(ic *amass.IntelCollection, outpath string, minconf int) error {
+	var out *os.File
 
-		go func(ip net.IP) {
-			if _, answer, err := dnssrv.Reverse(ip.String()); err == nil {
-				if d := strings.TrimSpace(amass.SubdomainToDomain(answer)); d != "" {
-					results <- d
-				}
-			}
-			core.MaxConnections.Release(1)
-			done <- struct{}{}
-		}(ip)
+	if outpath != "" {
+		var err error
+
+		out, err = os.Create(outpath)
+		if err != nil {
+			return fmt.Errorf("Failed to open the output file: %v", err)
+		}
+		defer out.Close()
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- ic.Start()
+	}()
+
+	for result := range ic.Output {
+		fmt.Printf("%s, %d, %s\n", result.Domain, result.Confidence, strings.Join(result.Sources, " "))
+		if out != nil && result.Confidence >= minconf {
+			fmt.Fprintln(out, result.Domain)
+		}
 	}
+	return <-errChan
 }
 
-func pullAllCertificates(ips []net.IP, ports parseInts) {
-	maxPulls := utils.NewSemaphore(100)
+// printDomain prints the domain name, or requests the registration data that will be printed
+// with the name. The number of lines still to be printed is returned.
+func printDomain(domain string, rdap bool) int {
+	if !rdap {
+		printLine(domain)
+		return 0
+	}
 
-	for _, ip := range ips {
-		core.MaxConnections.Acquire(1)
-		maxPulls.Acquire(1)
-		started <- struct{}{}
+	go func() {
+		rdapChan <- domain + registrationDetails(amass.RDAPDomainRequest(domain))
+	}()
+	return 1
+}
 
-		go func(ip net.IP) {
-			var domains []string
-			for _, r := range amass.PullCertificateNames(ip.String(), ports) {
-				domains = utils.UniqueAppend(domains, r.Domain)
-			}
-			for _, domain := range domains {
-				if d := strings.TrimSpace(domain); d != "" {
-					results <- d
-				}
+// registrationDetails returns the registrant organization and abuse contacts to be appended to an output line.
+func registrationDetails(record *amass.RDAPRecord, err error) string {
+	if err != nil {
+		return ", (RDAP unavailable)"
+	}
+
+	details := ", " + record.Organization
+	if len(record.AbuseEmails) > 0 {
+		details += ", " + strings.Join(record.AbuseEmails, " ")
+	}
+	return details
+}
+
+// getWhoisDomains sends the domains related to d through reverse whois on the whois channel.
+func getWhoisDomains(d string) {
+	var names []string
+
+	if domains, err := amass.ReverseWhois(d); err == nil {
+		for _, domain := range domains {
+			if name := strings.TrimSpace(domain); name != "" {
+				names = append(names, name)
 			}
-			maxPulls.Release(1)
-			core.MaxConnections.Release(1)
-			done <- struct{}{}
-		}(ip)
+		}
 	}
+	whoisChan <- names
 }
 
-func allIPsInScope(addrs parseIPs, cidrs parseCIDRs, asns parseInts) []net.IP {
-	var ips []net.IP
+// allRangesInScope returns the address ranges provided by the parameters, with the
+// overlapping ranges combined so each address is only investigated once.
+func allRangesInScope(addrs parseIPs, cidrs parseCIDRs, asns parseInts) []utils.IPRange {
+	var ranges []utils.IPRange
 
-	ips = append(ips, addrs...)
+	ranges = append(ranges, addrs...)
 
 	for _, cidr := range cidrs {
-		ips = append(ips, utils.NetHosts(cidr)...)
+		ranges = append(ranges, utils.CIDRHosts(cidr))
 	}
 
 	for _, asn := range asns {
@@ -175,8 +325,8 @@ func allIPsInScope(addrs parseIPs, cidrs parseCIDRs, asns parseInts) []net.IP {
 				continue
 			}
 
-			ips = append(ips, utils.NetHosts(ipnet)...)
+			ranges = append(ranges, utils.CIDRHosts(ipnet))
 		}
 	}
-	return ips
+	return utils.MergeIPRanges(ranges)
 }
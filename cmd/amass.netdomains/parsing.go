@@ -14,9 +14,10 @@ import (
 
 // Types that implement the flag.Value interface for parsing
 type parseStrings []string
-type parseIPs []net.IP
+type parseIPs []utils.IPRange
 type parseCIDRs []*net.IPNet
 type parseInts []int
+type parseHeaders map[string]string
 
 // parseStrings implementation of the flag.Value interface
 func (p *parseStrings) String() string {
@@ -38,6 +39,26 @@ func (p *parseStrings) Set(s string) error {
 	return nil
 }
 
+// parseHeaders implementation of the flag.Value interface
+func (p parseHeaders) String() string {
+	var hvals []string
+
+	for k, v := range p {
+		hvals = append(hvals, k+": "+v)
+	}
+	return strings.Join(hvals, ",")
+}
+
+func (p parseHeaders) Set(s string) error {
+	parts := strings.SplitN(s, ":", 2)
+
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+		return fmt.Errorf("%s is not a valid HTTP header", s)
+	}
+	p[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	return nil
+}
+
 // parseInts implementation of the flag.Value interface
 func (p *parseInts) String() string {
 	if p == nil {
@@ -74,8 +95,12 @@ func (p *parseIPs) String() string {
 	}
 
 	var ipaddrs []string
-	for _, ipaddr := range *p {
-		ipaddrs = append(ipaddrs, ipaddr.String())
+	for _, r := range *p {
+		if r.First.Equal(r.Last) {
+			ipaddrs = append(ipaddrs, r.First.String())
+			continue
+		}
+		ipaddrs = append(ipaddrs, r.String())
 	}
 	return strings.Join(ipaddrs, ",")
 }
@@ -96,39 +121,24 @@ func (p *parseIPs) Set(s string) error {
 		if addr == nil {
 			return fmt.Errorf("%s is not a valid IP address or range", ip)
 		}
-		*p = append(*p, addr)
-	}
-	return nil
-}
-
-func (p *parseIPs) appendIPs(addrs []net.IP) error {
-	for _, addr := range addrs {
-		*p = append(*p, addr)
+		r, _ := utils.NewIPRange(addr, addr)
+		*p = append(*p, r)
 	}
 	return nil
 }
 
 func (p *parseIPs) parseRange(s string) error {
-	twoIPs := strings.Split(s, "-")
-
-	if twoIPs[0] == s {
+	if !strings.Contains(s, "-") {
 		// This is not an IP range
 		return fmt.Errorf("%s is not a valid IP range", s)
 	}
-	start := net.ParseIP(twoIPs[0])
-	end := net.ParseIP(twoIPs[1])
-	if end == nil {
-		num, err := strconv.Atoi(twoIPs[1])
-		if err == nil {
-			end = net.ParseIP(twoIPs[0])
-			end[len(end)-1] = byte(num)
-		}
-	}
-	if start == nil || end == nil {
-		// These should have parsed properly
-		return fmt.Errorf("%s is not a valid IP range", s)
+
+	r, err := utils.ParseIPRange(s)
+	if err != nil {
+		return err
 	}
-	return p.appendIPs(utils.RangeHosts(start, end))
+	*p = append(*p, r)
+	return nil
 }
 
 // parseCIDRs implementation of the flag.Value interface
@@ -160,3 +170,21 @@ func (p *parseCIDRs) Set(s string) error {
 	}
 	return nil
 }
+
+// parseShard returns the zero-based shard index and the number of shards for the
+// N/M notation. Zero shards are returned when the string is empty.
+func parseShard(s string) (int, int, error) {
+	if s == "" {
+		return 0, 0, nil
+	}
+
+	parts := strings.Split(s, "/")
+	if len(parts) == 2 {
+		n, err1 := strconv.Atoi(strings.TrimSpace(parts[0]))
+		m, err2 := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err1 == nil && err2 == nil && n >= 1 && n <= m {
+			return n - 1, m, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("%s is not a valid shard, such as 1/4", s)
+}
@@ -22,13 +22,14 @@ var (
 	graphistrypath = flag.String("graphistry", "", "Path to the Graphistry JSON file")
 	gexfpath       = flag.String("gexf", "", "Path to the Gephi Graph Exchange XML Format (GEXF) file")
 	d3path         = flag.String("d3", "", "Path to the D3 v4 force simulation HTML file")
+	geomap         = flag.Bool("map", false, "Place the located addresses on a world map in the D3 and Visjs files")
 )
 
 func main() {
 	flag.Parse()
 
 	if *help {
-		fmt.Printf("Usage: %s -i infile --maltego of1 --visjs of2 --gexf of3 --d3 of4 --graphistry of5\n", path.Base(os.Args[0]))
+		fmt.Printf("Usage: %s -i infile --maltego of1 --visjs of2 --gexf of3 --d3 of4 --graphistry of5 [--map]\n", path.Base(os.Args[0]))
 		flag.PrintDefaults()
 		return
 	}
@@ -91,7 +92,11 @@ func writeVisjsFile(path string, nodes []viz.Node, edges []viz.Edge) {
 	}
 	defer f.Close()
 
-	viz.WriteVisjsData(f, nodes, edges)
+	if *geomap {
+		viz.WriteVisjsMapData(f, nodes, edges)
+	} else {
+		viz.WriteVisjsData(f, nodes, edges)
+	}
 	f.Sync()
 }
 
@@ -136,6 +141,10 @@ func writeD3File(path string, nodes []viz.Node, edges []viz.Edge) {
 	}
 	defer f.Close()
 
-	viz.WriteD3Data(f, nodes, edges)
+	if *geomap {
+		viz.WriteD3MapData(f, nodes, edges)
+	} else {
+		viz.WriteD3Data(f, nodes, edges)
+	}
 	f.Sync()
 }
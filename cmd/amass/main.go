@@ -11,10 +11,12 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"math/rand"
 	"os"
 	"path"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -22,6 +24,7 @@ import (
 	"github.com/OWASP/Amass/amass"
 	"github.com/OWASP/Amass/amass/core"
 	"github.com/OWASP/Amass/amass/dnssrv"
+	"github.com/OWASP/Amass/amass/sources"
 	"github.com/OWASP/Amass/amass/utils"
 	"github.com/fatih/color"
 )
@@ -40,18 +43,36 @@ type asnData struct {
 }
 
 type jsonAddr struct {
-	IP          string `json:"ip"`
-	CIDR        string `json:"cidr"`
-	ASN         int    `json:"asn"`
-	Description string `json:"desc"`
+	IP          string  `json:"ip"`
+	CIDR        string  `json:"cidr"`
+	ASN         int     `json:"asn"`
+	Description string  `json:"desc"`
+	Provider    string  `json:"provider,omitempty"`
+	Service     string  `json:"service,omitempty"`
+	Region      string  `json:"region,omitempty"`
+	Country     string  `json:"country,omitempty"`
+	City        string  `json:"city,omitempty"`
+	Latitude    float64 `json:"lat,omitempty"`
+	Longitude   float64 `json:"lon,omitempty"`
+}
+
+type jsonHTTP struct {
+	URL          string   `json:"url"`
+	StatusCode   int      `json:"status"`
+	Title        string   `json:"title,omitempty"`
+	Server       string   `json:"server,omitempty"`
+	Redirects    []string `json:"redirects,omitempty"`
+	Technologies []string `json:"tech,omitempty"`
 }
 
 type jsonSave struct {
-	Name      string     `json:"name"`
-	Domain    string     `json:"domain"`
-	Addresses []jsonAddr `json:"addresses"`
-	Tag       string     `json:"tag"`
-	Source    string     `json:"source"`
+	Name       string     `json:"name"`
+	Domain     string     `json:"domain"`
+	Addresses  []jsonAddr `json:"addresses"`
+	HTTP       []jsonHTTP `json:"http,omitempty"`
+	Tag        string     `json:"tag"`
+	Source     string     `json:"source"`
+	OutOfScope bool       `json:"out_of_scope,omitempty"`
 }
 
 var (
@@ -64,6 +85,7 @@ var (
 	yellow = color.New(color.FgHiYellow).SprintFunc()
 	green  = color.New(color.FgHiGreen).SprintFunc()
 	blue   = color.New(color.FgHiBlue).SprintFunc()
+	red    = color.New(color.FgHiRed).SprintFunc()
 	// Command-line switches and provided parameters
 	help          = flag.Bool("h", false, "Show the program usage message")
 	version       = flag.Bool("version", false, "Print the version number of this amass binary")
@@ -73,11 +95,21 @@ var (
 	active        = flag.Bool("active", false, "Attempt zone transfers and certificate name grabs")
 	norecursive   = flag.Bool("norecursive", false, "Turn off recursive brute forcing")
 	minrecursive  = flag.Int("min-for-recursive", 1, "Number of subdomain discoveries before recursive brute forcing")
+	maxdepth      = flag.Int("max-depth", 0, "Maximum subdomain labels beneath the root domain for recursive brute forcing")
 	passive       = flag.Bool("passive", false, "Disable DNS resolution of names and dependent features")
 	noalts        = flag.Bool("noalts", false, "Disable generation of altered names")
-	sources       = flag.Bool("src", false, "Print data sources for the discovered names")
+	rdap          = flag.Bool("rdap", false, "Retrieve registration data using RDAP for netblocks, ASNs and root domains")
+	httpprobe     = flag.Bool("http-probe", false, "Fingerprint the web services on resolved names using the ports provided")
+	progress      = flag.Bool("progress", false, "Show a live status line with brute forcing and alteration progress")
+	srcs          = flag.Bool("src", false, "Print data sources for the discovered names")
+	list          = flag.Bool("list", false, "Print the names of all available data sources")
 	timing        = flag.Int("T", int(core.Normal), "Timing templates 0 (slowest) through 5 (fastest)")
-	wordlist      = flag.String("w", "", "Path to a different wordlist file")
+	sweepsize     = flag.Int("sweep-size", 0, "Number of addresses swept around each discovered address")
+	sweepnets     = flag.Bool("sweep-netblocks", false, "Sweep the entire netblock containing each discovered address")
+	sweeprate     = flag.Int("sweep-rate", 0, "Maximum reverse DNS sweep queries per second")
+	altwordlist   = flag.String("aw", "", "Path to a different wordlist file for generating altered names")
+	altrate       = flag.Int("alt-rate", 0, "Maximum word-based altered names sent per second")
+	combopath     = flag.String("cw", "", "Path to a wordlist file combined with the brute forcing words (word1-word2)")
 	allpath       = flag.String("oA", "", "Path prefix used for naming all output files")
 	logpath       = flag.String("log", "", "Path to the log file where errors will be written")
 	outpath       = flag.String("o", "", "Path to the text output file")
@@ -86,11 +118,17 @@ var (
 	domainspath   = flag.String("df", "", "Path to a file providing root domain names")
 	resolvepath   = flag.String("rf", "", "Path to a file providing preferred DNS resolvers")
 	blacklistpath = flag.String("blf", "", "Path to a file providing blacklisted subdomains")
+	proxy         = flag.String("proxy", "", "URL of the HTTP(S) or SOCKS5 proxy used for web requests")
+	capath        = flag.String("ca", "", "Path to a PEM file providing additional trusted certificate authorities")
+	agentspath    = flag.String("uaf", "", "Path to a file providing user agents rotated through during web requests")
 )
 
 func main() {
-	var ports parseInts
-	var domains, resolvers, blacklist parseStrings
+	var ports, asns, asnbl parseInts
+	var cidrs, cidrbl parseCIDRs
+	var domains, resolvers, included, excluded, masks, charsets, wordlists, levelwords, asndbs, clouds parseStrings
+	var blacklist parseRules
+	headers := make(parseHeaders)
 
 	defaultBuf := new(bytes.Buffer)
 	flag.CommandLine.SetOutput(defaultBuf)
@@ -98,7 +136,22 @@ func main() {
 	flag.Var(&ports, "p", "Ports separated by commas (default: 443)")
 	flag.Var(&domains, "d", "Domain names separated by commas (can be used multiple times)")
 	flag.Var(&resolvers, "r", "IP addresses of preferred DNS resolvers (can be used multiple times)")
-	flag.Var(&blacklist, "bl", "Blacklist of subdomain names that will not be investigated")
+	flag.Var(&blacklist, "bl", "Blacklist rules (names, =exact, globs, re:regex, !allow) for subdomains not investigated")
+	flag.Var(&cidrs, "cidr", "CIDRs in scope separated by commas (can be used multiple times)")
+	flag.Var(&asns, "asn", "ASNs in scope separated by commas (can be used multiple times)")
+	flag.Var(&cidrbl, "cidr-bl", "CIDRs out of scope separated by commas (can be used multiple times)")
+	flag.Var(&asnbl, "asn-bl", "ASNs out of scope separated by commas (can be used multiple times)")
+	flag.Var(&asndbs, "asn-db", "Local IP-to-ASN database files used before online sources (can be used multiple times)")
+	flag.Var(&clouds, "cloud-ranges", "Published cloud/CDN IP range files, as path or provider=path (can be used multiple times)")
+	geodb := flag.String("geo-db", "", "MaxMind DB file, such as GeoLite2 City, used to locate the addresses")
+	netcache := flag.String("netcache", "", "File that keeps the ASN and netblock data between runs, such as "+amass.DefaultInfraCachePath())
+	flag.Var(&included, "include", "Data source names or types separated by commas to be included")
+	flag.Var(&excluded, "exclude", "Data source names or types separated by commas to be excluded")
+	flag.Var(&wordlists, "w", "Paths to different wordlist files, which can be gzipped (can be used multiple times)")
+	flag.Var(&levelwords, "rw", "Wordlist files for each recursive brute forcing level (last file used for deeper levels)")
+	flag.Var(&masks, "mask", "Masks for brute forcing labels, e.g. web?d?d (can be used multiple times)")
+	flag.Var(&charsets, "charset", "Custom character sets used in masks as ?1 through ?4 (in the order provided)")
+	flag.Var(headers, "H", "HTTP header 'Name: value' added to web requests (can be used multiple times)")
 	flag.Parse()
 
 	// Some input validation
@@ -113,15 +166,22 @@ func main() {
 		fmt.Printf("version %s\n", amass.Version)
 		return
 	}
+	if *list {
+		printSources()
+		return
+	}
 	if *passive && *ips {
 		r.Println("IP addresses cannot be provided without DNS resolution")
 		return
 	}
 
-	var words []string
+	var altwords, combowords []string
 	// Obtain parameters from provided files
-	if *wordlist != "" {
-		words = getLinesFromFile(*wordlist)
+	if *altwordlist != "" {
+		altwords = getLinesFromFile(*altwordlist)
+	}
+	if *combopath != "" {
+		combowords = getLinesFromFile(*combopath)
 	}
 	if *blacklistpath != "" {
 		blacklist = utils.UniqueAppend(blacklist, getLinesFromFile(*blacklistpath)...)
@@ -130,6 +190,10 @@ func main() {
 		resolvers = utils.UniqueAppend(resolvers, getLinesFromFile(*resolvepath)...)
 	}
 	dnssrv.SetCustomResolvers(resolvers)
+	if err := setupHTTP(*proxy, *capath, *agentspath, headers); err != nil {
+		r.Println(err)
+		return
+	}
 	if *domainspath != "" {
 		domains = utils.UniqueAppend(domains, getLinesFromFile(*domainspath)...)
 	}
@@ -163,19 +227,47 @@ func main() {
 	rLog, wLog := io.Pipe()
 	enum := amass.NewEnumeration()
 	enum.Config.Log = log.New(wLog, "", log.Lmicroseconds)
-	enum.Config.Wordlist = words
-	enum.Config.BruteForcing = *brute
+	enum.Config.WordlistFiles = wordlists
+	enum.Config.BruteForcing = *brute || len(masks) > 0 || len(combowords) > 0
+	enum.Config.BruteMasks = masks
+	enum.Config.MaskCharsets = charsets
+	enum.Config.CombinatorWordlist = combowords
 	enum.Config.Recursive = recursive
 	enum.Config.MinForRecursive = *minrecursive
+	enum.Config.MaxRecursionDepth = *maxdepth
+	enum.Config.RecursiveWordlistFiles = levelwords
 	enum.Config.Active = *active
+	enum.Config.HTTPProbe = *httpprobe
 	enum.Config.IncludeUnresolvable = *unresolved
 	enum.Config.Alterations = alts
+	enum.Config.AltWordlist = altwords
+	enum.Config.AltRate = *altrate
 	enum.Config.Timing = core.EnumerationTiming(*timing)
 	enum.Config.Passive = *passive
+	enum.Config.SweepSize = *sweepsize
+	enum.Config.SweepNetblocks = *sweepnets
+	enum.Config.SweepRate = *sweeprate
 	enum.Config.Blacklist = blacklist
+	enum.Config.CIDRs = cidrs
+	enum.Config.ASNs = asns
+	enum.Config.ExcludedCIDRs = cidrbl
+	enum.Config.ExcludedASNs = asnbl
+	enum.Config.ASNDatabases = asndbs
+	enum.Config.CloudRanges = clouds
+	enum.Config.GeoDatabase = *geodb
+	enum.Config.InfraCachePath = *netcache
+	enum.Config.RDAP = *rdap
+	enum.Config.IncludeSources = included
+	enum.Config.ExcludeSources = excluded
 	for _, domain := range domains {
 		enum.Config.AddDomain(domain)
 	}
+	if len(masks) > 0 || len(combowords) > 0 {
+		if err := printBruteForceEstimate(enum.Config); err != nil {
+			r.Println(err)
+			return
+		}
+	}
 
 	// Setup the log file for saving error messages
 	var logFilePtr *os.File
@@ -209,11 +301,14 @@ func main() {
 	finished = make(chan struct{})
 	go manageOutput(&outputParams{
 		Enum:     enum,
-		PrintSrc: *sources,
+		PrintSrc: *srcs,
 		PrintIPs: *ips,
 		FileOut:  txt,
 		JSONOut:  jsonfile,
 	})
+	if *progress {
+		go printProgress(enum)
+	}
 
 	// Execute the signal handler
 	go signalHandler(enum)
@@ -249,6 +344,22 @@ func getLinesFromFile(path string) []string {
 	return lines
 }
 
+func setupHTTP(proxy, capath, agentspath string, headers map[string]string) error {
+	if err := utils.SetHTTPProxy(proxy); err != nil {
+		return err
+	}
+	if capath != "" {
+		if err := utils.SetHTTPCABundle(capath); err != nil {
+			return err
+		}
+	}
+	if agentspath != "" {
+		utils.SetUserAgents(getLinesFromFile(agentspath))
+	}
+	utils.SetHTTPHeaders(headers)
+	return nil
+}
+
 func writeLogsAndMessages(logs *io.PipeReader, logfile *os.File) {
 	wildcard := regexp.MustCompile("DNS wildcard")
 	avg := regexp.MustCompile("Average requests")
@@ -258,7 +369,9 @@ func writeLogsAndMessages(logs *io.PipeReader, logfile *os.File) {
 		line := scanner.Text()
 
 		if err := scanner.Err(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading the Amass logs: %v\n", err)
+			printWithoutStatus(func() {
+				fmt.Fprintf(os.Stderr, "Error reading the Amass logs: %v\n", err)
+			})
 			break
 		}
 
@@ -268,31 +381,52 @@ func writeLogsAndMessages(logs *io.PipeReader, logfile *os.File) {
 		// Remove the timestamp
 		parts := strings.Split(line, " ")
 		line = strings.Join(parts[1:], " ")
-		// Check for Amass DNS wildcard messages
-		if wildcard.FindString(line) != "" {
-			r.Fprintln(os.Stderr, line)
-		}
-		// Check for the Amass average requests processed messages
-		if avg.FindString(line) != "" {
-			r.Fprintln(os.Stderr, line)
+		// Check for Amass DNS wildcard messages and the average requests processed messages
+		if wildcard.FindString(line) != "" || avg.FindString(line) != "" {
+			printWithoutStatus(func() {
+				r.Fprintln(os.Stderr, line)
+			})
 		}
 	}
 }
 
 func writeJSONData(f *os.File, result *core.AmassOutput) {
 	save := &jsonSave{
-		Name:   result.Name,
-		Domain: result.Domain,
-		Tag:    result.Tag,
-		Source: result.Source,
+		Name:       result.Name,
+		Domain:     result.Domain,
+		Tag:        result.Tag,
+		Source:     result.Source,
+		OutOfScope: result.OutOfScope,
 	}
 
 	for _, addr := range result.Addresses {
-		save.Addresses = append(save.Addresses, jsonAddr{
+		a := jsonAddr{
 			IP:          addr.Address.String(),
 			CIDR:        addr.Netblock.String(),
 			ASN:         addr.ASN,
 			Description: addr.Description,
+			Provider:    addr.CloudProvider,
+			Service:     addr.CloudService,
+			Region:      addr.CloudRegion,
+		}
+		if loc := addr.Location; loc != nil {
+			a.Country = loc.Country
+			a.City = loc.City
+			if loc.Located {
+				a.Latitude = loc.Latitude
+				a.Longitude = loc.Longitude
+			}
+		}
+		save.Addresses = append(save.Addresses, a)
+	}
+	for _, h := range result.HTTP {
+		save.HTTP = append(save.HTTP, jsonHTTP{
+			URL:          h.URL,
+			StatusCode:   h.StatusCode,
+			Title:        h.Title,
+			Server:       h.Server,
+			Redirects:    h.Redirects,
+			Technologies: h.Technologies,
 		})
 	}
 	enc := json.NewEncoder(f)
@@ -319,6 +453,56 @@ func printBanner() {
 	y.Printf("%s\n\n\n", desc)
 }
 
+func printSources() {
+	all := sources.GetAllSources(nil)
+
+	fmt.Fprintf(color.Output, "%s %s\n", blue("Data source types:"), green(strings.Join(sources.SourceTypes, ", ")))
+	for _, source := range all {
+		var notes []string
+
+		switch source.APIKeyRequired() {
+		case sources.APIKeyRequired:
+			notes = append(notes, "API key required")
+		case sources.APIkeyOptional:
+			notes = append(notes, "API key optional")
+		}
+		if sources.DisabledByDefault(source) {
+			notes = append(notes, "disabled by default")
+		}
+
+		var info string
+		if len(notes) > 0 {
+			info = "(" + strings.Join(notes, ", ") + ")"
+		}
+		name := fmt.Sprintf("%-20s", source.String())
+		stype := fmt.Sprintf("%-10s", source.Type())
+		fmt.Fprintf(color.Output, "%s %s %s\n", green(name), blue(stype), yellow(info))
+	}
+}
+
+func printBruteForceEstimate(config *core.AmassConfig) error {
+	gens, err := amass.BruteForceGenerators(config)
+	if err != nil {
+		return err
+	}
+
+	var total uint64
+	var counts []string
+	for _, gen := range gens {
+		c := gen.Count()
+		// Saturate instead of overflowing
+		if total > math.MaxUint64-c {
+			total = math.MaxUint64
+		} else {
+			total += c
+		}
+		counts = append(counts, fmt.Sprintf("%s: %d", gen, c))
+	}
+	fmt.Fprintf(color.Error, "%s %s %s\n", blue("Brute forcing will attempt"),
+		green(strconv.FormatUint(total, 10)), yellow("names per subdomain ("+strings.Join(counts, ", ")+")"))
+	return nil
+}
+
 func resultToLine(result *core.AmassOutput, params *outputParams) (string, string, string, string) {
 	var source, comma, ips string
 
@@ -368,6 +552,7 @@ func manageOutput(params *outputParams) {
 
 	tags := make(map[string]int)
 	asns := make(map[int]*asnData)
+	clouds := make(map[string]int)
 	// Collect all the names returned by the enumeration
 	for result := range params.Enum.Output {
 		if params.Enum.Config.Passive || len(result.Addresses) > 0 {
@@ -375,15 +560,21 @@ func manageOutput(params *outputParams) {
 		}
 		// Do not count unresolved names
 		if len(result.Addresses) > 0 {
-			updateData(result, tags, asns)
+			updateData(result, tags, asns, clouds)
 		}
 
 		source, name, comma, ips := resultToLine(result, params)
-		fmt.Fprintf(color.Output, "%s%s%s%s\n",
-			blue(source), green(name), green(comma), yellow(ips))
+		var scope string
+		if result.OutOfScope {
+			scope = " (out of scope)"
+		}
+		printWithoutStatus(func() {
+			fmt.Fprintf(color.Output, "%s%s%s%s%s\n",
+				blue(source), green(name), green(comma), yellow(ips), red(scope))
+		})
 		// Handle writing the line to a specified output file
 		if outptr != nil {
-			fmt.Fprintf(outptr, "%s%s%s%s\n", source, name, comma, ips)
+			fmt.Fprintf(outptr, "%s%s%s%s%s\n", source, name, comma, ips, scope)
 		}
 		// Handle encoding the result as JSON
 		if jsonptr != nil {
@@ -393,12 +584,12 @@ func manageOutput(params *outputParams) {
 	if total == 0 {
 		r.Println("No names were discovered")
 	} else if !params.Enum.Config.Passive {
-		printSummary(total, tags, asns)
+		printSummary(total, tags, asns, clouds)
 	}
 	close(finished)
 }
 
-func updateData(output *core.AmassOutput, tags map[string]int, asns map[int]*asnData) {
+func updateData(output *core.AmassOutput, tags map[string]int, asns map[int]*asnData, clouds map[string]int) {
 	tags[output.Tag]++
 
 	// Update the ASN information
@@ -413,10 +604,24 @@ func updateData(output *core.AmassOutput, tags map[string]int, asns map[int]*asn
 		}
 		// Increment how many IPs were in this netblock
 		data.Netblocks[addr.Netblock.String()]++
+		// Increment how many IPs were hosted by the cloud provider service
+		if addr.CloudProvider != "" {
+			clouds[cloudLabel(addr)]++
+		}
 	}
 }
 
-func printSummary(total int, tags map[string]int, asns map[int]*asnData) {
+func cloudLabel(addr core.AmassAddressInfo) string {
+	label := addr.CloudProvider
+	for _, s := range []string{addr.CloudService, addr.CloudRegion} {
+		if s != "" {
+			label += " " + s
+		}
+	}
+	return label
+}
+
+func printSummary(total int, tags map[string]int, asns map[int]*asnData, clouds map[string]int) {
 	pad := func(num int, chr string) {
 		for i := 0; i < num; i++ {
 			b.Fprint(os.Stderr, chr)
@@ -463,4 +668,24 @@ func printSummary(total int, tags map[string]int, asns map[int]*asnData) {
 				yellow(cidrstr), yellow(countstr), blue("Subdomain Name(s)"))
 		}
 	}
+
+	if len(clouds) == 0 {
+		return
+	}
+	// Print the cloud and CDN provider information
+	pad(8, "----------")
+	fmt.Fprintln(os.Stderr)
+	var labels []string
+	for label := range clouds {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	for _, label := range labels {
+		ips := clouds[label]
+		labelstr := fmt.Sprintf("%-40s", label)
+		countstr := fmt.Sprintf("%-4s", strconv.Itoa(ips))
+
+		fmt.Fprintf(color.Error, "%s%s %s %s\n", blue("Cloud: "), green(labelstr), yellow(countstr), blue("Address(es)"))
+	}
 }
@@ -5,6 +5,7 @@ package main
 
 import (
 	"fmt"
+	"math/big"
 	"net"
 	"strconv"
 	"strings"
@@ -12,11 +13,16 @@ import (
 	"github.com/OWASP/Amass/amass/utils"
 )
 
+// The largest IP range expanded into individual addresses
+const maxRangeAddrs = 1 << 16
+
 // Types that implement the flag.Value interface for parsing
 type parseStrings []string
+type parseRules []string
 type parseIPs []net.IP
 type parseCIDRs []*net.IPNet
 type parseInts []int
+type parseHeaders map[string]string
 
 // parseStrings implementation of the flag.Value interface
 func (p *parseStrings) String() string {
@@ -38,6 +44,51 @@ func (p *parseStrings) Set(s string) error {
 	return nil
 }
 
+// parseRules implementation of the flag.Value interface
+func (p *parseRules) String() string {
+	if p == nil {
+		return ""
+	}
+	return strings.Join(*p, ",")
+}
+
+func (p *parseRules) Set(s string) error {
+	if s == "" {
+		return fmt.Errorf("Rule parsing failed")
+	}
+
+	// Regular expression rules can contain commas, so they are provided one per flag
+	if v := strings.TrimPrefix(strings.TrimSpace(s), "!"); strings.HasPrefix(v, "re:") {
+		*p = append(*p, strings.TrimSpace(s))
+		return nil
+	}
+
+	for _, r := range strings.Split(s, ",") {
+		*p = append(*p, strings.TrimSpace(r))
+	}
+	return nil
+}
+
+// parseHeaders implementation of the flag.Value interface
+func (p parseHeaders) String() string {
+	var hvals []string
+
+	for k, v := range p {
+		hvals = append(hvals, k+": "+v)
+	}
+	return strings.Join(hvals, ",")
+}
+
+func (p parseHeaders) Set(s string) error {
+	parts := strings.SplitN(s, ":", 2)
+
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+		return fmt.Errorf("%s is not a valid HTTP header", s)
+	}
+	p[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	return nil
+}
+
 // parseInts implementation of the flag.Value interface
 func (p *parseInts) String() string {
 	if p == nil {
@@ -109,26 +160,20 @@ func (p *parseIPs) appendIPs(addrs []net.IP) error {
 }
 
 func (p *parseIPs) parseRange(s string) error {
-	twoIPs := strings.Split(s, "-")
-
-	if twoIPs[0] == s {
+	if !strings.Contains(s, "-") {
 		// This is not an IP range
 		return fmt.Errorf("%s is not a valid IP range", s)
 	}
-	start := net.ParseIP(twoIPs[0])
-	end := net.ParseIP(twoIPs[1])
-	if end == nil {
-		num, err := strconv.Atoi(twoIPs[1])
-		if err == nil {
-			end = net.ParseIP(twoIPs[0])
-			end[len(end)-1] = byte(num)
-		}
+
+	r, err := utils.ParseIPRange(s)
+	if err != nil {
+		return err
 	}
-	if start == nil || end == nil {
-		// These should have parsed properly
-		return fmt.Errorf("%s is not a valid IP range", s)
+	// Each address within the range is stored
+	if r.Size().Cmp(big.NewInt(maxRangeAddrs)) > 0 {
+		return fmt.Errorf("The range %s has more than %d addresses", s, maxRangeAddrs)
 	}
-	return p.appendIPs(utils.RangeHosts(start, end))
+	return p.appendIPs(utils.RangeHosts(r.First, r.Last))
 }
 
 // parseCIDRs implementation of the flag.Value interface
@@ -0,0 +1,72 @@
+// Copyright 2017 Jeff Foley. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/OWASP/Amass/amass"
+	"github.com/OWASP/Amass/amass/utils"
+	"github.com/fatih/color"
+)
+
+// The status line that displays the progress on standard error
+var status = utils.NewStatusLine(color.Error)
+
+// printProgress renders the progress of the enumeration as a status line until the output has finished.
+func printProgress(enum *amass.Enumeration) {
+	t := time.NewTicker(utils.StatusInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-finished:
+			status.Clear()
+			return
+		case <-t.C:
+			status.Show(progressLine(enum.Progress()))
+		}
+	}
+}
+
+// printWithoutStatus removes the status line while the function prints output.
+func printWithoutStatus(print func()) {
+	status.Print(print)
+}
+
+func progressLine(p amass.Progress) string {
+	var parts []string
+
+	if p.BruteForce.Total > 0 {
+		parts = append(parts, phaseLine("Brute", p.BruteForce))
+	}
+	if p.Alterations.Total > 0 {
+		parts = append(parts, phaseLine("Alts", p.Alterations))
+	}
+	parts = append(parts, fmt.Sprintf("%s %s %s", blue("Resolved:"),
+		green(p.Resolved), yellow(fmt.Sprintf("(%.0f/s)", p.ResolvedRate))))
+	return strings.Join(parts, " | ")
+}
+
+func phaseLine(name string, p amass.PhaseProgress) string {
+	// The total is unknown while the words are being counted
+	total, pct := "?", "?"
+	if p.Total != math.MaxUint64 {
+		total = fmt.Sprintf("%d", p.Total)
+		pct = "0.0%"
+		if p.Total > 0 {
+			pct = fmt.Sprintf("%.1f%%", float64(p.Tried)/float64(p.Total)*100)
+		}
+	}
+
+	eta := "?"
+	if p.ETA > 0 {
+		eta = p.ETA.String()
+	}
+	return fmt.Sprintf("%s %s %s", blue(name+":"), green(fmt.Sprintf("%d/%s (%s)", p.Tried, total, pct)),
+		yellow(fmt.Sprintf("%.0f/s ETA %s queued %d", p.Rate, eta, p.Queued)))
+}